@@ -1,76 +1,340 @@
 package main
 
 import (
-	"encoding/json"
-	"fmt"
+	"context"
+	"crypto/rand"
+	stderrors "errors"
 	"log"
-	"strings"
+	"net/http"
+	"os/signal"
+	"sync"
+	"syscall"
 	"time"
 
-	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/domain/value_objects"
+	"github.com/gin-gonic/gin"
+	"github.com/williamkoller/multi-tenant-nexus-manager/cmd/internal/models"
+	"github.com/williamkoller/multi-tenant-nexus-manager/configs/database"
+	"github.com/williamkoller/multi-tenant-nexus-manager/configs/server"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/auth"
+	coredb "github.com/williamkoller/multi-tenant-nexus-manager/internal/core/database"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/events"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/mail"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/middleware"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/outbox"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/query"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/stream"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/validator"
+	application_tenant "github.com/williamkoller/multi-tenant-nexus-manager/internal/tenant/application"
+	domain_tenant "github.com/williamkoller/multi-tenant-nexus-manager/internal/tenant/domain"
+	infrastructure_tenant "github.com/williamkoller/multi-tenant-nexus-manager/internal/tenant/infrastructure"
+	application_user "github.com/williamkoller/multi-tenant-nexus-manager/internal/user/application"
 	domain_user "github.com/williamkoller/multi-tenant-nexus-manager/internal/user/domain"
+	handler_user "github.com/williamkoller/multi-tenant-nexus-manager/internal/user/handler"
+	infrastructure_user "github.com/williamkoller/multi-tenant-nexus-manager/internal/user/infrastructure"
+	application_webhook "github.com/williamkoller/multi-tenant-nexus-manager/internal/webhook/application"
+	handler_webhook "github.com/williamkoller/multi-tenant-nexus-manager/internal/webhook/handler"
+	infrastructure_webhook "github.com/williamkoller/multi-tenant-nexus-manager/internal/webhook/infrastructure"
 )
 
 func main() {
-	emailVO, err := value_objects.NewEmail("william@mail.com")
-	if err != nil {
+	if err := run(); err != nil {
 		log.Fatal(err)
 	}
+}
 
-	cpfVO, err := value_objects.NewCPF("06819091966")
+func run() error {
+	config, err := server.ConfigFromEnv()
 	if err != nil {
-		log.Fatal(err)
+		return err
+	}
+	gin.SetMode(config.GinMode)
+
+	// Sem o papel de sistema, relay e workers dependeriam de o papel da
+	// aplicação ignorar o RLS, o que anula o isolamento
+	if config.Database.SystemUser == "" {
+		if !config.IsDevelopment() {
+			return stderrors.New("DB_SYSTEM_USER is required outside development")
+		}
+		log.Printf("DB_SYSTEM_USER not set, cross-tenant jobs only work if the application role bypasses row level security")
 	}
 
-	phoneVO, err := value_objects.NewPhone("41998682343")
+	db, err := database.NewConnection(config.Database)
 	if err != nil {
-		log.Fatal(err)
+		return err
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
 	}
+	defer sqlDB.Close()
+
+	// Tenants com banco dedicado ganham pools próprios, abertos sob demanda
+	router := database.NewRouter(database.DedicatedConfigProvider{Base: config.Database}, database.DefaultRouterConfig())
+	if err := coredb.UseConnectionRouter(db, router); err != nil {
+		return err
+	}
+	defer router.Close()
+
+	// SIGTERM/SIGINT cancelam ctx e disparam o desligamento
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if config.AutoMigrate {
+		if err := coredb.Migrate(ctx, db, models.All()...); err != nil {
+			return err
+		}
+	}
+
+	registry := events.DefaultRegistry()
+	domain_tenant.RegisterEvents(registry)
+	domain_user.RegisterEvents(registry)
+
+	txManager := coredb.NewTxManager(db)
+	bus := events.NewInMemoryEventBus()
+	tenantLoader := infrastructure_tenant.NewLoader(db)
+	validate := validator.New()
+	cursors := query.NewCursorCodec([]byte(config.CursorSecret))
+
+	subscriptionSpec := infrastructure_webhook.SubscriptionSpec().WithCursors(cursors)
+	deliverySpec := infrastructure_webhook.DeliverySpec().WithCursors(cursors)
+	subscriptions := infrastructure_webhook.NewSubscriptionRepository(db, subscriptionSpec)
+	deliveries := infrastructure_webhook.NewDeliveryRepository(db, deliverySpec)
+	application_webhook.NewDispatcher(subscriptions, deliveries, txManager).Subscribe(bus)
+	webhookService := application_webhook.NewService(subscriptions, deliveries, txManager)
 
-	userD := domain_user.User{
-		Email: emailVO,
-		CPF:   cpfVO,
-		Phone: phoneVO,
+	eventOutbox := outbox.New(db, txManager)
+	tenantService := application_tenant.NewService(
+		infrastructure_tenant.NewRepository(db, infrastructure_tenant.Spec()),
+		txManager,
+		eventOutbox,
+		coredb.NewSchemaProvisioner(db, models.All()...),
+	)
+	if config.AutoMigrate {
+		if err := tenantService.MigrateIsolated(ctx); err != nil {
+			return err
+		}
 	}
 
-	user, err := domain_user.NewUser(&userD)
+	userSpec := infrastructure_user.Spec().WithCursors(cursors)
+	membershipSpec := infrastructure_user.MembershipSpec().WithCursors(cursors)
+	invitationSpec := infrastructure_user.InvitationSpec().WithCursors(cursors)
+	users := infrastructure_user.NewRepository(db, userSpec)
+	memberships := infrastructure_user.NewMembershipRepository(db, membershipSpec)
+	userService := application_user.NewService(users, memberships, tenantLoader, txManager, eventOutbox)
+
+	authService := application_user.NewAuthService(
+		users,
+		memberships,
+		infrastructure_user.NewCredentialRepository(db),
+		infrastructure_user.NewPasswordPolicyRepository(db),
+		tenantLoader,
+		txManager,
+		auth.NewPasswordHasher(auth.DefaultArgon2Params()),
+		application_user.DefaultAuthConfig(),
+	)
+
+	invitationConfig := application_user.DefaultInvitationConfig()
+	invitationConfig.TTL = config.InvitationTTL
+	if config.InvitationAcceptURL != "" {
+		invitationConfig.AcceptURL = config.InvitationAcceptURL
+	}
+	invitationConfig.TokenKey, err = invitationKey(config)
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
+	sender, err := mailSender(config)
+	if err != nil {
+		return err
+	}
+	invitationService := application_user.NewInvitationService(
+		infrastructure_user.NewInvitationRepository(db, invitationSpec),
+		users,
+		memberships,
+		authService,
+		tenantLoader,
+		txManager,
+		eventOutbox,
+		sender,
+		invitationConfig,
+	)
+	invitationService.Subscribe(bus)
 
-	user.Activate()
-
-	// Prints originais
-	fmt.Println("GetDomainEvents(): ", user.GetDomainEvents())
-	fmt.Println("ID: ", user.GetID())
-	fmt.Println("Email: ", user.Email)
-	fmt.Println("CPF: ", user.CPF.Formatted())
-	fmt.Println("Phone: ", user.Phone.Formatted())
-
-	fmt.Println("\n" + strings.Repeat("=", 50))
-	fmt.Println("JSON :")
-	fmt.Println(strings.Repeat("=", 50))
-
-	apiResponse := map[string]interface{}{
-		"success": true,
-		"message": "User created successfully",
-		"data": map[string]interface{}{
-			"user": map[string]interface{}{
-				"id":    user.GetID(),
-				"email": user.Email.String(),
-				"cpf":   user.CPF.Formatted(),
-				"phone": user.Phone.Formatted(),
-			},
-			"events": user.GetDomainEvents(),
+	keys, err := loadKeys(config)
+	if err != nil {
+		return err
+	}
+	tokenIssuer := auth.NewTokenIssuer(keys, auth.TokenConfig{
+		Issuer:   config.JWTIssuer,
+		Audience: config.JWTAudience,
+		TTL:      config.AccessTokenTTL,
+		Leeway:   auth.DefaultTokenConfig().Leeway,
+	})
+	tokenService := application_user.NewTokenService(
+		users,
+		memberships,
+		tenantLoader,
+		infrastructure_user.NewRefreshTokenRepository(db),
+		txManager,
+		tokenIssuer,
+		application_user.TokenConfig{RefreshTTL: config.RefreshTokenTTL},
+	)
+
+	eventStream := stream.New(db, txManager, stream.DefaultConfig())
+
+	workers := []worker{
+		outbox.NewRelay(db, bus, tenantLoader, outbox.DefaultRelayConfig()),
+		infrastructure_webhook.NewWorker(db, tenantLoader, nil, infrastructure_webhook.DefaultWorkerConfig()),
+		invitationService,
+		router,
+	}
+
+	tenantConfig := middleware.DefaultTenantConfig(tenantLoader)
+	tenantConfig.BaseDomain = config.BaseDomain
+	tenantConfig.ClaimResolver = middleware.PrincipalTenant
+	authMiddleware := middleware.AuthMiddleware(middleware.AuthConfig{
+		Issuer:   tokenIssuer,
+		Optional: !config.AuthRequired,
+	})
+
+	// O stream e os webhooks expõem todos os eventos do tenant
+	adminOnly := middleware.RequireRole(string(domain_user.RoleOwner), string(domain_user.RoleAdmin))
+	health := newHealth(sqlDB)
+	engine := newEngine(health, keys, []gin.HandlerFunc{authMiddleware, middleware.TenantMiddleware(tenantConfig)},
+		handler_user.NewHandler(userService, validate, userSpec, membershipSpec),
+		handler_user.NewInvitationHandler(invitationService, validate, invitationSpec),
+		handler_user.NewAuthHandler(authService, tokenService, validate),
+		restrictedModule{
+			module: handler_webhook.NewHandler(webhookService, validate, subscriptionSpec, deliverySpec),
+			guard:  adminOnly,
 		},
-		"timestamp": time.Now().Format(time.RFC3339),
+		restrictedModule{module: streamModule{stream: eventStream}, guard: adminOnly},
+	)
+
+	httpServer := &http.Server{
+		Addr:              config.Addr,
+		Handler:           engine,
+		ReadHeaderTimeout: config.ReadHeaderTimeout,
 	}
+	httpServer.RegisterOnShutdown(eventStream.Close)
 
-	apiJSON, err := json.MarshalIndent(apiResponse, "", "  ")
-	if err != nil {
-		log.Printf("Erro ao serializar API response: %v", err)
-	} else {
-		fmt.Println("Response Format (JSON):")
-		fmt.Println(string(apiJSON))
+	// Os workers têm contexto próprio para só pararem depois que o HTTP drenar
+	workerCtx, cancelWorkers := context.WithCancel(context.Background())
+	defer cancelWorkers()
+
+	var wg sync.WaitGroup
+	for _, w := range workers {
+		wg.Add(1)
+		go func(w worker) {
+			defer wg.Done()
+			w.Run(workerCtx)
+		}(w)
+	}
+
+	serverErr := make(chan error, 1)
+	go func() {
+		log.Printf("HTTP server listening on %s", config.Addr)
+		if err := httpServer.ListenAndServe(); err != nil && !stderrors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+		close(serverErr)
+	}()
+
+	select {
+	case err := <-serverErr:
+		if err != nil {
+			cancelWorkers()
+			wg.Wait()
+			return err
+		}
+	case <-ctx.Done():
+	}
+
+	log.Println("Shutting down")
+	health.markShuttingDown()
+
+	// /ready já falha, mas o balanceador só percebe na próxima checagem
+	if config.ShutdownDelay > 0 {
+		log.Printf("Waiting %s before closing the listener", config.ShutdownDelay)
+		time.Sleep(config.ShutdownDelay)
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), config.ShutdownTimeout)
+	defer cancel()
+
+	// Ordem: para de aceitar requisições, encerra os streams SSE e drena as
+	// demais em andamento, depois os workers terminam o lote corrente e por fim
+	// os handlers assíncronos do bus.
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		log.Printf("HTTP server shutdown: %v", err)
+	}
+
+	cancelWorkers()
+	drained := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(drained)
+	}()
+	select {
+	case <-drained:
+	case <-shutdownCtx.Done():
+		log.Println("Timed out waiting for background workers")
+	}
+
+	if err := bus.Close(shutdownCtx); err != nil {
+		log.Printf("Event bus shutdown: %v", err)
+	}
+
+	log.Println("Shutdown complete")
+	return nil
+}
+
+// loadKeys exige JWT_KEYS_DIR fora de desenvolvimento; sem ele, cada processo
+// gera a própria chave e tokens emitidos por outra instância não são aceitos.
+func loadKeys(config server.Config) (*auth.KeySet, error) {
+	if config.JWTKeysDir != "" {
+		return auth.LoadKeySet(config.JWTKeysDir, config.JWTActiveKeyID)
+	}
+	if !config.IsDevelopment() {
+		return nil, stderrors.New("JWT_KEYS_DIR is required outside development")
+	}
+	log.Printf("JWT_KEYS_DIR not set, generating an ephemeral signing key")
+	return auth.GenerateKeySet("ephemeral")
+}
+
+// mailSender só cai no LogSender em desenvolvimento: o log guardaria os links dos convites
+func mailSender(config server.Config) (mail.Sender, error) {
+	if config.SMTPAddr != "" {
+		return mail.NewSMTPSender(mail.SMTPConfig{
+			Addr:     config.SMTPAddr,
+			Username: config.SMTPUsername,
+			Password: config.SMTPPassword,
+			From:     config.MailFrom,
+		}), nil
+	}
+	if !config.IsDevelopment() {
+		return nil, stderrors.New("SMTP_ADDR is required outside development")
 	}
+	log.Printf("SMTP_ADDR not set, emails will only be logged")
+	return mail.LogSender{}, nil
+}
+
+// invitationKey gera uma chave por processo apenas em desenvolvimento; com
+// ela, convites enviados antes de um restart deixam de funcionar.
+func invitationKey(config server.Config) (domain_user.InvitationTokenKey, error) {
+	if config.InvitationSecret != "" {
+		return domain_user.InvitationTokenKey(config.InvitationSecret), nil
+	}
+	if !config.IsDevelopment() {
+		return nil, stderrors.New("INVITATION_SECRET is required outside development")
+	}
+	log.Printf("INVITATION_SECRET not set, generating an ephemeral invitation key")
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+type worker interface {
+	Run(ctx context.Context)
 }
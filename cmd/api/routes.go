@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/auth"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/middleware"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/stream"
+)
+
+// module é implementado pelos handlers de cada bounded context
+type module interface {
+	RegisterRoutes(router gin.IRouter)
+}
+
+// publicModule expõe rotas que não passam pelo TenantMiddleware
+type publicModule interface {
+	RegisterPublicRoutes(router gin.IRouter)
+}
+
+// restrictedModule registra todas as rotas do módulo atrás de guard
+type restrictedModule struct {
+	module
+	guard gin.HandlerFunc
+}
+
+func (m restrictedModule) RegisterRoutes(router gin.IRouter) {
+	m.module.RegisterRoutes(router.Group("", m.guard))
+}
+
+// newEngine aplica apiMiddlewares (autenticação e tenant, nessa ordem) às rotas de cada módulo
+func newEngine(health *health, keys *auth.KeySet, apiMiddlewares []gin.HandlerFunc, modules ...module) *gin.Engine {
+	engine := gin.New()
+	engine.Use(gin.Recovery(), middleware.LoggingMiddleware(), middleware.CORSMiddleware())
+
+	engine.GET("/healthz", health.live)
+	engine.GET("/readyz", health.ready)
+	engine.GET("/.well-known/jwks.json", jwks(keys))
+
+	public := engine.Group("/api/v1")
+	api := engine.Group("/api/v1", apiMiddlewares...)
+	for _, m := range modules {
+		if p, ok := m.(publicModule); ok {
+			p.RegisterPublicRoutes(public)
+		}
+		m.RegisterRoutes(api)
+	}
+	return engine
+}
+
+// jwks publica as chaves de verificação; o cache curto acompanha a rotação
+func jwks(keys *auth.KeySet) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Cache-Control", "public, max-age=300")
+		c.JSON(http.StatusOK, keys.JWKS())
+	}
+}
+
+type streamModule struct {
+	stream *stream.Stream
+}
+
+func (m streamModule) RegisterRoutes(router gin.IRouter) {
+	router.GET("/events/stream", m.stream.Handler())
+}
+
+type health struct {
+	db           *sql.DB
+	shuttingDown atomic.Bool
+}
+
+func newHealth(db *sql.DB) *health {
+	return &health{db: db}
+}
+
+func (h *health) markShuttingDown() {
+	h.shuttingDown.Store(true)
+}
+
+func (h *health) live(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// ready falha durante o desligamento para que o balanceador pare de enviar tráfego
+func (h *health) ready(c *gin.Context) {
+	if h.shuttingDown.Load() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "shutting_down"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 2*time.Second)
+	defer cancel()
+	if err := h.db.PingContext(ctx); err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "unavailable", "database": "unreachable"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
@@ -0,0 +1,24 @@
+// Package models reúne as tabelas de todos os contextos para os comandos que migram o banco
+package models
+
+import (
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/eventsourcing"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/outbox"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/projection"
+	infrastructure_tenant "github.com/williamkoller/multi-tenant-nexus-manager/internal/tenant/infrastructure"
+	infrastructure_user "github.com/williamkoller/multi-tenant-nexus-manager/internal/user/infrastructure"
+	infrastructure_webhook "github.com/williamkoller/multi-tenant-nexus-manager/internal/webhook/infrastructure"
+)
+
+func All() []interface{} {
+	models := []interface{}{
+		&outbox.Message{},
+		&eventsourcing.StoredEvent{},
+		&eventsourcing.Snapshot{},
+	}
+	models = append(models, infrastructure_tenant.Models()...)
+	models = append(models, infrastructure_user.Models()...)
+	models = append(models, infrastructure_webhook.Models()...)
+	models = append(models, projection.Models()...)
+	return models
+}
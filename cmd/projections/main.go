@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/williamkoller/multi-tenant-nexus-manager/configs/database"
+	coredb "github.com/williamkoller/multi-tenant-nexus-manager/internal/core/database"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/domain/value_objects"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/projection"
+	infrastructure_tenant "github.com/williamkoller/multi-tenant-nexus-manager/internal/tenant/infrastructure"
+)
+
+const dateLayout = "2006-01-02"
+
+// Reconstrói uma projeção a partir dos eventos armazenados:
+//
+//	go run ./cmd/projections -projection tenant_activity [-source outbox|event_store] [-tenant <id>] [-from 2025-01-01 -to 2025-01-31]
+//
+// Interromper o comando (Ctrl+C) preserva o checkpoint; rodar de novo com os
+// mesmos argumentos retoma a reconstrução.
+func main() {
+	var (
+		name      = flag.String("projection", "", "projection to rebuild")
+		source    = flag.String("source", projection.SourceOutbox, "event source: outbox or event_store")
+		tenantID  = flag.String("tenant", "", "rebuild a single tenant")
+		from      = flag.String("from", "", "first day (YYYY-MM-DD) of the events to replay")
+		to        = flag.String("to", "", "last day (YYYY-MM-DD) of the events to replay")
+		batchSize = flag.Int("batch", projection.DefaultConfig().BatchSize, "events per transaction")
+		list      = flag.Bool("list", false, "list available projections")
+	)
+	flag.Parse()
+
+	registry := projection.NewRegistry(
+		projection.NewActivityProjection(),
+	)
+
+	if *list {
+		names := registry.Names()
+		sort.Strings(names)
+		fmt.Println(strings.Join(names, "\n"))
+		return
+	}
+
+	target, err := registry.Get(*name)
+	if err != nil {
+		log.Fatalf("%v (use -list to see the available projections)", err)
+	}
+	eventSource, ok := projection.NewSource(*source)
+	if !ok {
+		log.Fatalf("unknown source %q", *source)
+	}
+	period, err := parsePeriod(*from, *to)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	config, err := database.ConfigFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+	db, err := database.NewConnection(config)
+	if err != nil {
+		log.Fatal(err)
+	}
+	router := database.NewRouter(database.DedicatedConfigProvider{Base: config}, database.DefaultRouterConfig())
+	if err := coredb.UseConnectionRouter(db, router); err != nil {
+		log.Fatal(err)
+	}
+	defer router.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := coredb.Migrate(ctx, db, projection.Models()...); err != nil {
+		log.Fatalf("failed to migrate projection tables: %v", err)
+	}
+
+	scope := projection.Scope{TenantID: *tenantID, Period: period}
+	rebuilder := projection.NewRebuilder(db, infrastructure_tenant.NewLoader(db), eventSource, projection.Config{BatchSize: *batchSize})
+
+	started := time.Now()
+	result, err := rebuilder.Rebuild(ctx, target, scope)
+	if err != nil {
+		log.Fatalf("projection %s stopped at position %d after %d events: %v", target.Name(), result.Position, result.Events, err)
+	}
+
+	log.Printf("projection %s rebuilt from %s: %d events up to position %d in %s (resumed: %t)",
+		target.Name(), eventSource.Name(), result.Events, result.Position, time.Since(started).Round(time.Millisecond), result.Resumed)
+}
+
+func parsePeriod(from, to string) (*value_objects.DateRange, error) {
+	if from == "" && to == "" {
+		return nil, nil
+	}
+	if from == "" || to == "" {
+		return nil, fmt.Errorf("-from and -to must be used together")
+	}
+
+	start, err := time.Parse(dateLayout, from)
+	if err != nil {
+		return nil, fmt.Errorf("invalid -from: %w", err)
+	}
+	end, err := time.Parse(dateLayout, to)
+	if err != nil {
+		return nil, fmt.Errorf("invalid -to: %w", err)
+	}
+
+	// O último dia entra inteiro no período
+	period, err := value_objects.NewDateRange(start, end.Add(24*time.Hour-time.Nanosecond))
+	if err != nil {
+		return nil, err
+	}
+	return &period, nil
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/williamkoller/multi-tenant-nexus-manager/cmd/internal/models"
+	"github.com/williamkoller/multi-tenant-nexus-manager/configs/database"
+	coredb "github.com/williamkoller/multi-tenant-nexus-manager/internal/core/database"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/events"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/outbox"
+	application_tenant "github.com/williamkoller/multi-tenant-nexus-manager/internal/tenant/application"
+	domain_tenant "github.com/williamkoller/multi-tenant-nexus-manager/internal/tenant/domain"
+	infrastructure_tenant "github.com/williamkoller/multi-tenant-nexus-manager/internal/tenant/infrastructure"
+)
+
+// Cria e provisiona tenants:
+//
+//	go run ./cmd/tenants -name Acme -slug acme -cnpj 11222333000181 [-color #0055FF] [-isolation schema]
+//	go run ./cmd/tenants -provision <id>
+//	go run ./cmd/tenants -migrate
+//
+// -provision retoma um tenant cujo provisionamento falhou; -migrate reaplica as
+// migrações nos schemas de todos os tenants isolados.
+func main() {
+	var (
+		name      = flag.String("name", "", "tenant name")
+		slug      = flag.String("slug", "", "tenant slug")
+		cnpj      = flag.String("cnpj", "", "tenant CNPJ")
+		color     = flag.String("color", "#000000", "primary color")
+		isolation = flag.String("isolation", "shared_table", "isolation strategy: shared_table, schema or database")
+		provision = flag.String("provision", "", "finish provisioning the tenant with this ID")
+		migrate   = flag.Bool("migrate", false, "migrate the schemas and databases of all isolated tenants")
+	)
+	flag.Parse()
+
+	config, err := database.ConfigFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+	db, err := database.NewConnection(config)
+	if err != nil {
+		log.Fatal(err)
+	}
+	router := database.NewRouter(database.DedicatedConfigProvider{Base: config}, database.DefaultRouterConfig())
+	if err := coredb.UseConnectionRouter(db, router); err != nil {
+		log.Fatal(err)
+	}
+	defer router.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	domain_tenant.RegisterEvents(events.DefaultRegistry())
+
+	txManager := coredb.NewTxManager(db)
+	service := application_tenant.NewService(
+		infrastructure_tenant.NewRepository(db, infrastructure_tenant.Spec()),
+		txManager,
+		outbox.New(db, txManager),
+		coredb.NewSchemaProvisioner(db, models.All()...),
+	)
+
+	switch {
+	case *migrate:
+		if err := service.MigrateIsolated(ctx); err != nil {
+			log.Fatal(err)
+		}
+		log.Println("isolated tenants migrated")
+	case *provision != "":
+		tenant, err := service.Provision(ctx, *provision)
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("tenant %s (%s) is %s", tenant.GetID(), tenant.GetSlug(), tenant.Status)
+	default:
+		tenant, err := service.Create(ctx, application_tenant.CreateTenantInput{
+			Name:         *name,
+			Slug:         *slug,
+			CNPJ:         *cnpj,
+			PrimaryColor: *color,
+			Isolation:    *isolation,
+		})
+		if tenant != nil && err != nil {
+			log.Fatalf("tenant %s was created but provisioning failed (retry with -provision %s): %v", tenant.GetID(), tenant.GetID(), err)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("tenant %s (%s) is %s", tenant.GetID(), tenant.GetSlug(), tenant.Status)
+	}
+}
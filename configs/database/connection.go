@@ -3,7 +3,9 @@ package database
 import (
 	"fmt"
 	"log"
+	"strings"
 
+	coredb "github.com/williamkoller/multi-tenant-nexus-manager/internal/core/database"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
@@ -16,12 +18,20 @@ type Config struct {
 	Password string
 	DBName   string
 	SSLMode  string
+
+	MaxOpenConns int
+	MaxIdleConns int
+
+	// SystemUser abre a conexão dos jobs que atravessam tenants; o papel precisa
+	// de BYPASSRLS. Vazio mantém esses jobs na conexão da aplicação.
+	SystemUser     string
+	SystemPassword string
 }
 
 func NewConnection(config Config) (*gorm.DB, error) {
 	dsn := fmt.Sprintf(
 		"host=%s user=%s password=%s dbname=%s port=%d sslmode=%s TimeZone=UTC",
-		config.Host, config.User, config.Password, config.DBName, config.Port, config.SSLMode,
+		dsnValue(config.Host), dsnValue(config.User), dsnValue(config.Password), dsnValue(config.DBName), config.Port, dsnValue(config.SSLMode),
 	)
 
 	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
@@ -32,14 +42,43 @@ func NewConnection(config Config) (*gorm.DB, error) {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
+	if err := db.Use(coredb.NewTenantPlugin()); err != nil {
+		return nil, fmt.Errorf("failed to register tenant plugin: %w", err)
+	}
+
 	sqlDB, err := db.DB()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get underlying sql.DB: %w", err)
 	}
 
-	sqlDB.SetMaxIdleConns(10)
-	sqlDB.SetMaxOpenConns(100)
+	maxIdleConns, maxOpenConns := config.MaxIdleConns, config.MaxOpenConns
+	if maxIdleConns <= 0 {
+		maxIdleConns = 10
+	}
+	if maxOpenConns <= 0 {
+		maxOpenConns = 100
+	}
+	sqlDB.SetMaxIdleConns(maxIdleConns)
+	sqlDB.SetMaxOpenConns(maxOpenConns)
+
+	if config.SystemUser != "" {
+		systemConfig := config
+		systemConfig.User, systemConfig.Password = config.SystemUser, config.SystemPassword
+		systemConfig.SystemUser, systemConfig.SystemPassword = "", ""
+		systemDB, err := NewConnection(systemConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open system connection: %w", err)
+		}
+		if err := db.Use(coredb.NewSystemConnection(systemDB)); err != nil {
+			return nil, fmt.Errorf("failed to register system connection: %w", err)
+		}
+	}
 
 	log.Println("Database connected successfully")
 	return db, nil
 }
+
+// dsnValue cita o valor para que senhas vazias ou com espaços não quebrem o DSN
+func dsnValue(value string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value) + "'"
+}
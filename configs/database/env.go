@@ -0,0 +1,58 @@
+package database
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// ConfigFromEnv lê DB_HOST, DB_PORT, DB_USER, DB_PASSWORD, DB_NAME, DB_SSLMODE,
+// DB_MAX_OPEN_CONNS, DB_MAX_IDLE_CONNS, DB_SYSTEM_USER e DB_SYSTEM_PASSWORD,
+// com padrões para desenvolvimento local.
+func ConfigFromEnv() (Config, error) {
+	port, err := intFromEnv("DB_PORT", 5432)
+	if err != nil {
+		return Config{}, err
+	}
+	maxOpenConns, err := intFromEnv("DB_MAX_OPEN_CONNS", 0)
+	if err != nil {
+		return Config{}, err
+	}
+	maxIdleConns, err := intFromEnv("DB_MAX_IDLE_CONNS", 0)
+	if err != nil {
+		return Config{}, err
+	}
+
+	return Config{
+		Host:         stringFromEnv("DB_HOST", "localhost"),
+		Port:         port,
+		User:         stringFromEnv("DB_USER", "postgres"),
+		Password:     os.Getenv("DB_PASSWORD"),
+		DBName:       stringFromEnv("DB_NAME", "nexus_manager"),
+		SSLMode:      stringFromEnv("DB_SSLMODE", "disable"),
+		MaxOpenConns: maxOpenConns,
+		MaxIdleConns: maxIdleConns,
+
+		SystemUser:     os.Getenv("DB_SYSTEM_USER"),
+		SystemPassword: os.Getenv("DB_SYSTEM_PASSWORD"),
+	}, nil
+}
+
+func stringFromEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func intFromEnv(key string, fallback int) (int, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be a number: %w", key, err)
+	}
+	return parsed, nil
+}
@@ -0,0 +1,270 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	coredb "github.com/williamkoller/multi-tenant-nexus-manager/internal/core/database"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/tenancy"
+	"gorm.io/gorm"
+)
+
+type ConfigProvider interface {
+	ConfigForTenant(ctx context.Context, tenantID string) (Config, error)
+}
+
+type StaticConfigProvider map[string]Config
+
+func (p StaticConfigProvider) ConfigForTenant(ctx context.Context, tenantID string) (Config, error) {
+	config, ok := p[tenantID]
+	if !ok {
+		return Config{}, fmt.Errorf("no database configured for tenant %s", tenantID)
+	}
+	return config, nil
+}
+
+// DedicatedConfigProvider deriva o banco de cada tenant do banco padrão: mesmo
+// servidor e credenciais, com o nome <DB_NAME>_<id do tenant>. A conexão de
+// sistema não é repetida para não escapar do limite de conexões do Router.
+type DedicatedConfigProvider struct {
+	Base Config
+}
+
+func (p DedicatedConfigProvider) ConfigForTenant(ctx context.Context, tenantID string) (Config, error) {
+	name := strings.ToLower(p.Base.DBName + "_" + strings.ReplaceAll(tenantID, "-", "_"))
+	if !tenancy.IsValidSchemaName(name) {
+		return Config{}, fmt.Errorf("invalid database name for tenant %s: %q", tenantID, name)
+	}
+
+	config := p.Base
+	config.DBName = name
+	config.SystemUser, config.SystemPassword = "", ""
+	return config, nil
+}
+
+type RouterConfig struct {
+	MaxOpenConnsPerTenant int
+	MaxIdleConnsPerTenant int
+	// MaxTotalOpenConns limita a soma de conexões abertas por todos os pools de tenants
+	MaxTotalOpenConns int
+	IdleTimeout       time.Duration
+}
+
+func DefaultRouterConfig() RouterConfig {
+	return RouterConfig{
+		MaxOpenConnsPerTenant: 10,
+		MaxIdleConnsPerTenant: 2,
+		MaxTotalOpenConns:     200,
+		IdleTimeout:           10 * time.Minute,
+	}
+}
+
+type tenantPool struct {
+	db       *gorm.DB
+	sqlDB    *sql.DB
+	maxOpen  int
+	lastUsed time.Time
+}
+
+// Router mapeia tenants com banco dedicado para pools próprios, abertos sob
+// demanda e fechados quando ociosos.
+type Router struct {
+	provider ConfigProvider
+	config   RouterConfig
+	open     func(Config) (*gorm.DB, error)
+
+	mu        sync.Mutex
+	pools     map[string]*tenantPool
+	totalOpen int
+}
+
+var _ coredb.ConnectionRouter = (*Router)(nil)
+
+func NewRouter(provider ConfigProvider, config RouterConfig) *Router {
+	defaults := DefaultRouterConfig()
+	if config.MaxOpenConnsPerTenant <= 0 {
+		config.MaxOpenConnsPerTenant = defaults.MaxOpenConnsPerTenant
+	}
+	if config.MaxIdleConnsPerTenant <= 0 {
+		config.MaxIdleConnsPerTenant = defaults.MaxIdleConnsPerTenant
+	}
+	if config.MaxTotalOpenConns <= 0 {
+		config.MaxTotalOpenConns = defaults.MaxTotalOpenConns
+	}
+	if config.IdleTimeout <= 0 {
+		config.IdleTimeout = defaults.IdleTimeout
+	}
+
+	return &Router{
+		provider: provider,
+		config:   config,
+		open:     NewConnection,
+		pools:    make(map[string]*tenantPool),
+	}
+}
+
+func (r *Router) Name() string {
+	return coredb.ConnectionRouterName
+}
+
+func (r *Router) Initialize(db *gorm.DB) error {
+	return coredb.RegisterConnectionRouting(db, r)
+}
+
+func (r *Router) ForTenant(ctx context.Context, tenantID string) (*gorm.DB, error) {
+	if db, ok := r.cached(tenantID); ok {
+		return db, nil
+	}
+
+	config, err := r.provider.ConfigForTenant(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if config.MaxOpenConns <= 0 || config.MaxOpenConns > r.config.MaxOpenConnsPerTenant {
+		config.MaxOpenConns = r.config.MaxOpenConnsPerTenant
+	}
+	if config.MaxIdleConns <= 0 || config.MaxIdleConns > config.MaxOpenConns {
+		config.MaxIdleConns = min(r.config.MaxIdleConnsPerTenant, config.MaxOpenConns)
+	}
+
+	if err := r.reserve(config.MaxOpenConns); err != nil {
+		return nil, err
+	}
+
+	db, err := r.open(config)
+	if err != nil {
+		r.release(config.MaxOpenConns)
+		return nil, err
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		r.release(config.MaxOpenConns)
+		return nil, err
+	}
+	sqlDB.SetConnMaxIdleTime(r.config.IdleTimeout)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Outra goroutine pode ter aberto o pool enquanto conectávamos
+	if existing, ok := r.pools[tenantID]; ok {
+		r.totalOpen -= config.MaxOpenConns
+		sqlDB.Close()
+		existing.lastUsed = time.Now()
+		return existing.db, nil
+	}
+
+	r.pools[tenantID] = &tenantPool{
+		db:       db,
+		sqlDB:    sqlDB,
+		maxOpen:  config.MaxOpenConns,
+		lastUsed: time.Now(),
+	}
+	return db, nil
+}
+
+func (r *Router) cached(tenantID string) (*gorm.DB, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	pool, ok := r.pools[tenantID]
+	if !ok {
+		return nil, false
+	}
+	pool.lastUsed = time.Now()
+	return pool.db, true
+}
+
+// reserve garante espaço no limite global, fechando pools ociosos se necessário
+func (r *Router) reserve(conns int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for r.totalOpen+conns > r.config.MaxTotalOpenConns {
+		if !r.evictLeastRecentlyUsedLocked() {
+			return fmt.Errorf("tenant connection limit reached: %d of %d connections in use", r.totalOpen, r.config.MaxTotalOpenConns)
+		}
+	}
+	r.totalOpen += conns
+	return nil
+}
+
+func (r *Router) release(conns int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.totalOpen -= conns
+}
+
+func (r *Router) evictLeastRecentlyUsedLocked() bool {
+	var victimID string
+	var victim *tenantPool
+	for tenantID, pool := range r.pools {
+		if pool.sqlDB.Stats().InUse > 0 {
+			continue
+		}
+		if victim == nil || pool.lastUsed.Before(victim.lastUsed) {
+			victimID, victim = tenantID, pool
+		}
+	}
+	if victim == nil {
+		return false
+	}
+	r.closeLocked(victimID, victim)
+	return true
+}
+
+func (r *Router) closeLocked(tenantID string, pool *tenantPool) {
+	delete(r.pools, tenantID)
+	r.totalOpen -= pool.maxOpen
+	if err := pool.sqlDB.Close(); err != nil {
+		log.Printf("failed to close database pool for tenant %s: %v", tenantID, err)
+	}
+}
+
+func (r *Router) EvictIdle() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	evicted := 0
+	deadline := time.Now().Add(-r.config.IdleTimeout)
+	for tenantID, pool := range r.pools {
+		if pool.lastUsed.After(deadline) || pool.sqlDB.Stats().InUse > 0 {
+			continue
+		}
+		r.closeLocked(tenantID, pool)
+		evicted++
+	}
+	return evicted
+}
+
+// Run remove periodicamente os pools ociosos até o contexto ser cancelado
+func (r *Router) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.config.IdleTimeout / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if evicted := r.EvictIdle(); evicted > 0 {
+				log.Printf("Evicted %d idle tenant database pools", evicted)
+			}
+		}
+	}
+}
+
+func (r *Router) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for tenantID, pool := range r.pools {
+		r.closeLocked(tenantID, pool)
+	}
+	return nil
+}
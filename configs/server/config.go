@@ -0,0 +1,161 @@
+package server
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/williamkoller/multi-tenant-nexus-manager/configs/database"
+)
+
+// EnvironmentDevelopment libera os atalhos de desenvolvimento (e-mails no log,
+// segredos gerados a cada início); qualquer outro valor exige a configuração completa
+const EnvironmentDevelopment = "development"
+
+type Config struct {
+	Environment       string
+	Addr              string
+	GinMode           string
+	ReadHeaderTimeout time.Duration
+	ShutdownTimeout   time.Duration
+	// ShutdownDelay mantém o servidor atendendo depois que /ready passa a falhar,
+	// para o balanceador tirar a instância antes de as conexões serem recusadas
+	ShutdownDelay time.Duration
+	// BaseDomain habilita a resolução do tenant por subdomínio (acme.<BaseDomain>)
+	BaseDomain string
+	// CursorSecret assina os cursores de paginação; vazio desabilita a paginação por cursor
+	CursorSecret string
+	AutoMigrate  bool
+	// InvitationTTL e InvitationAcceptURL definem validade e link dos convites
+	InvitationTTL       time.Duration
+	InvitationAcceptURL string
+	// InvitationSecret deriva os tokens dos convites a partir do nonce do evento
+	InvitationSecret string
+	// SMTPAddr vazio mantém os e-mails apenas no log (só em desenvolvimento)
+	SMTPAddr     string
+	SMTPUsername string
+	SMTPPassword string
+	MailFrom     string
+	// JWTKeysDir contém as chaves RSA <kid>.pem; vazio só é aceito em desenvolvimento
+	JWTKeysDir      string
+	JWTActiveKeyID  string
+	JWTIssuer       string
+	JWTAudience     string
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+	// AuthRequired falso aceita requisições sem token (apenas desenvolvimento)
+	AuthRequired bool
+	Database     database.Config
+}
+
+// ConfigFromEnv lê APP_ENV, HTTP_ADDR, GIN_MODE, READ_HEADER_TIMEOUT,
+// SHUTDOWN_TIMEOUT, SHUTDOWN_DELAY, TENANT_BASE_DOMAIN, CURSOR_SECRET,
+// AUTO_MIGRATE, INVITATION_TTL, INVITATION_ACCEPT_URL, INVITATION_SECRET,
+// SMTP_ADDR, SMTP_USERNAME, SMTP_PASSWORD, MAIL_FROM, JWT_KEYS_DIR,
+// JWT_ACTIVE_KID, JWT_ISSUER, JWT_AUDIENCE, JWT_ACCESS_TTL, JWT_REFRESH_TTL,
+// AUTH_REQUIRED e as variáveis DB_*.
+func ConfigFromEnv() (Config, error) {
+	dbConfig, err := database.ConfigFromEnv()
+	if err != nil {
+		return Config{}, err
+	}
+	readHeaderTimeout, err := durationFromEnv("READ_HEADER_TIMEOUT", 10*time.Second)
+	if err != nil {
+		return Config{}, err
+	}
+	shutdownTimeout, err := durationFromEnv("SHUTDOWN_TIMEOUT", 30*time.Second)
+	if err != nil {
+		return Config{}, err
+	}
+	shutdownDelay, err := durationFromEnv("SHUTDOWN_DELAY", 5*time.Second)
+	if err != nil {
+		return Config{}, err
+	}
+	autoMigrate, err := boolFromEnv("AUTO_MIGRATE", false)
+	if err != nil {
+		return Config{}, err
+	}
+	invitationTTL, err := durationFromEnv("INVITATION_TTL", 7*24*time.Hour)
+	if err != nil {
+		return Config{}, err
+	}
+	accessTokenTTL, err := durationFromEnv("JWT_ACCESS_TTL", 15*time.Minute)
+	if err != nil {
+		return Config{}, err
+	}
+	refreshTokenTTL, err := durationFromEnv("JWT_REFRESH_TTL", 30*24*time.Hour)
+	if err != nil {
+		return Config{}, err
+	}
+	environment := stringFromEnv("APP_ENV", "production")
+	authRequired, err := boolFromEnv("AUTH_REQUIRED", true)
+	if err != nil {
+		return Config{}, err
+	}
+	if !authRequired && environment != EnvironmentDevelopment {
+		return Config{}, fmt.Errorf("AUTH_REQUIRED=false is only allowed with APP_ENV=%s", EnvironmentDevelopment)
+	}
+
+	return Config{
+		Environment:         environment,
+		Addr:                stringFromEnv("HTTP_ADDR", ":8080"),
+		GinMode:             stringFromEnv("GIN_MODE", "release"),
+		ReadHeaderTimeout:   readHeaderTimeout,
+		ShutdownTimeout:     shutdownTimeout,
+		ShutdownDelay:       shutdownDelay,
+		BaseDomain:          os.Getenv("TENANT_BASE_DOMAIN"),
+		CursorSecret:        os.Getenv("CURSOR_SECRET"),
+		AutoMigrate:         autoMigrate,
+		InvitationTTL:       invitationTTL,
+		InvitationAcceptURL: os.Getenv("INVITATION_ACCEPT_URL"),
+		InvitationSecret:    os.Getenv("INVITATION_SECRET"),
+		SMTPAddr:            os.Getenv("SMTP_ADDR"),
+		SMTPUsername:        os.Getenv("SMTP_USERNAME"),
+		SMTPPassword:        os.Getenv("SMTP_PASSWORD"),
+		MailFrom:            stringFromEnv("MAIL_FROM", "no-reply@localhost"),
+		JWTKeysDir:          os.Getenv("JWT_KEYS_DIR"),
+		JWTActiveKeyID:      os.Getenv("JWT_ACTIVE_KID"),
+		JWTIssuer:           stringFromEnv("JWT_ISSUER", "nexus-manager"),
+		JWTAudience:         stringFromEnv("JWT_AUDIENCE", "nexus-api"),
+		AccessTokenTTL:      accessTokenTTL,
+		RefreshTokenTTL:     refreshTokenTTL,
+		AuthRequired:        authRequired,
+		Database:            dbConfig,
+	}, nil
+}
+
+func (c Config) IsDevelopment() bool {
+	return c.Environment == EnvironmentDevelopment
+}
+
+func stringFromEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func durationFromEnv(key string, fallback time.Duration) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be a duration: %w", key, err)
+	}
+	return parsed, nil
+}
+
+func boolFromEnv(key string, fallback bool) (bool, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("%s must be a boolean: %w", key, err)
+	}
+	return parsed, nil
+}
@@ -0,0 +1,25 @@
+package server
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConfigFromEnvRejectsOptionalAuthOutsideDevelopment(t *testing.T) {
+	t.Setenv("AUTH_REQUIRED", "false")
+	t.Setenv("APP_ENV", "production")
+
+	_, err := ConfigFromEnv()
+	if err == nil || !strings.Contains(err.Error(), "AUTH_REQUIRED") {
+		t.Fatalf("ConfigFromEnv returned %v, want an AUTH_REQUIRED error", err)
+	}
+
+	t.Setenv("APP_ENV", EnvironmentDevelopment)
+	config, err := ConfigFromEnv()
+	if err != nil {
+		t.Fatalf("ConfigFromEnv in development returned %v", err)
+	}
+	if config.AuthRequired {
+		t.Fatal("AuthRequired = true, want false")
+	}
+}
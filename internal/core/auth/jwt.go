@@ -0,0 +1,195 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/domain"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/errors"
+)
+
+const algorithmRS256 = "RS256"
+
+// Claims segue os nomes registrados do RFC 7519 e acrescenta o contexto do tenant
+type Claims struct {
+	Issuer       string   `json:"iss"`
+	Subject      string   `json:"sub"`
+	Audience     string   `json:"aud"`
+	ExpiresAt    int64    `json:"exp"`
+	NotBefore    int64    `json:"nbf"`
+	IssuedAt     int64    `json:"iat"`
+	ID           string   `json:"jti"`
+	TenantID     string   `json:"tenant_id"`
+	MembershipID string   `json:"membership_id"`
+	Roles        []string `json:"roles"`
+}
+
+type header struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ"`
+	Kid string `json:"kid"`
+}
+
+type TokenConfig struct {
+	Issuer   string
+	Audience string
+	TTL      time.Duration
+	// Leeway tolera diferenças de relógio entre as instâncias
+	Leeway time.Duration
+}
+
+func DefaultTokenConfig() TokenConfig {
+	return TokenConfig{
+		Issuer:   "nexus-manager",
+		Audience: "nexus-api",
+		TTL:      15 * time.Minute,
+		Leeway:   30 * time.Second,
+	}
+}
+
+var ErrInvalidToken = errors.NewAppErrorWithDetails(
+	errors.ErrUnauthorized.Code,
+	errors.ErrUnauthorized.Message,
+	"invalid or expired access token",
+)
+
+// TokenIssuer assina e valida access tokens RS256 sem dependências externas
+type TokenIssuer struct {
+	keys   *KeySet
+	config TokenConfig
+}
+
+func NewTokenIssuer(keys *KeySet, config TokenConfig) *TokenIssuer {
+	defaults := DefaultTokenConfig()
+	if config.Issuer == "" {
+		config.Issuer = defaults.Issuer
+	}
+	if config.Audience == "" {
+		config.Audience = defaults.Audience
+	}
+	if config.TTL <= 0 {
+		config.TTL = defaults.TTL
+	}
+	if config.Leeway < 0 {
+		config.Leeway = 0
+	}
+	return &TokenIssuer{keys: keys, config: config}
+}
+
+func (i *TokenIssuer) Keys() *KeySet {
+	return i.keys
+}
+
+func (i *TokenIssuer) TTL() time.Duration {
+	return i.config.TTL
+}
+
+// Issue preenche iss, aud, iat, nbf, exp e jti e assina com a chave ativa
+func (i *TokenIssuer) Issue(principal Principal) (string, Claims, error) {
+	now := time.Now().UTC()
+	claims := Claims{
+		Issuer:       i.config.Issuer,
+		Subject:      principal.UserID,
+		Audience:     i.config.Audience,
+		IssuedAt:     now.Unix(),
+		NotBefore:    now.Unix(),
+		ExpiresAt:    now.Add(i.config.TTL).Unix(),
+		ID:           domain.GetGenerator().Generate(),
+		TenantID:     principal.TenantID,
+		MembershipID: principal.MembershipID,
+		Roles:        principal.Roles,
+	}
+
+	token, err := i.sign(claims)
+	if err != nil {
+		return "", Claims{}, err
+	}
+	return token, claims, nil
+}
+
+// Verify aceita apenas RS256 com kid conhecido, evitando a troca de algoritmo
+func (i *TokenIssuer) Verify(token string) (Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return Claims{}, ErrInvalidToken
+	}
+
+	var h header
+	if err := decodeSegment(parts[0], &h); err != nil || h.Alg != algorithmRS256 {
+		return Claims{}, ErrInvalidToken
+	}
+	publicKey, ok := i.keys.PublicKey(h.Kid)
+	if !ok {
+		return Claims{}, ErrInvalidToken
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return Claims{}, ErrInvalidToken
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(publicKey, crypto.SHA256, digest[:], signature); err != nil {
+		return Claims{}, ErrInvalidToken
+	}
+
+	var claims Claims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return Claims{}, ErrInvalidToken
+	}
+
+	now := time.Now().UTC()
+	leeway := int64(i.config.Leeway / time.Second)
+	switch {
+	case claims.Issuer != i.config.Issuer, claims.Audience != i.config.Audience:
+		return Claims{}, ErrInvalidToken
+	case claims.ExpiresAt == 0 || now.Unix() > claims.ExpiresAt+leeway:
+		return Claims{}, ErrInvalidToken
+	case claims.NotBefore != 0 && now.Unix() < claims.NotBefore-leeway:
+		return Claims{}, ErrInvalidToken
+	case claims.Subject == "":
+		return Claims{}, ErrInvalidToken
+	}
+	return claims, nil
+}
+
+func (i *TokenIssuer) sign(claims Claims) (string, error) {
+	headerSegment, err := encodeSegment(header{Alg: algorithmRS256, Typ: "JWT", Kid: i.keys.signer.ID})
+	if err != nil {
+		return "", err
+	}
+	claimsSegment, err := encodeSegment(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := headerSegment + "." + claimsSegment
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, i.keys.signer.PrivateKey, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("auth: failed to sign token: %w", err)
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+func encodeSegment(value interface{}) (string, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeSegment(segment string, target interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, target)
+}
@@ -0,0 +1,177 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"testing"
+	"time"
+)
+
+func TestTokenIssuerVerify(t *testing.T) {
+	keys, err := GenerateKeySet("active")
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := GenerateKeySet("other")
+	if err != nil {
+		t.Fatal(err)
+	}
+	issuer := NewTokenIssuer(keys, TokenConfig{Issuer: "nexus", Audience: "api", TTL: time.Minute, Leeway: time.Second})
+
+	now := time.Now().Unix()
+	valid := Claims{Issuer: "nexus", Subject: "user-1", Audience: "api", IssuedAt: now, NotBefore: now, ExpiresAt: now + 60}
+	withClaims := func(change func(claims *Claims)) Claims {
+		claims := valid
+		change(&claims)
+		return claims
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{
+			name:  "valid",
+			token: signToken(t, keys.signer, header{Alg: algorithmRS256, Typ: "JWT", Kid: "active"}, valid),
+		},
+		{
+			name:    "alg none",
+			token:   unsignedToken(t, header{Alg: "none", Typ: "JWT", Kid: "active"}, valid),
+			wantErr: true,
+		},
+		{
+			name:    "alg HS256 over an RS256 signature",
+			token:   signToken(t, keys.signer, header{Alg: "HS256", Typ: "JWT", Kid: "active"}, valid),
+			wantErr: true,
+		},
+		{
+			name:    "unknown kid",
+			token:   signToken(t, other.signer, header{Alg: algorithmRS256, Typ: "JWT", Kid: "other"}, valid),
+			wantErr: true,
+		},
+		{
+			name:    "known kid signed by another key",
+			token:   signToken(t, other.signer, header{Alg: algorithmRS256, Typ: "JWT", Kid: "active"}, valid),
+			wantErr: true,
+		},
+		{
+			name:    "expired beyond leeway",
+			token:   signToken(t, keys.signer, header{Alg: algorithmRS256, Typ: "JWT", Kid: "active"}, withClaims(func(c *Claims) { c.ExpiresAt = now - 10 })),
+			wantErr: true,
+		},
+		{
+			name:    "missing exp",
+			token:   signToken(t, keys.signer, header{Alg: algorithmRS256, Typ: "JWT", Kid: "active"}, withClaims(func(c *Claims) { c.ExpiresAt = 0 })),
+			wantErr: true,
+		},
+		{
+			name:    "not yet valid",
+			token:   signToken(t, keys.signer, header{Alg: algorithmRS256, Typ: "JWT", Kid: "active"}, withClaims(func(c *Claims) { c.NotBefore = now + 60 })),
+			wantErr: true,
+		},
+		{
+			name:    "wrong audience",
+			token:   signToken(t, keys.signer, header{Alg: algorithmRS256, Typ: "JWT", Kid: "active"}, withClaims(func(c *Claims) { c.Audience = "other-api" })),
+			wantErr: true,
+		},
+		{
+			name:    "wrong issuer",
+			token:   signToken(t, keys.signer, header{Alg: algorithmRS256, Typ: "JWT", Kid: "active"}, withClaims(func(c *Claims) { c.Issuer = "other" })),
+			wantErr: true,
+		},
+		{
+			name:    "malformed",
+			token:   "not-a-token",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := issuer.Verify(tt.token)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Verify accepted the token with claims %+v", claims)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Verify returned %v", err)
+			}
+			if claims.Subject != valid.Subject {
+				t.Fatalf("subject = %q, want %q", claims.Subject, valid.Subject)
+			}
+		})
+	}
+}
+
+func TestTokenIssuerVerifyIssued(t *testing.T) {
+	keys, err := GenerateKeySet("active")
+	if err != nil {
+		t.Fatal(err)
+	}
+	issuer := NewTokenIssuer(keys, DefaultTokenConfig())
+
+	token, issued, err := issuer.Issue(Principal{UserID: "user-1", TenantID: "tenant-1", Roles: []string{"admin"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err := issuer.Verify(token)
+	if err != nil {
+		t.Fatalf("Verify returned %v", err)
+	}
+	if claims.ID != issued.ID || claims.TenantID != "tenant-1" {
+		t.Fatalf("claims = %+v, want %+v", claims, issued)
+	}
+
+	// Um token assinado pela chave anterior continua válido depois da rotação
+	rotated, err := NewKeySet(SigningKey{ID: "next", PrivateKey: mustGenerateKey(t)}, *keys.signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewTokenIssuer(rotated, DefaultTokenConfig()).Verify(token); err != nil {
+		t.Fatalf("Verify after rotation returned %v", err)
+	}
+}
+
+func signToken(t *testing.T, key *SigningKey, h header, claims Claims) string {
+	t.Helper()
+	signingInput := segments(t, h, claims)
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key.PrivateKey, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func unsignedToken(t *testing.T, h header, claims Claims) string {
+	t.Helper()
+	return segments(t, h, claims) + "."
+}
+
+func segments(t *testing.T, h header, claims Claims) string {
+	t.Helper()
+	headerSegment, err := encodeSegment(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	claimsSegment, err := encodeSegment(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return headerSegment + "." + claimsSegment
+}
+
+func mustGenerateKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, keyBits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
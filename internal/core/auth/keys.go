@@ -0,0 +1,161 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const keyBits = 2048
+
+type SigningKey struct {
+	ID         string
+	PrivateKey *rsa.PrivateKey
+}
+
+// KeySet assina com a chave ativa e continua aceitando as chaves anteriores
+// enquanto estiverem publicadas; para rotacionar, adicione a nova chave, torne-a
+// ativa e remova a antiga depois que os tokens emitidos com ela expirarem.
+type KeySet struct {
+	active string
+	keys   map[string]*rsa.PublicKey
+	signer *SigningKey
+}
+
+func NewKeySet(active SigningKey, previous ...SigningKey) (*KeySet, error) {
+	if active.ID == "" || active.PrivateKey == nil {
+		return nil, fmt.Errorf("auth: active signing key requires an id and a private key")
+	}
+
+	set := &KeySet{
+		active: active.ID,
+		keys:   map[string]*rsa.PublicKey{active.ID: &active.PrivateKey.PublicKey},
+		signer: &active,
+	}
+	for _, key := range previous {
+		if _, exists := set.keys[key.ID]; exists {
+			return nil, fmt.Errorf("auth: duplicated key id %q", key.ID)
+		}
+		set.keys[key.ID] = &key.PrivateKey.PublicKey
+	}
+	return set, nil
+}
+
+// GenerateKeySet cria uma chave efêmera; tokens deixam de valer quando o processo reinicia
+func GenerateKeySet(kid string) (*KeySet, error) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, keyBits)
+	if err != nil {
+		return nil, err
+	}
+	return NewKeySet(SigningKey{ID: kid, PrivateKey: privateKey})
+}
+
+// LoadKeySet lê arquivos <kid>.pem do diretório; activeID escolhe a chave que assina
+func LoadKeySet(dir, activeID string) (*KeySet, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	var active *SigningKey
+	var previous []SigningKey
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		privateKey, err := ParsePrivateKey(data)
+		if err != nil {
+			return nil, fmt.Errorf("auth: %s: %w", path, err)
+		}
+
+		key := SigningKey{ID: strings.TrimSuffix(filepath.Base(path), ".pem"), PrivateKey: privateKey}
+		if key.ID == activeID {
+			active = &key
+			continue
+		}
+		previous = append(previous, key)
+	}
+	if active == nil {
+		return nil, fmt.Errorf("auth: active key %q not found in %s", activeID, dir)
+	}
+	return NewKeySet(*active, previous...)
+}
+
+// ParsePrivateKey aceita chaves RSA em PEM nos formatos PKCS#1 e PKCS#8
+func ParsePrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block found")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("private key is not RSA")
+	}
+	return key, nil
+}
+
+func (s *KeySet) ActiveID() string {
+	return s.active
+}
+
+func (s *KeySet) PublicKey(kid string) (*rsa.PublicKey, bool) {
+	key, ok := s.keys[kid]
+	return key, ok
+}
+
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS publica todas as chaves públicas aceitas, com a ativa primeiro
+func (s *KeySet) JWKS() JWKS {
+	ids := make([]string, 0, len(s.keys))
+	for kid := range s.keys {
+		if kid != s.active {
+			ids = append(ids, kid)
+		}
+	}
+	sort.Strings(ids)
+	ids = append([]string{s.active}, ids...)
+
+	jwks := JWKS{Keys: make([]JWK, 0, len(ids))}
+	for _, kid := range ids {
+		key := s.keys[kid]
+		jwks.Keys = append(jwks.Keys, JWK{
+			Kty: "RSA",
+			Use: "sig",
+			Alg: algorithmRS256,
+			Kid: kid,
+			N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		})
+	}
+	return jwks
+}
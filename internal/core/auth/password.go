@@ -0,0 +1,111 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// Argon2Params segue a recomendação do RFC 9106 para ambientes com memória limitada
+type Argon2Params struct {
+	Memory     uint32
+	Iterations uint32
+	Threads    uint8
+	SaltLength uint32
+	KeyLength  uint32
+}
+
+func DefaultArgon2Params() Argon2Params {
+	return Argon2Params{
+		Memory:     64 * 1024,
+		Iterations: 3,
+		Threads:    4,
+		SaltLength: 16,
+		KeyLength:  32,
+	}
+}
+
+// PasswordHasher gera hashes Argon2id no formato PHC
+// ($argon2id$v=19$m=...,t=...,p=...$salt$hash), que carrega os próprios parâmetros.
+type PasswordHasher struct {
+	params Argon2Params
+}
+
+func NewPasswordHasher(params Argon2Params) *PasswordHasher {
+	return &PasswordHasher{params: params}
+}
+
+func (h *PasswordHasher) Hash(password string) (string, error) {
+	salt := make([]byte, h.params.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, h.params.Iterations, h.params.Memory, h.params.Threads, h.params.KeyLength)
+	return fmt.Sprintf(
+		"$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, h.params.Memory, h.params.Iterations, h.params.Threads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// Verify recalcula o hash com os parâmetros gravados e compara em tempo constante
+func (h *PasswordHasher) Verify(password, encoded string) (bool, error) {
+	params, salt, key, err := decodeHash(encoded)
+	if err != nil {
+		return false, err
+	}
+
+	candidate := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Threads, uint32(len(key)))
+	return subtle.ConstantTimeCompare(key, candidate) == 1, nil
+}
+
+// NeedsRehash indica hashes gerados com parâmetros diferentes dos atuais
+func (h *PasswordHasher) NeedsRehash(encoded string) bool {
+	params, salt, key, err := decodeHash(encoded)
+	if err != nil {
+		return true
+	}
+	return params.Memory != h.params.Memory ||
+		params.Iterations != h.params.Iterations ||
+		params.Threads != h.params.Threads ||
+		uint32(len(salt)) != h.params.SaltLength ||
+		uint32(len(key)) != h.params.KeyLength
+}
+
+func decodeHash(encoded string) (Argon2Params, []byte, []byte, error) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return Argon2Params{}, nil, nil, fmt.Errorf("auth: unsupported password hash format")
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return Argon2Params{}, nil, nil, fmt.Errorf("auth: invalid password hash version: %w", err)
+	}
+	if version != argon2.Version {
+		return Argon2Params{}, nil, nil, fmt.Errorf("auth: unsupported argon2 version %d", version)
+	}
+
+	var params Argon2Params
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Threads); err != nil {
+		return Argon2Params{}, nil, nil, fmt.Errorf("auth: invalid password hash parameters: %w", err)
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return Argon2Params{}, nil, nil, fmt.Errorf("auth: invalid password hash salt: %w", err)
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return Argon2Params{}, nil, nil, fmt.Errorf("auth: invalid password hash key: %w", err)
+	}
+	params.SaltLength = uint32(len(salt))
+	params.KeyLength = uint32(len(key))
+	return params, salt, key, nil
+}
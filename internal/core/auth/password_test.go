@@ -0,0 +1,58 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPasswordHasherRoundTrip(t *testing.T) {
+	hasher := NewPasswordHasher(Argon2Params{Memory: 1024, Iterations: 1, Threads: 1, SaltLength: 16, KeyLength: 32})
+
+	hash, err := hasher.Hash("Correct horse 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(hash, "$argon2id$v=19$m=1024,t=1,p=1$") {
+		t.Fatalf("hash = %s, want an encoded argon2id hash", hash)
+	}
+
+	if ok, err := hasher.Verify("Correct horse 1", hash); err != nil || !ok {
+		t.Fatalf("Verify of the right password = %v, %v", ok, err)
+	}
+	if ok, err := hasher.Verify("correct horse 1", hash); err != nil || ok {
+		t.Fatalf("Verify of a wrong password = %v, %v", ok, err)
+	}
+
+	// O salt é aleatório: a mesma senha nunca gera o mesmo hash
+	again, err := hasher.Hash("Correct horse 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again == hash {
+		t.Fatal("two hashes of the same password are equal")
+	}
+}
+
+func TestPasswordHasherNeedsRehash(t *testing.T) {
+	weak := NewPasswordHasher(Argon2Params{Memory: 1024, Iterations: 1, Threads: 1, SaltLength: 16, KeyLength: 32})
+	stronger := NewPasswordHasher(Argon2Params{Memory: 2048, Iterations: 2, Threads: 1, SaltLength: 16, KeyLength: 32})
+
+	hash, err := weak.Hash("Correct horse 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if weak.NeedsRehash(hash) {
+		t.Fatal("hash with the current parameters needs a rehash")
+	}
+	if !stronger.NeedsRehash(hash) {
+		t.Fatal("hash with old parameters does not need a rehash")
+	}
+	// Hashes antigos continuam verificáveis com os parâmetros gravados
+	if ok, err := stronger.Verify("Correct horse 1", hash); err != nil || !ok {
+		t.Fatalf("Verify with newer parameters = %v, %v", ok, err)
+	}
+
+	if _, err := weak.Verify("x", "$2a$10$bcrypt"); err == nil {
+		t.Fatal("Verify accepted an unsupported hash format")
+	}
+}
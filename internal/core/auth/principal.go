@@ -0,0 +1,44 @@
+package auth
+
+import "context"
+
+// Principal é a identidade autenticada da requisição
+type Principal struct {
+	UserID       string
+	TenantID     string
+	MembershipID string
+	Roles        []string
+	TokenID      string
+}
+
+func (p Principal) HasRole(role string) bool {
+	for _, r := range p.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+func PrincipalFromClaims(claims Claims) Principal {
+	return Principal{
+		UserID:       claims.Subject,
+		TenantID:     claims.TenantID,
+		MembershipID: claims.MembershipID,
+		Roles:        claims.Roles,
+		TokenID:      claims.ID,
+	}
+}
+
+type principalKeyType struct{}
+
+var principalKey = principalKeyType{}
+
+func WithPrincipal(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, principalKey, principal)
+}
+
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(principalKey).(Principal)
+	return principal, ok
+}
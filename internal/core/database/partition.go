@@ -0,0 +1,39 @@
+package database
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/tenancy"
+)
+
+// ForEachPartition roda fn uma vez em escopo de sistema, para as linhas que
+// ficam no schema public, e uma vez no escopo de cada tenant isolado, cujas
+// tabelas uma consulta de sistema não alcança. A falha de um tenant não
+// impede os demais; os erros são agregados.
+func ForEachPartition(ctx context.Context, tenants tenancy.IsolatedLister, fn func(ctx context.Context) error) error {
+	systemCtx := tenancy.WithSystemScope(ctx)
+	if err := fn(systemCtx); err != nil {
+		return err
+	}
+	if tenants == nil {
+		return nil
+	}
+
+	isolated, err := tenants.FindIsolated(systemCtx)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, tenant := range isolated {
+		if ctx.Err() != nil {
+			break
+		}
+		if err := fn(tenancy.WithTenant(ctx, tenant)); err != nil {
+			errs = append(errs, fmt.Errorf("tenant %s: %w", tenant.GetID(), err))
+		}
+	}
+	return stderrors.Join(errs...)
+}
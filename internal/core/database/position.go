@@ -0,0 +1,51 @@
+package database
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Position é o cursor de tabelas append-only (outbox, event store). O serial
+// de position é reservado antes do commit, então sozinho ele não serve de
+// cursor: uma transação lenta pode confirmar uma posição menor depois que uma
+// maior já foi lida. As tabelas guardam em tx_id o xid da transação que gravou
+// a linha, a ordem passa a ser (tx_id, position) e só transações anteriores à
+// mais antiga ainda aberta são lidas.
+type Position struct {
+	TxID     int64
+	Position int64
+}
+
+func (p Position) String() string {
+	return fmt.Sprintf("%d-%d", p.TxID, p.Position)
+}
+
+func ParsePosition(value string) (Position, error) {
+	txID, position, ok := strings.Cut(value, "-")
+	if !ok {
+		return Position{}, fmt.Errorf("position %q must be <tx_id>-<position>", value)
+	}
+
+	var parsed Position
+	var err error
+	if parsed.TxID, err = strconv.ParseInt(txID, 10, 64); err != nil || parsed.TxID < 0 {
+		return Position{}, fmt.Errorf("position %q has an invalid tx_id", value)
+	}
+	if parsed.Position, err = strconv.ParseInt(position, 10, 64); err != nil || parsed.Position < 0 {
+		return Position{}, fmt.Errorf("position %q has an invalid position", value)
+	}
+	return parsed, nil
+}
+
+// After lê as linhas posteriores ao cursor cujas transações já não podem ter
+// vizinhas menores pendentes: tx_id abaixo do xmin do snapshot atual.
+func After(db *gorm.DB, after Position) *gorm.DB {
+	return db.
+		Where("(tx_id, position) > (?, ?)", after.TxID, after.Position).
+		Where("tx_id < pg_snapshot_xmin(pg_current_snapshot())::text::bigint").
+		Order("tx_id").
+		Order("position")
+}
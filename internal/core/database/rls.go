@@ -0,0 +1,105 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/tenancy"
+	"gorm.io/gorm"
+)
+
+const (
+	TenantSessionVariable = "app.current_tenant"
+
+	rlsPolicyName = "tenant_isolation"
+)
+
+// setTenantSession define a variável lida pelas policies de RLS. O escopo é
+// local à transação; leituras fora de WithTx ganham uma transação própria no
+// TenantPlugin. Operações de sistema não definem nada: atravessam tenants pela
+// conexão de sistema, cujo papel tem BYPASSRLS.
+func setTenantSession(ctx context.Context, tx *gorm.DB) error {
+	if tenancy.IsSystemScope(ctx) {
+		return nil
+	}
+
+	tenantID, ok := tenancy.TenantIDFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	return tx.Exec("SELECT set_config(?, ?, true)", TenantSessionVariable, tenantID).Error
+}
+
+func (p *TenantPlugin) setSessionInDefaultTx(db *gorm.DB) {
+	if db.Error != nil || db.DryRun || p.tenantField(db) == nil {
+		return
+	}
+
+	ctx := db.Statement.Context
+	if tenancy.IsSystemScope(ctx) {
+		return
+	}
+	tenantID, ok := tenancy.TenantIDFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	if _, err := db.Statement.ConnPool.ExecContext(ctx, "SELECT set_config($1, $2, true)", TenantSessionVariable, tenantID); err != nil {
+		db.AddError(err)
+	}
+}
+
+func RLSStatements(db *gorm.DB, models ...interface{}) ([]string, error) {
+	var statements []string
+	for _, model := range models {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(model); err != nil {
+			return nil, fmt.Errorf("failed to parse model %T: %w", model, err)
+		}
+		field := stmt.Schema.LookUpField("tenant_id")
+		if field == nil {
+			continue
+		}
+
+		table := QuoteIdentifier(stmt.Schema.Table)
+		condition := fmt.Sprintf(
+			"(%s::text = current_setting('%s', true))",
+			QuoteIdentifier(field.DBName), TenantSessionVariable,
+		)
+		// FORCE aplica as policies também ao dono da tabela, que é o papel da aplicação após Migrate
+		statements = append(statements,
+			fmt.Sprintf("ALTER TABLE %s ENABLE ROW LEVEL SECURITY", table),
+			fmt.Sprintf("ALTER TABLE %s FORCE ROW LEVEL SECURITY", table),
+			fmt.Sprintf("DROP POLICY IF EXISTS %s ON %s", rlsPolicyName, table),
+			fmt.Sprintf("CREATE POLICY %s ON %s USING %s WITH CHECK %s", rlsPolicyName, table, condition, condition),
+		)
+	}
+	return statements, nil
+}
+
+func EnableRowLevelSecurity(db *gorm.DB, models ...interface{}) error {
+	statements, err := RLSStatements(db, models...)
+	if err != nil {
+		return err
+	}
+	for _, statement := range statements {
+		if err := db.Exec(statement).Error; err != nil {
+			return fmt.Errorf("failed to apply row level security: %w", err)
+		}
+	}
+	return nil
+}
+
+// Migrate cria/atualiza as tabelas e aplica as policies de RLS das tabelas com tenant_id
+func Migrate(ctx context.Context, db *gorm.DB, models ...interface{}) error {
+	return db.WithContext(tenancy.WithSystemScope(ctx)).Transaction(func(tx *gorm.DB) error {
+		return migrateModels(tx, models...)
+	})
+}
+
+func migrateModels(tx *gorm.DB, models ...interface{}) error {
+	if err := tx.Migrator().AutoMigrate(models...); err != nil {
+		return err
+	}
+	return EnableRowLevelSecurity(tx, models...)
+}
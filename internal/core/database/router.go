@@ -0,0 +1,172 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/errors"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/tenancy"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+const ConnectionRouterName = "tenancy:connection_router"
+
+// ConnectionRouter é registrado como plugin no banco padrão e devolve o pool
+// dos tenants isolados em banco próprio.
+type ConnectionRouter interface {
+	gorm.Plugin
+	ForTenant(ctx context.Context, tenantID string) (*gorm.DB, error)
+}
+
+// UseConnectionRouter registra o roteador no banco padrão e estende o desvio
+// à conexão de sistema, usada quando o escopo de sistema carrega um tenant.
+func UseConnectionRouter(db *gorm.DB, router ConnectionRouter) error {
+	if err := db.Use(router); err != nil {
+		return err
+	}
+	if system, ok := db.Config.Plugins[SystemConnectionName].(*SystemConnection); ok {
+		return RegisterConnectionRouting(system.db, router)
+	}
+	return nil
+}
+
+type tenantTxKeyType struct{}
+
+var tenantTxKey = tenantTxKeyType{}
+
+// tenantTx é a transação aberta no banco dedicado ao lado da transação do
+// banco padrão, onde continuam as tabelas compartilhadas e o outbox.
+type tenantTx struct {
+	tenantID string
+	tx       *gorm.DB
+}
+
+// RegisterConnectionRouting desvia para o banco do tenant as instruções sobre
+// tabelas do tenant; tabelas compartilhadas seguem no banco padrão, assim como
+// no isolamento por schema. O desvio acontece antes de qualquer outro callback
+// para que a transação de leitura do TenantPlugin seja aberta no pool certo.
+func RegisterConnectionRouting(db *gorm.DB, router ConnectionRouter) error {
+	route := func(db *gorm.DB) {
+		routeStatement(db, router)
+	}
+	callbacks := []error{
+		db.Callback().Create().Before("*").Register("tenancy:route_create", route),
+		db.Callback().Query().Before("*").Register("tenancy:route_query", route),
+		db.Callback().Update().Before("*").Register("tenancy:route_update", route),
+		db.Callback().Delete().Before("*").Register("tenancy:route_delete", route),
+		db.Callback().Row().Before("*").Register("tenancy:route_row", route),
+	}
+	for _, err := range callbacks {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func routeStatement(db *gorm.DB, router ConnectionRouter) {
+	if db.Error != nil || !isTenantOwned(db.Statement.Schema) {
+		return
+	}
+	ctx := db.Statement.Context
+	tenant, ok := dedicatedTenant(ctx)
+	if !ok {
+		return
+	}
+
+	if current, ok := ctx.Value(tenantTxKey).(tenantTx); ok && current.tenantID == tenant.GetID() {
+		db.Statement.ConnPool = current.tx.Statement.ConnPool
+		return
+	}
+	pool, err := router.ForTenant(ctx, tenant.GetID())
+	if err != nil {
+		db.AddError(err)
+		return
+	}
+	db.Statement.ConnPool = pool.Statement.ConnPool
+}
+
+// isTenantOwned indica as tabelas que acompanham o isolamento do tenant
+func isTenantOwned(s *schema.Schema) bool {
+	if s == nil || s.LookUpField("tenant_id") == nil {
+		return false
+	}
+	shared, ok := reflect.New(s.ModelType).Interface().(SharedTable)
+	return !ok || !shared.SharedTable()
+}
+
+func dedicatedTenant(ctx context.Context) (tenancy.Tenant, bool) {
+	tenant, ok := tenancy.FromContext(ctx)
+	if !ok || tenant.GetIsolation() != tenancy.IsolationDatabase {
+		return nil, false
+	}
+	return tenant, true
+}
+
+// routeTenant devolve o pool do banco dedicado do tenant em contexto ou o
+// banco padrão para os demais tenants.
+func routeTenant(ctx context.Context, defaultDB *gorm.DB) (*gorm.DB, error) {
+	tenant, ok := dedicatedTenant(ctx)
+	if !ok {
+		return defaultDB, nil
+	}
+
+	router, ok := defaultDB.Config.Plugins[ConnectionRouterName].(ConnectionRouter)
+	if !ok {
+		return nil, errors.NewAppErrorWithDetails(
+			"INTERNAL_SERVER",
+			"Internal server error",
+			fmt.Sprintf("tenant %s uses a dedicated database but no connection router is registered", tenant.GetID()),
+		)
+	}
+	return router.ForTenant(ctx, tenant.GetID())
+}
+
+// withTenantTx abre, para tenants com banco dedicado, a transação do banco do
+// tenant. Ela é confirmada antes da transação do banco padrão: se esta falhar
+// depois, os dados do tenant ficam gravados sem os eventos do outbox.
+func withTenantTx(ctx context.Context, defaultDB *gorm.DB, fn func(ctx context.Context) error) error {
+	tenant, ok := dedicatedTenant(ctx)
+	if !ok {
+		return fn(ctx)
+	}
+
+	if current, ok := ctx.Value(tenantTxKey).(tenantTx); ok && current.tenantID == tenant.GetID() {
+		return current.tx.Transaction(func(nested *gorm.DB) error {
+			return fn(context.WithValue(ctx, tenantTxKey, tenantTx{tenantID: current.tenantID, tx: nested}))
+		})
+	}
+
+	pool, err := routeTenant(ctx, defaultDB)
+	if err != nil {
+		return err
+	}
+	return pool.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := setTenantSession(ctx, tx); err != nil {
+			return err
+		}
+		return fn(context.WithValue(ctx, tenantTxKey, tenantTx{tenantID: tenant.GetID(), tx: tx}))
+	})
+}
+
+// GetTenantTxFromContext é a variante de GetTxFromContext para SQL escrito à
+// mão sobre tabelas do tenant, que o roteamento por modelo não alcança.
+func GetTenantTxFromContext(ctx context.Context, defaultDB *gorm.DB) *gorm.DB {
+	tenant, ok := dedicatedTenant(ctx)
+	if !ok {
+		return GetTxFromContext(ctx, defaultDB)
+	}
+	if current, ok := ctx.Value(tenantTxKey).(tenantTx); ok && current.tenantID == tenant.GetID() {
+		return current.tx.WithContext(ctx)
+	}
+
+	pool, err := routeTenant(ctx, defaultDB)
+	if err != nil {
+		db := defaultDB.WithContext(ctx)
+		db.AddError(err)
+		return db
+	}
+	return pool.WithContext(ctx)
+}
@@ -0,0 +1,103 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/tenancy"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestConnectionRoutingSendsTenantTablesToDedicatedDatabase(t *testing.T) {
+	main, mainPool := newRecordingDB(t)
+	tenantDB, tenantPool := newRecordingDB(t)
+	router := &fakeRouter{pools: map[string]*gorm.DB{"t1": tenantDB}}
+	if err := UseConnectionRouter(main, router); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := tenancy.WithTenant(context.Background(), testTenant{id: "t1", dedicated: true})
+	if err := main.WithContext(ctx).Create(&note{ID: "n1"}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := main.WithContext(ctx).Create(&sharedNote{ID: "s1"}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if !tenantPool.executed(`INSERT INTO "notes"`) || mainPool.executed(`INSERT INTO "notes"`) {
+		t.Errorf("tenant table went to main %q / tenant %q, want the tenant database", mainPool.queries, tenantPool.queries)
+	}
+	if !mainPool.executed(`INSERT INTO "shared_notes"`) || tenantPool.executed(`INSERT INTO "shared_notes"`) {
+		t.Errorf("shared table went to main %q / tenant %q, want the main database", mainPool.queries, tenantPool.queries)
+	}
+}
+
+func TestConnectionRoutingIgnoresOtherTenants(t *testing.T) {
+	main, mainPool := newRecordingDB(t)
+	router := &fakeRouter{}
+	if err := UseConnectionRouter(main, router); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := tenancy.WithTenant(context.Background(), testTenant{id: "t1", schema: "tenant_acme"})
+	if err := main.WithContext(ctx).Create(&note{ID: "n1"}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if router.calls != 0 || !mainPool.executed(`INSERT INTO "tenant_acme"."notes"`) {
+		t.Fatalf("router called %d times, main executed %q", router.calls, mainPool.queries)
+	}
+}
+
+type fakeRouter struct {
+	pools map[string]*gorm.DB
+	calls int
+}
+
+func (r *fakeRouter) Name() string                 { return ConnectionRouterName }
+func (r *fakeRouter) Initialize(db *gorm.DB) error { return RegisterConnectionRouting(db, r) }
+
+func (r *fakeRouter) ForTenant(ctx context.Context, tenantID string) (*gorm.DB, error) {
+	r.calls++
+	return r.pools[tenantID], nil
+}
+
+// recordingPool guarda o SQL executado, sem transações
+type recordingPool struct {
+	unavailablePool
+	queries []string
+}
+
+func (p *recordingPool) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	p.queries = append(p.queries, query)
+	return recordedResult{}, nil
+}
+
+func (p *recordingPool) executed(prefix string) bool {
+	for _, query := range p.queries {
+		if strings.HasPrefix(query, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+type recordedResult struct{}
+
+func (recordedResult) LastInsertId() (int64, error) { return 0, nil }
+func (recordedResult) RowsAffected() (int64, error) { return 1, nil }
+
+func newRecordingDB(t *testing.T) (*gorm.DB, *recordingPool) {
+	t.Helper()
+	pool := &recordingPool{}
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: pool}), &gorm.Config{SkipDefaultTransaction: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Use(NewTenantPlugin()); err != nil {
+		t.Fatal(err)
+	}
+	return db, pool
+}
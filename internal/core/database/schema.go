@@ -0,0 +1,105 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/tenancy"
+	"gorm.io/gorm"
+)
+
+type SchemaProvisioner struct {
+	db     *gorm.DB
+	models []interface{}
+}
+
+func NewSchemaProvisioner(db *gorm.DB, models ...interface{}) *SchemaProvisioner {
+	return &SchemaProvisioner{db: db, models: models}
+}
+
+// Provision cria o schema do tenant ou, para tenants com banco dedicado, migra
+// as tabelas do tenant no banco devolvido pelo roteador. O banco em si precisa
+// existir antes; a aplicação não tem permissão para criá-lo.
+func (p *SchemaProvisioner) Provision(ctx context.Context, tenant tenancy.Tenant) error {
+	switch tenant.GetIsolation() {
+	case tenancy.IsolationSchema:
+		return p.provisionSchema(ctx, tenant)
+	case tenancy.IsolationDatabase:
+		return p.provisionDatabase(ctx, tenant)
+	default:
+		return nil
+	}
+}
+
+func (p *SchemaProvisioner) provisionDatabase(ctx context.Context, tenant tenancy.Tenant) error {
+	db, err := routeTenant(tenancy.WithTenant(ctx, tenant), p.db)
+	if err != nil {
+		return err
+	}
+	models, err := tenantTables(db, p.models...)
+	if err != nil {
+		return err
+	}
+	err = db.WithContext(tenancy.WithSystemScope(ctx)).Transaction(func(tx *gorm.DB) error {
+		return migrateModels(tx, models...)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to migrate database of tenant %s: %w", tenant.GetID(), err)
+	}
+	return nil
+}
+
+func (p *SchemaProvisioner) provisionSchema(ctx context.Context, tenant tenancy.Tenant) error {
+
+	schemaName := tenant.GetSchemaName()
+	if !tenancy.IsValidSchemaName(schemaName) {
+		return fmt.Errorf("invalid schema name for tenant %s: %q", tenant.GetID(), schemaName)
+	}
+
+	ctx = tenancy.WithSystemScope(ctx)
+	return p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("CREATE SCHEMA IF NOT EXISTS " + QuoteIdentifier(schemaName)).Error; err != nil {
+			return fmt.Errorf("failed to create schema %s: %w", schemaName, err)
+		}
+		if err := tx.Exec("SET LOCAL search_path TO " + QuoteIdentifier(schemaName) + ", public").Error; err != nil {
+			return err
+		}
+		models, err := tenantTables(tx, p.models...)
+		if err != nil {
+			return err
+		}
+		if err := migrateModels(tx, models...); err != nil {
+			return fmt.Errorf("failed to migrate schema %s: %w", schemaName, err)
+		}
+		return nil
+	})
+}
+
+func (p *SchemaProvisioner) Drop(ctx context.Context, tenant tenancy.Tenant) error {
+	schemaName := tenant.GetSchemaName()
+	if tenant.GetIsolation() != tenancy.IsolationSchema || !tenancy.IsValidSchemaName(schemaName) {
+		return nil
+	}
+	return p.db.WithContext(tenancy.WithSystemScope(ctx)).
+		Exec("DROP SCHEMA IF EXISTS " + QuoteIdentifier(schemaName) + " CASCADE").Error
+}
+
+// tenantTables mantém apenas os modelos que o TenantPlugin direciona para o
+// schema do tenant; os demais continuam no schema public.
+func tenantTables(db *gorm.DB, models ...interface{}) ([]interface{}, error) {
+	var tables []interface{}
+	for _, model := range models {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(model); err != nil {
+			return nil, fmt.Errorf("failed to parse model %T: %w", model, err)
+		}
+		if stmt.Schema.LookUpField("tenant_id") == nil {
+			continue
+		}
+		if shared, ok := model.(SharedTable); ok && shared.SharedTable() {
+			continue
+		}
+		tables = append(tables, model)
+	}
+	return tables, nil
+}
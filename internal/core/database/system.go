@@ -0,0 +1,41 @@
+package database
+
+import (
+	"context"
+
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/tenancy"
+	"gorm.io/gorm"
+)
+
+const SystemConnectionName = "tenancy:system_connection"
+
+// SystemConnection é registrada como plugin no banco padrão e atende as
+// operações em escopo de sistema. O papel dessa conexão precisa de BYPASSRLS:
+// as policies não têm outra forma de liberar leituras entre tenants.
+type SystemConnection struct {
+	db *gorm.DB
+}
+
+func NewSystemConnection(db *gorm.DB) *SystemConnection {
+	return &SystemConnection{db: db}
+}
+
+func (c *SystemConnection) Name() string {
+	return SystemConnectionName
+}
+
+func (c *SystemConnection) Initialize(db *gorm.DB) error {
+	return nil
+}
+
+// connectionFor troca o banco padrão pela conexão de sistema quando ela está
+// registrada; sem ela, o papel da aplicação precisa ignorar o RLS por conta própria.
+func connectionFor(ctx context.Context, defaultDB *gorm.DB) *gorm.DB {
+	if !tenancy.IsSystemScope(ctx) {
+		return defaultDB
+	}
+	if system, ok := defaultDB.Config.Plugins[SystemConnectionName].(*SystemConnection); ok {
+		return system.db
+	}
+	return defaultDB
+}
@@ -0,0 +1,243 @@
+package database
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/errors"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/tenancy"
+	"gorm.io/gorm"
+	"gorm.io/gorm/callbacks"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+const TenantPluginName = "tenancy:row_isolation"
+
+// SharedTable é implementado por modelos com tenant_id que permanecem no schema
+// public mesmo para tenants isolados por schema. Ficam ali apenas as tabelas
+// lidas antes de haver um tenant resolvido ou por todos os tenants de uma vez:
+// memberships e convites (login e aceite), refresh tokens e o outbox, fila
+// única do relay. Identidades e credenciais são globais e não têm tenant_id.
+type SharedTable interface {
+	SharedTable() bool
+}
+
+var ErrTenantScopeMissing = errors.NewAppError("INTERNAL_SERVER", "Tenant scope missing from context")
+
+type TenantPlugin struct {
+	column string
+}
+
+func NewTenantPlugin() *TenantPlugin {
+	return &TenantPlugin{column: "tenant_id"}
+}
+
+func (p *TenantPlugin) Name() string {
+	return TenantPluginName
+}
+
+func (p *TenantPlugin) Initialize(db *gorm.DB) error {
+	callbacks := []error{
+		db.Callback().Create().Before("gorm:create").Register("tenancy:create", p.stampTenant),
+		db.Callback().Query().Before("gorm:query").Register("tenancy:query", p.scopeTenant),
+		db.Callback().Update().Before("gorm:update").Register("tenancy:update", p.scopeTenant),
+		db.Callback().Delete().Before("gorm:delete").Register("tenancy:delete", p.scopeTenant),
+		db.Callback().Row().Before("gorm:row").Register("tenancy:row", p.scopeTenant),
+		db.Callback().Row().Before("gorm:row").Register("tenancy:row_tx", p.requireTxForRows),
+		db.Callback().Query().Before("gorm:query").Register("tenancy:query_begin", p.beginReadTx),
+		db.Callback().Query().After("gorm:after_query").Register("tenancy:query_commit", callbacks.CommitOrRollbackTransaction),
+		db.Callback().Create().After("gorm:begin_transaction").Before("gorm:create").Register("tenancy:create_session", p.setSessionInDefaultTx),
+		db.Callback().Update().After("gorm:begin_transaction").Before("gorm:update").Register("tenancy:update_session", p.setSessionInDefaultTx),
+		db.Callback().Delete().After("gorm:begin_transaction").Before("gorm:delete").Register("tenancy:delete_session", p.setSessionInDefaultTx),
+	}
+	for _, err := range callbacks {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *TenantPlugin) tenantField(db *gorm.DB) *schema.Field {
+	if db.Statement.Schema == nil {
+		return nil
+	}
+	return db.Statement.Schema.LookUpField(p.column)
+}
+
+// beginReadTx abre uma transação para leituras feitas fora de WithTx, pois a
+// variável lida pelo RLS só existe dentro de uma transação
+func (p *TenantPlugin) beginReadTx(db *gorm.DB) {
+	if !p.needsTenantTx(db) {
+		return
+	}
+	callbacks.BeginTransaction(db)
+	if started, _ := db.InstanceGet("gorm:started_transaction"); started == true {
+		p.setSessionInDefaultTx(db)
+	}
+}
+
+// requireTxForRows recusa Row/Rows fora de WithTx: as linhas são lidas depois
+// do callback, então não há como encerrar uma transação aberta aqui
+func (p *TenantPlugin) requireTxForRows(db *gorm.DB) {
+	if !p.needsTenantTx(db) {
+		return
+	}
+	db.AddError(errors.NewAppErrorWithDetails(
+		ErrTenantScopeMissing.Code,
+		ErrTenantScopeMissing.Message,
+		fmt.Sprintf("rows from table %s must be read inside a transaction", db.Statement.Table),
+	))
+}
+
+func (p *TenantPlugin) needsTenantTx(db *gorm.DB) bool {
+	if db.Error != nil || db.DryRun || p.tenantField(db) == nil {
+		return false
+	}
+	if _, inTx := db.Statement.ConnPool.(gorm.TxCommitter); inTx {
+		return false
+	}
+	ctx := db.Statement.Context
+	if tenancy.IsSystemScope(ctx) {
+		return false
+	}
+	// Sem tenant, scopeTenant já reporta o erro
+	_, ok := tenancy.TenantIDFromContext(ctx)
+	return ok
+}
+
+func (p *TenantPlugin) scopeTenant(db *gorm.DB) {
+	if db.Error != nil {
+		return
+	}
+	field := p.tenantField(db)
+	if field == nil {
+		return
+	}
+
+	ctx := db.Statement.Context
+	if tenancy.IsSystemScope(ctx) {
+		return
+	}
+
+	tenant, ok := tenancy.FromContext(ctx)
+	if !ok {
+		db.AddError(missingTenantError(db))
+		return
+	}
+	tenantID := tenant.GetID()
+	p.qualifySchema(db, tenant)
+
+	// Agrupa as condições existentes para que um OR não escape do filtro do tenant
+	if c, ok := db.Statement.Clauses["WHERE"]; ok {
+		if where, ok := c.Expression.(clause.Where); ok && len(where.Exprs) > 1 {
+			where.Exprs = []clause.Expression{clause.And(where.Exprs...)}
+			c.Expression = where
+			db.Statement.Clauses["WHERE"] = c
+		}
+	}
+
+	db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{
+		clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}, Value: tenantID},
+	}})
+}
+
+func (p *TenantPlugin) stampTenant(db *gorm.DB) {
+	if db.Error != nil {
+		return
+	}
+	field := p.tenantField(db)
+	if field == nil {
+		return
+	}
+
+	ctx := db.Statement.Context
+	// Operações de sistema devem preencher o tenant_id explicitamente
+	if tenancy.IsSystemScope(ctx) {
+		return
+	}
+
+	tenant, ok := tenancy.FromContext(ctx)
+	if !ok {
+		db.AddError(missingTenantError(db))
+		return
+	}
+	tenantID := tenant.GetID()
+	p.qualifySchema(db, tenant)
+
+	rv := db.Statement.ReflectValue
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			if err := p.checkTenant(db, field, rv.Index(i), tenantID); err != nil {
+				db.AddError(err)
+				return
+			}
+		}
+	case reflect.Struct:
+		if err := p.checkTenant(db, field, rv, tenantID); err != nil {
+			db.AddError(err)
+			return
+		}
+	}
+
+	db.Statement.SetColumn(field.DBName, tenantID, true)
+}
+
+// qualifySchema direciona tabelas do tenant para o seu schema quando o
+// isolamento é por schema; fora de WithTx não há search_path definido.
+func (p *TenantPlugin) qualifySchema(db *gorm.DB, tenant tenancy.Tenant) {
+	if tenant.GetIsolation() != tenancy.IsolationSchema || tenant.GetSchemaName() == "" {
+		return
+	}
+	stmt := db.Statement
+	if stmt.TableExpr != nil || strings.Contains(stmt.Table, ".") {
+		return
+	}
+	if shared, ok := reflect.New(stmt.Schema.ModelType).Interface().(SharedTable); ok && shared.SharedTable() {
+		return
+	}
+	stmt.Table = TenantTable(tenant, stmt.Table)
+}
+
+// TenantTable qualifica a tabela com o schema do tenant quando o isolamento é
+// por schema; SQL escrito à mão usa o mesmo nome que o TenantPlugin.
+func TenantTable(tenant tenancy.Tenant, table string) string {
+	if tenant == nil || tenant.GetIsolation() != tenancy.IsolationSchema || tenant.GetSchemaName() == "" {
+		return table
+	}
+	return tenant.GetSchemaName() + "." + table
+}
+
+// QuoteTable cita cada parte de um nome possivelmente qualificado por schema
+func QuoteTable(name string) string {
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		parts[i] = QuoteIdentifier(part)
+	}
+	return strings.Join(parts, ".")
+}
+
+func (p *TenantPlugin) checkTenant(db *gorm.DB, field *schema.Field, rv reflect.Value, tenantID string) error {
+	for rv.Kind() == reflect.Ptr {
+		rv = rv.Elem()
+	}
+	value, zero := field.ValueOf(db.Statement.Context, rv)
+	if zero {
+		return nil
+	}
+	if current, ok := value.(string); ok && current != tenantID {
+		return errors.NewAppErrorWithDetails("FORBIDDEN", "Access forbidden", "record belongs to another tenant")
+	}
+	return nil
+}
+
+func missingTenantError(db *gorm.DB) error {
+	return errors.NewAppErrorWithDetails(
+		ErrTenantScopeMissing.Code,
+		ErrTenantScopeMissing.Message,
+		fmt.Sprintf("table %s requires a tenant in context", db.Statement.Table),
+	)
+}
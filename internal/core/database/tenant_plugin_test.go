@@ -0,0 +1,205 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	stderrors "errors"
+	"testing"
+
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/errors"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/tenancy"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestTenantPluginScopesStatements(t *testing.T) {
+	db := newDryRunDB(t)
+	ctx := tenancy.WithTenant(context.Background(), testTenant{id: "t1"})
+	var notes []note
+
+	cases := []struct {
+		name string
+		run  func(tx *gorm.DB) *gorm.DB
+		sql  string
+		vars []interface{}
+	}{
+		{
+			name: "query groups existing conditions",
+			run:  func(tx *gorm.DB) *gorm.DB { return tx.Where("body = ?", "a").Or("body = ?", "b").Find(&notes) },
+			sql:  `SELECT * FROM "notes" WHERE (body = $1 OR body = $2) AND "notes"."tenant_id" = $3`,
+			vars: []interface{}{"a", "b", "t1"},
+		},
+		{
+			name: "create stamps the tenant",
+			run:  func(tx *gorm.DB) *gorm.DB { return tx.Create(&note{ID: "n1", Body: "x"}) },
+			sql:  `INSERT INTO "notes" ("id","tenant_id","body") VALUES ($1,$2,$3)`,
+			vars: []interface{}{"n1", "t1", "x"},
+		},
+		{
+			name: "update",
+			run:  func(tx *gorm.DB) *gorm.DB { return tx.Model(&note{ID: "n1"}).Update("body", "y") },
+			sql:  `UPDATE "notes" SET "body"=$1 WHERE "notes"."tenant_id" = $2 AND "id" = $3`,
+			vars: []interface{}{"y", "t1", "n1"},
+		},
+		{
+			name: "delete",
+			run:  func(tx *gorm.DB) *gorm.DB { return tx.Delete(&note{ID: "n1"}) },
+			sql:  `DELETE FROM "notes" WHERE "notes"."tenant_id" = $1 AND "notes"."id" = $2`,
+			vars: []interface{}{"t1", "n1"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := tc.run(db.WithContext(ctx))
+			if result.Error != nil {
+				t.Fatal(result.Error)
+			}
+			assertStatement(t, result.Statement, tc.sql, tc.vars)
+		})
+	}
+}
+
+func TestTenantPluginQualifiesSchemaTenants(t *testing.T) {
+	db := newDryRunDB(t)
+	ctx := tenancy.WithTenant(context.Background(), testTenant{id: "t1", schema: "tenant_acme"})
+
+	var notes []note
+	result := db.WithContext(ctx).Find(&notes)
+	assertStatement(t, result.Statement, `SELECT * FROM "tenant_acme"."notes" WHERE "tenant_acme"."notes"."tenant_id" = $1`, []interface{}{"t1"})
+
+	// Tabelas compartilhadas continuam no schema public, mas filtradas pelo tenant
+	var shared []sharedNote
+	result = db.WithContext(ctx).Find(&shared)
+	assertStatement(t, result.Statement, `SELECT * FROM "shared_notes" WHERE "shared_notes"."tenant_id" = $1`, []interface{}{"t1"})
+}
+
+func TestTenantPluginRequiresTenantScope(t *testing.T) {
+	db := newDryRunDB(t)
+	var notes []note
+
+	statements := map[string]*gorm.DB{
+		"query":  db.Find(&notes),
+		"create": db.Create(&note{ID: "n1"}),
+		"update": db.Model(&note{ID: "n1"}).Update("body", "y"),
+		"delete": db.Delete(&note{ID: "n1"}),
+	}
+	for name, result := range statements {
+		var appErr errors.AppError
+		if !stderrors.As(result.Error, &appErr) || appErr.Code != ErrTenantScopeMissing.Code {
+			t.Errorf("%s without tenant returned %v, want %s", name, result.Error, ErrTenantScopeMissing.Code)
+		}
+	}
+
+	// Tabelas sem tenant_id não dependem do contexto
+	var globals []global
+	if err := db.Find(&globals).Error; err != nil {
+		t.Errorf("query on a global table returned %v", err)
+	}
+}
+
+func TestTenantPluginSystemScope(t *testing.T) {
+	db := newDryRunDB(t)
+	ctx := tenancy.WithSystemScope(context.Background())
+
+	var notes []note
+	result := db.WithContext(ctx).Where("body = ?", "a").Find(&notes)
+	assertStatement(t, result.Statement, `SELECT * FROM "notes" WHERE body = $1`, []interface{}{"a"})
+
+	result = db.WithContext(ctx).Create(&note{ID: "n1", TenantID: "t2"})
+	assertStatement(t, result.Statement, `INSERT INTO "notes" ("id","tenant_id","body") VALUES ($1,$2,$3)`, []interface{}{"n1", "t2", ""})
+}
+
+func TestTenantPluginRejectsRecordsOfAnotherTenant(t *testing.T) {
+	db := newDryRunDB(t)
+	ctx := tenancy.WithTenant(context.Background(), testTenant{id: "t1"})
+
+	err := db.WithContext(ctx).Create(&note{ID: "n1", TenantID: "t2"}).Error
+	var appErr errors.AppError
+	if !stderrors.As(err, &appErr) || appErr.Code != errors.ErrForbidden.Code {
+		t.Fatalf("Create for another tenant returned %v, want FORBIDDEN", err)
+	}
+}
+
+type note struct {
+	ID       string
+	TenantID string
+	Body     string
+}
+
+type sharedNote struct {
+	ID       string
+	TenantID string
+}
+
+func (sharedNote) SharedTable() bool { return true }
+
+type global struct {
+	ID string
+}
+
+type testTenant struct {
+	id        string
+	schema    string
+	dedicated bool
+}
+
+func (t testTenant) GetID() string   { return t.id }
+func (t testTenant) GetSlug() string { return t.id }
+func (t testTenant) IsActive() bool  { return true }
+func (t testTenant) GetIsolation() tenancy.Isolation {
+	if t.dedicated {
+		return tenancy.IsolationDatabase
+	}
+	if t.schema != "" {
+		return tenancy.IsolationSchema
+	}
+	return tenancy.IsolationSharedTable
+}
+func (t testTenant) GetSchemaName() string { return t.schema }
+
+func assertStatement(t *testing.T, stmt *gorm.Statement, sql string, vars []interface{}) {
+	t.Helper()
+	if got := stmt.SQL.String(); got != sql {
+		t.Fatalf("SQL = %s\nwant  %s", got, sql)
+	}
+	if len(stmt.Vars) != len(vars) {
+		t.Fatalf("vars = %v, want %v", stmt.Vars, vars)
+	}
+	for i := range vars {
+		if stmt.Vars[i] != vars[i] {
+			t.Fatalf("vars = %v, want %v", stmt.Vars, vars)
+		}
+	}
+}
+
+// newDryRunDB monta o SQL sem conexão com o PostgreSQL
+func newDryRunDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: unavailablePool{}}), &gorm.Config{DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Use(NewTenantPlugin()); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+type unavailablePool struct{}
+
+func (unavailablePool) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return nil, sql.ErrConnDone
+}
+
+func (unavailablePool) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return nil, sql.ErrConnDone
+}
+
+func (unavailablePool) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return nil, sql.ErrConnDone
+}
+
+func (unavailablePool) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return nil
+}
@@ -2,7 +2,9 @@ package database
 
 import (
 	"context"
+	"strings"
 
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/tenancy"
 	"gorm.io/gorm"
 )
 
@@ -23,15 +25,50 @@ type txKeyType struct{}
 var txKey = txKeyType{}
 
 func (tm *GormTxManager) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
-	return tm.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+	// Transações aninhadas reaproveitam a externa através de SAVEPOINT
+	if tx, ok := ctx.Value(txKey).(*gorm.DB); ok {
+		return tx.Transaction(func(nested *gorm.DB) error {
+			return withTenantTx(context.WithValue(ctx, txKey, nested), tm.db, fn)
+		})
+	}
+
+	return connectionFor(ctx, tm.db).WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := setSearchPath(tx, ctx); err != nil {
+			return err
+		}
+		if err := setTenantSession(ctx, tx); err != nil {
+			return err
+		}
 		txCtx := context.WithValue(ctx, txKey, tx)
-		return fn(txCtx)
+		return withTenantTx(txCtx, tm.db, fn)
 	})
 }
 
+func InTx(ctx context.Context) bool {
+	_, ok := ctx.Value(txKey).(*gorm.DB)
+	return ok
+}
+
+// GetTxFromContext devolve a transação corrente ou a conexão do escopo do
+// contexto, sempre ligadas ao contexto recebido para que os callbacks de
+// tenant enxerguem valores adicionados depois do início da transação. As
+// instruções sobre tabelas de tenants com banco dedicado são desviadas para o
+// banco do tenant pelo roteamento de conexões.
 func GetTxFromContext(ctx context.Context, defaultDB *gorm.DB) *gorm.DB {
 	if tx, ok := ctx.Value(txKey).(*gorm.DB); ok {
-		return tx
+		return tx.WithContext(ctx)
 	}
-	return defaultDB
+	return connectionFor(ctx, defaultDB).WithContext(ctx)
+}
+
+func setSearchPath(tx *gorm.DB, ctx context.Context) error {
+	tenant, ok := tenancy.FromContext(ctx)
+	if !ok || tenant.GetIsolation() != tenancy.IsolationSchema {
+		return nil
+	}
+	return tx.Exec("SET LOCAL search_path TO " + QuoteIdentifier(tenant.GetSchemaName()) + ", public").Error
+}
+
+func QuoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
 }
@@ -1,5 +1,7 @@
 package domain
 
+import "github.com/williamkoller/multi-tenant-nexus-manager/internal/core/errors"
+
 type AggregateRoot interface {
 	GetID() string
 	GetVersion() int64
@@ -10,9 +12,19 @@ type AggregateRoot interface {
 
 type BaseAggregateRoot struct {
 	BaseEntity
+	Version      int64 `json:"version" gorm:"not null"`
 	domainEvents []DomainEvent
 }
 
+func (a *BaseAggregateRoot) GetVersion() int64 {
+	return a.Version
+}
+
+// SetVersion é usado pelos repositórios após persistir o agregado
+func (a *BaseAggregateRoot) SetVersion(version int64) {
+	a.Version = version
+}
+
 func (a *BaseAggregateRoot) GetDomainEvents() []DomainEvent {
 	return a.domainEvents
 }
@@ -24,3 +36,11 @@ func (a *BaseAggregateRoot) ClearDomainEvents() {
 func (a *BaseAggregateRoot) RaiseDomainEvent(event DomainEvent) {
 	a.domainEvents = append(a.domainEvents, event)
 }
+
+// ExpectVersion valida a versão informada pelo cliente (If-Match); zero ignora a checagem
+func ExpectVersion(aggregate AggregateRoot, expected int64) error {
+	if expected > 0 && aggregate.GetVersion() != expected {
+		return errors.ErrPreconditionFailed
+	}
+	return nil
+}
@@ -11,25 +11,46 @@ type Repository[T AggregateRoot] interface {
 
 type ReadOnlyRepository[T any] interface {
 	FindByID(ctx context.Context, id string) (T, error)
-	FindAll(ctx context.Context, filter Filter) ([]T, error)
+	FindAll(ctx context.Context, filter Filter) (Page[T], error)
 	Count(ctx context.Context, filter Filter) (int64, error)
 	Exists(ctx context.Context, id string) (bool, error)
 }
 
+type PaginationMode string
+
+const (
+	PaginationOffset PaginationMode = "offset"
+	PaginationCursor PaginationMode = "cursor"
+)
+
 type Filter struct {
-	Limit  int                    `json:"limit"`
-	Offset int                    `json:"offset"`
-	Sort   string                 `json:"sort"`
-	Order  string                 `json:"order"`
-	Where  map[string]interface{} `json:"where"`
+	Limit      int                    `json:"limit"`
+	Offset     int                    `json:"offset"`
+	Sort       string                 `json:"sort"`
+	Order      string                 `json:"order"`
+	Where      map[string]interface{} `json:"where"`
+	Pagination PaginationMode         `json:"pagination"`
+	Cursor     string                 `json:"cursor,omitempty"`
 }
 
 func NewFilter() Filter {
 	return Filter{
-		Limit:  10,
-		Offset: 0,
-		Sort:   "created_at",
-		Order:  "desc",
-		Where:  make(map[string]interface{}),
+		Limit:      10,
+		Offset:     0,
+		Sort:       "created_at",
+		Order:      "desc",
+		Where:      make(map[string]interface{}),
+		Pagination: PaginationOffset,
 	}
 }
+
+func (f Filter) IsCursorPagination() bool {
+	return f.Pagination == PaginationCursor || f.Cursor != ""
+}
+
+// Page carrega os itens e, na paginação por cursor, os cursores opacos vizinhos
+type Page[T any] struct {
+	Items      []T    `json:"items"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+}
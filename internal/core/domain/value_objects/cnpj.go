@@ -1,6 +1,7 @@
 package value_objects
 
 import (
+	"database/sql/driver"
 	"fmt"
 	"regexp"
 )
@@ -68,3 +69,25 @@ func isValidCNPJ(cnpj string) bool {
 
 	return int(cnpj[13]-'0') == digit2
 }
+
+func (c CNPJ) Value() (driver.Value, error) {
+	return c.value, nil
+}
+
+func (c *CNPJ) Scan(value interface{}) error {
+	str, err := scanString(value)
+	if err != nil {
+		return err
+	}
+	if str == "" {
+		*c = CNPJ{}
+		return nil
+	}
+
+	parsed, err := NewCNPJ(str)
+	if err != nil {
+		return err
+	}
+	*c = parsed
+	return nil
+}
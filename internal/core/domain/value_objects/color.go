@@ -1,6 +1,7 @@
 package value_objects
 
 import (
+	"database/sql/driver"
 	"fmt"
 	"regexp"
 	"strconv"
@@ -45,3 +46,25 @@ func (c Color) IsLight() bool {
 	brightness := (r*299 + g*587 + b*114) / 1000
 	return brightness > 128
 }
+
+func (c Color) Value() (driver.Value, error) {
+	return c.value, nil
+}
+
+func (c *Color) Scan(value interface{}) error {
+	str, err := scanString(value)
+	if err != nil {
+		return err
+	}
+	if str == "" {
+		*c = Color{}
+		return nil
+	}
+
+	parsed, err := NewColor(str)
+	if err != nil {
+		return err
+	}
+	*c = parsed
+	return nil
+}
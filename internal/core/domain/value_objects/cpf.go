@@ -1,6 +1,7 @@
 package value_objects
 
 import (
+	"database/sql/driver"
 	"fmt"
 	"regexp"
 )
@@ -66,3 +67,25 @@ func isValidCPF(cpf string) bool {
 	}
 	return int(cpf[10]-'0') == digit2
 }
+
+func (c CPF) Value() (driver.Value, error) {
+	return c.value, nil
+}
+
+func (c *CPF) Scan(value interface{}) error {
+	str, err := scanString(value)
+	if err != nil {
+		return err
+	}
+	if str == "" {
+		*c = CPF{}
+		return nil
+	}
+
+	parsed, err := NewCPF(str)
+	if err != nil {
+		return err
+	}
+	*c = parsed
+	return nil
+}
@@ -1,6 +1,7 @@
 package value_objects
 
 import (
+	"database/sql/driver"
 	"fmt"
 	"regexp"
 	"strings"
@@ -28,3 +29,25 @@ func (e Email) String() string {
 func (e Email) IsEmpty() bool {
 	return e.value == ""
 }
+
+func (e Email) Value() (driver.Value, error) {
+	return e.value, nil
+}
+
+func (e *Email) Scan(value interface{}) error {
+	str, err := scanString(value)
+	if err != nil {
+		return err
+	}
+	if str == "" {
+		*e = Email{}
+		return nil
+	}
+
+	parsed, err := NewEmail(str)
+	if err != nil {
+		return err
+	}
+	*e = parsed
+	return nil
+}
@@ -1,6 +1,7 @@
 package value_objects
 
 import (
+	"database/sql/driver"
 	"fmt"
 	"regexp"
 )
@@ -60,3 +61,25 @@ func (p Phone) IsMobile() bool {
 	// Celular tem 11 dígitos e o terceiro dígito é 9
 	return len(p.value) == 11 && p.value[2] == '9'
 }
+
+func (p Phone) Value() (driver.Value, error) {
+	return p.value, nil
+}
+
+func (p *Phone) Scan(value interface{}) error {
+	str, err := scanString(value)
+	if err != nil {
+		return err
+	}
+	if str == "" {
+		*p = Phone{}
+		return nil
+	}
+
+	parsed, err := NewPhone(str)
+	if err != nil {
+		return err
+	}
+	*p = parsed
+	return nil
+}
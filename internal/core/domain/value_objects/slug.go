@@ -1,6 +1,7 @@
 package value_objects
 
 import (
+	"database/sql/driver"
 	"fmt"
 	"regexp"
 	"strings"
@@ -49,3 +50,25 @@ func (s Slug) String() string {
 func (s Slug) IsEmpty() bool {
 	return s.value == ""
 }
+
+func (s Slug) Value() (driver.Value, error) {
+	return s.value, nil
+}
+
+func (s *Slug) Scan(value interface{}) error {
+	str, err := scanString(value)
+	if err != nil {
+		return err
+	}
+	if str == "" {
+		*s = Slug{}
+		return nil
+	}
+
+	parsed, err := NewSlug(str)
+	if err != nil {
+		return err
+	}
+	*s = parsed
+	return nil
+}
@@ -0,0 +1,17 @@
+package value_objects
+
+import "fmt"
+
+// scanString normaliza os valores vindos do banco para string
+func scanString(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case []byte:
+		return string(v), nil
+	default:
+		return "", fmt.Errorf("cannot scan %T into value object", value)
+	}
+}
@@ -34,4 +34,6 @@ var (
 	ErrForbidden      = NewAppError("FORBIDDEN", "Access forbidden")
 	ErrInternalServer = NewAppError("INTERNAL_SERVER", "Internal server error")
 	ErrConflict       = NewAppError("CONFLICT", "Resource conflict")
+
+	ErrPreconditionFailed = NewAppError("PRECONDITION_FAILED", "Resource version does not match")
 )
@@ -0,0 +1,199 @@
+package events
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/domain"
+)
+
+type Handler func(ctx context.Context, event domain.DomainEvent) error
+
+type EventBus interface {
+	Publish(ctx context.Context, event domain.DomainEvent) error
+	Subscribe(pattern string, handler Handler, opts ...SubscribeOption) (unsubscribe func())
+}
+
+type subscription struct {
+	id       int
+	pattern  string
+	name     string
+	handler  Handler
+	async    bool
+	attempts int
+	backoff  time.Duration
+}
+
+type SubscribeOption func(*subscription)
+
+// Async executa o handler fora da goroutine do publish, com o contexto da
+// requisição (tenant, request id) mas sem o seu cancelamento.
+func Async() SubscribeOption {
+	return func(s *subscription) {
+		s.async = true
+	}
+}
+
+func WithRetry(attempts int, backoff time.Duration) SubscribeOption {
+	return func(s *subscription) {
+		if attempts > 0 {
+			s.attempts = attempts
+		}
+		s.backoff = backoff
+	}
+}
+
+func WithName(name string) SubscribeOption {
+	return func(s *subscription) {
+		s.name = name
+	}
+}
+
+type InMemoryEventBus struct {
+	mu            sync.RWMutex
+	subscriptions []*subscription
+	nextID        int
+	inFlight      sync.WaitGroup
+}
+
+var _ EventBus = (*InMemoryEventBus)(nil)
+
+func NewInMemoryEventBus() *InMemoryEventBus {
+	return &InMemoryEventBus{}
+}
+
+// Subscribe registra um handler para um tipo exato ("user.created"), um
+// prefixo ("user.*") ou todos os eventos ("*").
+func (b *InMemoryEventBus) Subscribe(pattern string, handler Handler, opts ...SubscribeOption) func() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.nextID++
+	sub := &subscription{
+		id:       b.nextID,
+		pattern:  pattern,
+		name:     pattern,
+		handler:  handler,
+		attempts: 1,
+	}
+	for _, opt := range opts {
+		opt(sub)
+	}
+	b.subscriptions = append(b.subscriptions, sub)
+
+	return func() { b.unsubscribe(sub.id) }
+}
+
+func (b *InMemoryEventBus) unsubscribe(id int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for i, sub := range b.subscriptions {
+		if sub.id == id {
+			b.subscriptions = append(b.subscriptions[:i:i], b.subscriptions[i+1:]...)
+			return
+		}
+	}
+}
+
+// Publish entrega o evento a todos os handlers compatíveis. A falha de um
+// handler não impede os demais; os erros dos handlers síncronos são agregados.
+func (b *InMemoryEventBus) Publish(ctx context.Context, event domain.DomainEvent) error {
+	b.mu.RLock()
+	matched := make([]*subscription, 0, len(b.subscriptions))
+	for _, sub := range b.subscriptions {
+		if Match(sub.pattern, event.GetEventType()) {
+			matched = append(matched, sub)
+		}
+	}
+	b.mu.RUnlock()
+
+	var errs []error
+	for _, sub := range matched {
+		if sub.async {
+			b.inFlight.Add(1)
+			go func(sub *subscription) {
+				defer b.inFlight.Done()
+				if err := b.dispatch(context.WithoutCancel(ctx), sub, event); err != nil {
+					log.Printf("event bus: async handler %s failed for %s (%s): %v",
+						sub.name, event.GetEventType(), event.GetEventID(), err)
+				}
+			}(sub)
+			continue
+		}
+
+		if err := b.dispatch(ctx, sub, event); err != nil {
+			errs = append(errs, fmt.Errorf("handler %s: %w", sub.name, err))
+		}
+	}
+	return stderrors.Join(errs...)
+}
+
+func (b *InMemoryEventBus) PublishAll(ctx context.Context, events []domain.DomainEvent) error {
+	var errs []error
+	for _, event := range events {
+		errs = append(errs, b.Publish(ctx, event))
+	}
+	return stderrors.Join(errs...)
+}
+
+func (b *InMemoryEventBus) dispatch(ctx context.Context, sub *subscription, event domain.DomainEvent) error {
+	var err error
+	delay := sub.backoff
+	for attempt := 1; attempt <= sub.attempts; attempt++ {
+		if err = safeCall(ctx, sub.handler, event); err == nil {
+			return nil
+		}
+		if attempt == sub.attempts {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return stderrors.Join(err, ctx.Err())
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+	return err
+}
+
+func safeCall(ctx context.Context, handler Handler, event domain.DomainEvent) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("handler panicked: %v", r)
+		}
+	}()
+	return handler(ctx, event)
+}
+
+// Close aguarda os handlers assíncronos em andamento ou o fim do contexto
+func (b *InMemoryEventBus) Close(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		b.inFlight.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func Match(pattern, eventType string) bool {
+	if pattern == "*" || pattern == eventType {
+		return true
+	}
+	if prefix, ok := strings.CutSuffix(pattern, ".*"); ok {
+		return strings.HasPrefix(eventType, prefix+".")
+	}
+	return false
+}
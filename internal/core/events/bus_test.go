@@ -0,0 +1,150 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/domain"
+)
+
+func TestInMemoryEventBusMatchesPatterns(t *testing.T) {
+	bus := NewInMemoryEventBus()
+	var received []string
+	record := func(name string) Handler {
+		return func(ctx context.Context, event domain.DomainEvent) error {
+			received = append(received, name+":"+event.GetEventType())
+			return nil
+		}
+	}
+
+	bus.Subscribe("user.created", record("exact"))
+	bus.Subscribe("user.*", record("prefix"))
+	unsubscribe := bus.Subscribe("*", record("all"))
+
+	for _, eventType := range []string{"user.created", "user.deactivated", "username.changed"} {
+		if err := bus.Publish(context.Background(), domain.NewBaseDomainEvent(eventType, "u1", nil)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := []string{
+		"exact:user.created", "prefix:user.created", "all:user.created",
+		"prefix:user.deactivated", "all:user.deactivated",
+		"all:username.changed",
+	}
+	if !slices.Equal(received, want) {
+		t.Fatalf("received %v, want %v", received, want)
+	}
+
+	unsubscribe()
+	received = nil
+	if err := bus.Publish(context.Background(), domain.NewBaseDomainEvent("tenant.created", "t1", nil)); err != nil {
+		t.Fatal(err)
+	}
+	if len(received) != 0 {
+		t.Fatalf("unsubscribed handler received %v", received)
+	}
+}
+
+func TestInMemoryEventBusPropagatesHandlerErrors(t *testing.T) {
+	bus := NewInMemoryEventBus()
+	failure := stderrors.New("mailer unavailable")
+	delivered := false
+
+	bus.Subscribe("user.created", func(ctx context.Context, event domain.DomainEvent) error {
+		return failure
+	}, WithName("mailer"))
+	bus.Subscribe("user.created", func(ctx context.Context, event domain.DomainEvent) error {
+		panic("boom")
+	}, WithName("audit"))
+	bus.Subscribe("user.created", func(ctx context.Context, event domain.DomainEvent) error {
+		delivered = true
+		return nil
+	})
+
+	err := bus.Publish(context.Background(), domain.NewBaseDomainEvent("user.created", "u1", nil))
+	if !stderrors.Is(err, failure) {
+		t.Fatalf("Publish returned %v, want it to wrap the handler error", err)
+	}
+	if !strings.Contains(err.Error(), "handler mailer") || !strings.Contains(err.Error(), "handler audit: handler panicked") {
+		t.Fatalf("Publish error %q does not name the failing handlers", err)
+	}
+	// A falha de um handler não impede os demais
+	if !delivered {
+		t.Fatal("healthy handler was skipped")
+	}
+}
+
+func TestInMemoryEventBusRetriesAndRunsAsync(t *testing.T) {
+	bus := NewInMemoryEventBus()
+	attempts := 0
+	bus.Subscribe("user.created", func(ctx context.Context, event domain.DomainEvent) error {
+		attempts++
+		if attempts < 3 {
+			return stderrors.New("transient")
+		}
+		return nil
+	}, WithRetry(3, time.Millisecond))
+
+	if err := bus.Publish(context.Background(), domain.NewBaseDomainEvent("user.created", "u1", nil)); err != nil {
+		t.Fatalf("Publish returned %v after a successful retry", err)
+	}
+	if attempts != 3 {
+		t.Fatalf("attempts = %d, want 3", attempts)
+	}
+
+	done := make(chan struct{})
+	bus.Subscribe("user.deleted", func(ctx context.Context, event domain.DomainEvent) error {
+		defer close(done)
+		if ctx.Err() != nil {
+			t.Error("async handler inherited the request cancellation")
+		}
+		return stderrors.New("ignored by Publish")
+	}, Async())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := bus.Publish(ctx, domain.NewBaseDomainEvent("user.deleted", "u1", nil)); err != nil {
+		t.Fatalf("Publish returned the error of an async handler: %v", err)
+	}
+	cancel()
+	if err := bus.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	<-done
+}
+
+type accountOpened struct {
+	AccountID string `json:"account_id"`
+}
+
+func TestOnDeliversTypedPayloads(t *testing.T) {
+	Register[accountOpened](DefaultRegistry(), "account.opened", 1)
+	bus := NewInMemoryEventBus()
+
+	var received []accountOpened
+	On(bus, func(ctx context.Context, event accountOpened) error {
+		received = append(received, event)
+		return nil
+	})
+
+	published := []domain.DomainEvent{
+		domain.NewBaseDomainEvent("account.opened", "a1", accountOpened{AccountID: "a1"}),
+		// Payloads vindos do relay ainda estão em JSON
+		domain.NewBaseDomainEvent("account.opened", "a2", json.RawMessage(`{"account_id":"a2"}`)),
+		domain.NewBaseDomainEvent("account.closed", "a3", json.RawMessage(`{"account_id":"a3"}`)),
+		domain.NewBaseDomainEvent("account.renamed", "a4", map[string]string{"account_id": "a4"}),
+	}
+	if err := bus.PublishAll(context.Background(), published); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []accountOpened{{AccountID: "a1"}, {AccountID: "a2"}}
+	if !slices.Equal(received, want) {
+		t.Fatalf("received %v, want %v", received, want)
+	}
+}
@@ -0,0 +1,131 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/domain"
+)
+
+// Upcaster transforma o payload de uma versão para a seguinte (v1 → v2)
+type Upcaster func(payload json.RawMessage) (json.RawMessage, error)
+
+type registeredType struct {
+	version int
+	goType  reflect.Type
+}
+
+// Registry associa cada tipo de evento ao struct Go da sua versão atual e às
+// funções que atualizam payloads antigos lidos do outbox ou do event store.
+type Registry struct {
+	mu        sync.RWMutex
+	types     map[string]registeredType
+	upcasters map[string]map[int]Upcaster
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		types:     make(map[string]registeredType),
+		upcasters: make(map[string]map[int]Upcaster),
+	}
+}
+
+var (
+	defaultRegistry     *Registry
+	defaultRegistryOnce sync.Once
+)
+
+func DefaultRegistry() *Registry {
+	defaultRegistryOnce.Do(func() {
+		defaultRegistry = NewRegistry()
+	})
+	return defaultRegistry
+}
+
+// Register define T como o payload da versão atual do tipo de evento
+func Register[T any](r *Registry, eventType string, version int) {
+	if version < 1 {
+		panic(fmt.Sprintf("events: invalid schema version %d for %s", version, eventType))
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.types[eventType] = registeredType{version: version, goType: reflect.TypeFor[T]()}
+}
+
+func (r *Registry) goType(eventType string) (reflect.Type, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	registered, ok := r.types[eventType]
+	return registered.goType, ok
+}
+
+// RegisterUpcaster registra a transformação de fromVersion para fromVersion+1
+func (r *Registry) RegisterUpcaster(eventType string, fromVersion int, upcaster Upcaster) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.upcasters[eventType] == nil {
+		r.upcasters[eventType] = make(map[int]Upcaster)
+	}
+	r.upcasters[eventType][fromVersion] = upcaster
+}
+
+func (r *Registry) CurrentVersion(eventType string) int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if registered, ok := r.types[eventType]; ok {
+		return registered.version
+	}
+	return 1
+}
+
+func (r *Registry) Encode(event domain.DomainEvent) ([]byte, int, error) {
+	payload, err := json.Marshal(event.GetEventData())
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to encode %s: %w", event.GetEventType(), err)
+	}
+	return payload, r.CurrentVersion(event.GetEventType()), nil
+}
+
+// Decode aplica os upcasters até a versão atual e desserializa no struct
+// registrado. Tipos não registrados seguem como json.RawMessage.
+func (r *Registry) Decode(eventType string, version int, payload []byte) (interface{}, error) {
+	r.mu.RLock()
+	registered, ok := r.types[eventType]
+	upcasters := r.upcasters[eventType]
+	r.mu.RUnlock()
+
+	if !ok {
+		return json.RawMessage(payload), nil
+	}
+	if version < 1 {
+		version = 1
+	}
+	if version > registered.version {
+		return nil, fmt.Errorf("%s payload version %d is newer than supported version %d", eventType, version, registered.version)
+	}
+
+	data := json.RawMessage(payload)
+	for v := version; v < registered.version; v++ {
+		upcaster, ok := upcasters[v]
+		if !ok {
+			return nil, fmt.Errorf("missing upcaster for %s from version %d", eventType, v)
+		}
+		upcasted, err := upcaster(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to upcast %s from version %d: %w", eventType, v, err)
+		}
+		data = upcasted
+	}
+
+	target := reflect.New(registered.goType)
+	if err := json.Unmarshal(data, target.Interface()); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", eventType, err)
+	}
+	return target.Elem().Interface(), nil
+}
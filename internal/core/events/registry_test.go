@@ -0,0 +1,90 @@
+package events
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+type renamedV3 struct {
+	GivenName  string `json:"given_name"`
+	FamilyName string `json:"family_name"`
+	Locale     string `json:"locale"`
+}
+
+func newRenamedRegistry() *Registry {
+	registry := NewRegistry()
+	Register[renamedV3](registry, "profile.renamed", 3)
+
+	// v1 → v2: "name" é dividido em nome e sobrenome
+	registry.RegisterUpcaster("profile.renamed", 1, func(payload json.RawMessage) (json.RawMessage, error) {
+		var v1 struct {
+			Name string `json:"name"`
+		}
+		if err := json.Unmarshal(payload, &v1); err != nil {
+			return nil, err
+		}
+		given, family, _ := strings.Cut(v1.Name, " ")
+		return json.Marshal(map[string]string{"given_name": given, "family_name": family})
+	})
+	// v2 → v3: locale passa a ser obrigatório
+	registry.RegisterUpcaster("profile.renamed", 2, func(payload json.RawMessage) (json.RawMessage, error) {
+		var v2 map[string]interface{}
+		if err := json.Unmarshal(payload, &v2); err != nil {
+			return nil, err
+		}
+		v2["locale"] = "pt-BR"
+		return json.Marshal(v2)
+	})
+	return registry
+}
+
+func TestRegistryChainsUpcasters(t *testing.T) {
+	registry := newRenamedRegistry()
+	want := renamedV3{GivenName: "Ana", FamilyName: "Souza", Locale: "pt-BR"}
+
+	payloads := map[int]string{
+		1: `{"name":"Ana Souza"}`,
+		2: `{"given_name":"Ana","family_name":"Souza"}`,
+		3: `{"given_name":"Ana","family_name":"Souza","locale":"pt-BR"}`,
+	}
+	for version, payload := range payloads {
+		decoded, err := registry.Decode("profile.renamed", version, []byte(payload))
+		if err != nil {
+			t.Fatalf("Decode v%d returned %v", version, err)
+		}
+		if decoded != want {
+			t.Fatalf("Decode v%d = %#v, want %#v", version, decoded, want)
+		}
+	}
+}
+
+func TestRegistryRejectsUnknownVersions(t *testing.T) {
+	registry := newRenamedRegistry()
+
+	if _, err := registry.Decode("profile.renamed", 4, []byte(`{}`)); err == nil {
+		t.Fatal("Decode accepted a payload newer than the registered version")
+	}
+
+	incomplete := NewRegistry()
+	Register[renamedV3](incomplete, "profile.renamed", 3)
+	incomplete.RegisterUpcaster("profile.renamed", 1, registry.upcasters["profile.renamed"][1])
+	if _, err := incomplete.Decode("profile.renamed", 1, []byte(`{"name":"Ana"}`)); err == nil || !strings.Contains(err.Error(), "missing upcaster") {
+		t.Fatalf("Decode with a gap in the chain returned %v, want a missing upcaster error", err)
+	}
+}
+
+func TestRegistryKeepsUnregisteredPayloadsRaw(t *testing.T) {
+	registry := NewRegistry()
+
+	decoded, err := registry.Decode("unknown.event", 7, []byte(`{"a":1}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if raw, ok := decoded.(json.RawMessage); !ok || string(raw) != `{"a":1}` {
+		t.Fatalf("Decode = %#v, want the raw payload", decoded)
+	}
+	if registry.CurrentVersion("unknown.event") != 1 {
+		t.Fatalf("CurrentVersion = %d, want 1", registry.CurrentVersion("unknown.event"))
+	}
+}
@@ -0,0 +1,49 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/domain"
+)
+
+// On registra um handler pelo tipo Go do evento. O handler é chamado quando o
+// próprio evento ou o seu payload (EventData) é do tipo E. Payloads ainda em
+// JSON são decodificados quando o tipo do evento está registrado como E.
+func On[E any](bus EventBus, handler func(ctx context.Context, event E) error, opts ...SubscribeOption) func() {
+	opts = append([]SubscribeOption{WithName(reflect.TypeFor[E]().String())}, opts...)
+
+	return bus.Subscribe("*", func(ctx context.Context, event domain.DomainEvent) error {
+		if typed, ok := event.(E); ok {
+			return handler(ctx, typed)
+		}
+		switch data := event.GetEventData().(type) {
+		case E:
+			return handler(ctx, data)
+		case json.RawMessage:
+			typed, ok, err := decodeAs[E](DefaultRegistry(), event.GetEventType(), data)
+			if err != nil || !ok {
+				return err
+			}
+			return handler(ctx, typed)
+		}
+		return nil
+	}, opts...)
+}
+
+// decodeAs trata o payload como da versão atual, já que o evento não carrega a versão
+func decodeAs[E any](registry *Registry, eventType string, payload json.RawMessage) (E, bool, error) {
+	var zero E
+	goType, ok := registry.goType(eventType)
+	if !ok || goType != reflect.TypeFor[E]() {
+		return zero, false, nil
+	}
+
+	var typed E
+	if err := json.Unmarshal(payload, &typed); err != nil {
+		return zero, false, fmt.Errorf("failed to decode %s: %w", eventType, err)
+	}
+	return typed, true, nil
+}
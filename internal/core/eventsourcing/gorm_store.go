@@ -0,0 +1,70 @@
+package eventsourcing
+
+import (
+	"context"
+	"errors"
+
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/database"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/domain"
+	apperrors "github.com/williamkoller/multi-tenant-nexus-manager/internal/core/errors"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/repository"
+	"gorm.io/gorm"
+)
+
+type GormEventStore struct {
+	db        *gorm.DB
+	txManager database.TxManager
+}
+
+var _ EventStore = (*GormEventStore)(nil)
+
+func NewGormEventStore(db *gorm.DB, txManager database.TxManager) *GormEventStore {
+	return &GormEventStore{db: db, txManager: txManager}
+}
+
+func (s *GormEventStore) Append(ctx context.Context, streamType, streamID string, expectedVersion int64, events []domain.DomainEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	return s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		tx := database.GetTxFromContext(ctx, s.db)
+
+		var current int64
+		err := tx.Model(&StoredEvent{}).
+			Where("stream_id = ?", streamID).
+			Select("COALESCE(MAX(version), 0)").
+			Scan(&current).Error
+		if err != nil {
+			return err
+		}
+		if current != expectedVersion {
+			return concurrencyError(streamID, expectedVersion, current)
+		}
+
+		stored, err := newStoredEvents("", streamType, streamID, expectedVersion, events)
+		if err != nil {
+			return err
+		}
+
+		// A unique (stream_id, version) cobre appends concorrentes entre a leitura e o insert
+		err = repository.TranslateError(tx.Create(&stored).Error)
+		var appErr apperrors.AppError
+		if errors.As(err, &appErr) && appErr.Code == apperrors.ErrConflict.Code {
+			return concurrencyError(streamID, expectedVersion, expectedVersion+1)
+		}
+		return err
+	})
+}
+
+func (s *GormEventStore) Load(ctx context.Context, streamID string, afterVersion int64) ([]StoredEvent, error) {
+	var events []StoredEvent
+	err := database.GetTxFromContext(ctx, s.db).
+		Where("stream_id = ? AND version > ?", streamID, afterVersion).
+		Order("version").
+		Find(&events).Error
+	if err != nil {
+		return nil, repository.TranslateError(err)
+	}
+	return events, nil
+}
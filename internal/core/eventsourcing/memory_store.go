@@ -0,0 +1,61 @@
+package eventsourcing
+
+import (
+	"context"
+	"sync"
+
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/domain"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/tenancy"
+)
+
+type InMemoryEventStore struct {
+	mu       sync.RWMutex
+	streams  map[string][]StoredEvent
+	position int64
+}
+
+var _ EventStore = (*InMemoryEventStore)(nil)
+
+func NewInMemoryEventStore() *InMemoryEventStore {
+	return &InMemoryEventStore{streams: make(map[string][]StoredEvent)}
+}
+
+func (s *InMemoryEventStore) Append(ctx context.Context, streamType, streamID string, expectedVersion int64, events []domain.DomainEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current := int64(len(s.streams[streamID]))
+	if current != expectedVersion {
+		return concurrencyError(streamID, expectedVersion, current)
+	}
+
+	tenantID, _ := tenancy.TenantIDFromContext(ctx)
+	stored, err := newStoredEvents(tenantID, streamType, streamID, expectedVersion, events)
+	if err != nil {
+		return err
+	}
+	for i := range stored {
+		s.position++
+		stored[i].Position = s.position
+	}
+	s.streams[streamID] = append(s.streams[streamID], stored...)
+	return nil
+}
+
+func (s *InMemoryEventStore) Load(ctx context.Context, streamID string, afterVersion int64) ([]StoredEvent, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	tenantID, scoped := tenancy.TenantIDFromContext(ctx)
+	var events []StoredEvent
+	for _, event := range s.streams[streamID] {
+		if event.Version <= afterVersion {
+			continue
+		}
+		if scoped && event.TenantID != tenantID {
+			continue
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
@@ -0,0 +1,196 @@
+package eventsourcing
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/domain"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/errors"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/outbox"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/tenancy"
+)
+
+// Aggregate é um agregado reconstruído a partir dos seus eventos. Apply deve
+// apenas mutar o estado (incluindo o ID no evento de criação), sem validar
+// regras nem gerar novos eventos.
+type Aggregate interface {
+	domain.AggregateRoot
+	SetVersion(version int64)
+	Apply(event domain.DomainEvent) error
+}
+
+// Raise aplica o evento ao estado do agregado e o registra como pendente
+func Raise(aggregate Aggregate, event domain.DomainEvent) error {
+	if err := aggregate.Apply(event); err != nil {
+		return err
+	}
+	aggregate.RaiseDomainEvent(event)
+	return nil
+}
+
+type Repository[T Aggregate] struct {
+	store      EventStore
+	streamType string
+	factory    func() T
+	outbox     *outbox.Outbox
+	snapshots  SnapshotStore
+	policy     SnapshotPolicy
+}
+
+func NewRepository[T Aggregate](store EventStore, streamType string, factory func() T) *Repository[T] {
+	return &Repository[T]{store: store, streamType: streamType, factory: factory}
+}
+
+// WithOutbox grava os eventos também no outbox, na mesma transação do append
+func (r *Repository[T]) WithOutbox(o *outbox.Outbox) *Repository[T] {
+	r.outbox = o
+	return r
+}
+
+// WithSnapshots ativa snapshots para agregados que implementam Snapshotter
+func (r *Repository[T]) WithSnapshots(store SnapshotStore, policy SnapshotPolicy) *Repository[T] {
+	r.snapshots = store
+	r.policy = policy
+	return r
+}
+
+func (r *Repository[T]) Save(ctx context.Context, aggregate T) error {
+	events := aggregate.GetDomainEvents()
+	if len(events) == 0 {
+		return nil
+	}
+	expected := aggregate.GetVersion()
+
+	appendEvents := func(ctx context.Context) error {
+		return r.store.Append(ctx, r.streamType, aggregate.GetID(), expected, events)
+	}
+
+	if r.outbox != nil {
+		if err := r.outbox.Save(ctx, aggregate, appendEvents); err != nil {
+			return err
+		}
+	} else {
+		if err := appendEvents(ctx); err != nil {
+			return err
+		}
+		aggregate.ClearDomainEvents()
+	}
+
+	aggregate.SetVersion(expected + int64(len(events)))
+	r.snapshotIfDue(ctx, aggregate)
+	return nil
+}
+
+// snapshotIfDue é uma otimização: falhas são registradas e não afetam o Save
+func (r *Repository[T]) snapshotIfDue(ctx context.Context, aggregate T) {
+	snapshotter, ok := any(aggregate).(Snapshotter)
+	if !ok || r.snapshots == nil || r.policy == nil {
+		return
+	}
+
+	last, hasLast, err := r.snapshots.Latest(ctx, aggregate.GetID())
+	if err != nil {
+		log.Printf("eventsourcing: failed to read snapshot of %s: %v", aggregate.GetID(), err)
+		return
+	}
+	if hasLast && last.SchemaVersion != snapshotter.SnapshotSchemaVersion() {
+		hasLast = false
+		last = Snapshot{}
+	}
+	if !r.policy.ShouldSnapshot(last, hasLast, aggregate.GetVersion()) {
+		return
+	}
+
+	state, err := snapshotter.SnapshotState()
+	if err != nil {
+		log.Printf("eventsourcing: failed to serialize snapshot of %s: %v", aggregate.GetID(), err)
+		return
+	}
+
+	tenantID, _ := tenancy.TenantIDFromContext(ctx)
+	err = r.snapshots.Save(ctx, Snapshot{
+		StreamID:      aggregate.GetID(),
+		StreamType:    r.streamType,
+		TenantID:      tenantID,
+		Version:       aggregate.GetVersion(),
+		SchemaVersion: snapshotter.SnapshotSchemaVersion(),
+		State:         state,
+		TakenAt:       time.Now().UTC(),
+	})
+	if err != nil {
+		log.Printf("eventsourcing: failed to save snapshot of %s: %v", aggregate.GetID(), err)
+	}
+}
+
+func (r *Repository[T]) FindByID(ctx context.Context, id string) (T, error) {
+	var zero T
+	aggregate := r.factory()
+
+	restored, err := r.restoreSnapshot(ctx, aggregate, id)
+	if err != nil {
+		return zero, err
+	}
+
+	stored, err := r.store.Load(ctx, id, aggregate.GetVersion())
+	if err != nil {
+		return zero, err
+	}
+	if len(stored) == 0 && !restored {
+		return zero, errors.ErrNotFound
+	}
+
+	if err := replay(aggregate, stored); err != nil {
+		return zero, err
+	}
+	return aggregate, nil
+}
+
+// restoreSnapshot carrega o snapshot mais recente compatível; snapshots de
+// outro SchemaVersion são descartados e o stream é reproduzido por completo.
+func (r *Repository[T]) restoreSnapshot(ctx context.Context, aggregate T, id string) (bool, error) {
+	snapshotter, ok := any(aggregate).(Snapshotter)
+	if !ok || r.snapshots == nil {
+		return false, nil
+	}
+
+	snapshot, found, err := r.snapshots.Latest(ctx, id)
+	if err != nil || !found {
+		return false, err
+	}
+
+	if snapshot.SchemaVersion != snapshotter.SnapshotSchemaVersion() {
+		if err := r.snapshots.Delete(ctx, id); err != nil {
+			log.Printf("eventsourcing: failed to discard outdated snapshot of %s: %v", id, err)
+		}
+		return false, nil
+	}
+
+	if err := snapshotter.RestoreSnapshot(snapshot.State); err != nil {
+		return false, err
+	}
+	aggregate.SetVersion(snapshot.Version)
+	return true, nil
+}
+
+func (r *Repository[T]) Exists(ctx context.Context, id string) (bool, error) {
+	stored, err := r.store.Load(ctx, id, 0)
+	if err != nil {
+		return false, err
+	}
+	return len(stored) > 0, nil
+}
+
+func replay(aggregate Aggregate, stored []StoredEvent) error {
+	for _, storedEvent := range stored {
+		event, err := storedEvent.Event()
+		if err != nil {
+			return err
+		}
+		if err := aggregate.Apply(event); err != nil {
+			return err
+		}
+		aggregate.SetVersion(storedEvent.Version)
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package eventsourcing
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"testing"
+
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/domain"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/errors"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/events"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/tenancy"
+)
+
+func TestRepositoryRebuildsAggregateFromEvents(t *testing.T) {
+	store := NewInMemoryEventStore()
+	repo := newAccountRepository(store)
+	ctx := context.Background()
+
+	opened := openAccount(t, "acc-1", "ana")
+	deposit(t, opened, 100)
+	if err := repo.Save(ctx, opened); err != nil {
+		t.Fatalf("Save returned %v", err)
+	}
+	if opened.GetVersion() != 2 || len(opened.GetDomainEvents()) != 0 {
+		t.Fatalf("after Save: version %d, pending events %d", opened.GetVersion(), len(opened.GetDomainEvents()))
+	}
+
+	deposit(t, opened, 50)
+	if err := repo.Save(ctx, opened); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := repo.FindByID(ctx, "acc-1")
+	if err != nil {
+		t.Fatalf("FindByID returned %v", err)
+	}
+	if loaded.GetID() != "acc-1" || loaded.Owner != "ana" || loaded.Balance != 150 || loaded.GetVersion() != 3 {
+		t.Fatalf("loaded %+v at version %d", loaded, loaded.GetVersion())
+	}
+
+	stored, err := store.Load(ctx, "acc-1", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, event := range stored {
+		if event.Version != int64(i+1) || event.StreamType != "account" || event.SchemaVersion != 1 {
+			t.Fatalf("stored event %d = %+v", i, event)
+		}
+	}
+
+	if _, err := repo.FindByID(ctx, "missing"); err != errors.ErrNotFound {
+		t.Fatalf("FindByID of an empty stream returned %v, want NOT_FOUND", err)
+	}
+}
+
+func TestRepositoryRejectsConcurrentAppends(t *testing.T) {
+	repo := newAccountRepository(NewInMemoryEventStore())
+	ctx := context.Background()
+
+	if err := repo.Save(ctx, openAccount(t, "acc-1", "ana")); err != nil {
+		t.Fatal(err)
+	}
+	first, err := repo.FindByID(ctx, "acc-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := repo.FindByID(ctx, "acc-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deposit(t, first, 10)
+	if err := repo.Save(ctx, first); err != nil {
+		t.Fatal(err)
+	}
+	deposit(t, second, 20)
+	err = repo.Save(ctx, second)
+	var appErr errors.AppError
+	if !stderrors.As(err, &appErr) || appErr.Code != errors.ErrConflict.Code {
+		t.Fatalf("Save of a stale copy returned %v, want CONFLICT", err)
+	}
+	if second.GetVersion() != 1 || len(second.GetDomainEvents()) != 1 {
+		t.Fatalf("failed Save changed the aggregate: version %d, pending %d", second.GetVersion(), len(second.GetDomainEvents()))
+	}
+}
+
+func TestInMemoryEventStoreScopesStreamsByTenant(t *testing.T) {
+	repo := newAccountRepository(NewInMemoryEventStore())
+	acme := tenancy.WithTenant(context.Background(), testTenant("acme"))
+	globex := tenancy.WithTenant(context.Background(), testTenant("globex"))
+
+	if err := repo.Save(acme, openAccount(t, "acc-1", "ana")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.FindByID(acme, "acc-1"); err != nil {
+		t.Fatalf("FindByID in the owning tenant returned %v", err)
+	}
+	if _, err := repo.FindByID(globex, "acc-1"); err != errors.ErrNotFound {
+		t.Fatalf("FindByID in another tenant returned %v, want NOT_FOUND", err)
+	}
+}
+
+// account é um agregado event-sourced mínimo; cada payload sabe se aplicar
+type account struct {
+	domain.BaseAggregateRoot
+	Owner   string `json:"owner"`
+	Balance int    `json:"balance"`
+}
+
+type accountChange interface {
+	applyTo(a *account, event domain.DomainEvent)
+}
+
+func (a *account) Apply(event domain.DomainEvent) error {
+	change, ok := event.GetEventData().(accountChange)
+	if !ok {
+		return fmt.Errorf("account: unexpected payload %T for %s", event.GetEventData(), event.GetEventType())
+	}
+	change.applyTo(a, event)
+	return nil
+}
+
+type accountOpened struct {
+	Owner string `json:"owner"`
+}
+
+func (e accountOpened) applyTo(a *account, event domain.DomainEvent) {
+	a.ID = event.GetAggregateID()
+	a.Owner = e.Owner
+}
+
+type accountDeposited struct {
+	Amount int `json:"amount"`
+}
+
+func (e accountDeposited) applyTo(a *account, _ domain.DomainEvent) {
+	a.Balance += e.Amount
+}
+
+func newAccountRepository(store EventStore) *Repository[*account] {
+	events.Register[accountOpened](events.DefaultRegistry(), "account.opened", 1)
+	events.Register[accountDeposited](events.DefaultRegistry(), "account.deposited", 1)
+	return NewRepository(store, "account", func() *account { return &account{} })
+}
+
+func openAccount(t *testing.T, id, owner string) *account {
+	t.Helper()
+	a := &account{}
+	if err := Raise(a, domain.NewBaseDomainEvent("account.opened", id, accountOpened{Owner: owner})); err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+func deposit(t *testing.T, a *account, amount int) {
+	t.Helper()
+	if err := Raise(a, domain.NewBaseDomainEvent("account.deposited", a.GetID(), accountDeposited{Amount: amount})); err != nil {
+		t.Fatal(err)
+	}
+}
+
+type testTenant string
+
+func (t testTenant) GetID() string                   { return string(t) }
+func (t testTenant) GetSlug() string                 { return string(t) }
+func (t testTenant) IsActive() bool                  { return true }
+func (t testTenant) GetIsolation() tenancy.Isolation { return tenancy.IsolationSharedTable }
+func (t testTenant) GetSchemaName() string           { return "" }
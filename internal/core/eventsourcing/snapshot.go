@@ -0,0 +1,152 @@
+package eventsourcing
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/database"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/repository"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/tenancy"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Snapshot guarda o estado serializado do agregado em uma versão do stream.
+// SchemaVersion identifica o formato do estado: snapshots de outro formato
+// são descartados e o agregado é reconstruído a partir dos eventos.
+type Snapshot struct {
+	StreamID      string    `json:"stream_id" gorm:"primaryKey"`
+	StreamType    string    `json:"stream_type" gorm:"index;not null"`
+	TenantID      string    `json:"tenant_id" gorm:"index"`
+	Version       int64     `json:"version" gorm:"not null"`
+	SchemaVersion int       `json:"schema_version" gorm:"not null"`
+	State         []byte    `json:"state" gorm:"not null"`
+	TakenAt       time.Time `json:"taken_at" gorm:"not null"`
+}
+
+func (Snapshot) TableName() string {
+	return "event_snapshots"
+}
+
+// Snapshotter é implementado pelos agregados que suportam snapshot
+type Snapshotter interface {
+	SnapshotSchemaVersion() int
+	SnapshotState() ([]byte, error)
+	RestoreSnapshot(state []byte) error
+}
+
+type SnapshotStore interface {
+	Save(ctx context.Context, snapshot Snapshot) error
+	Latest(ctx context.Context, streamID string) (Snapshot, bool, error)
+	Delete(ctx context.Context, streamID string) error
+}
+
+type SnapshotPolicy interface {
+	ShouldSnapshot(last Snapshot, hasLast bool, version int64) bool
+}
+
+// EveryNEvents tira um snapshot a cada N eventos desde o último
+type EveryNEvents int64
+
+func (n EveryNEvents) ShouldSnapshot(last Snapshot, hasLast bool, version int64) bool {
+	if n <= 0 {
+		return false
+	}
+	return version-last.Version >= int64(n)
+}
+
+// Interval tira um snapshot quando o último é mais antigo que a duração informada
+type Interval time.Duration
+
+func (d Interval) ShouldSnapshot(last Snapshot, hasLast bool, version int64) bool {
+	if !hasLast {
+		return version > 0
+	}
+	return version > last.Version && time.Since(last.TakenAt) >= time.Duration(d)
+}
+
+type InMemorySnapshotStore struct {
+	mu        sync.RWMutex
+	snapshots map[string]Snapshot
+}
+
+var _ SnapshotStore = (*InMemorySnapshotStore)(nil)
+
+func NewInMemorySnapshotStore() *InMemorySnapshotStore {
+	return &InMemorySnapshotStore{snapshots: make(map[string]Snapshot)}
+}
+
+func (s *InMemorySnapshotStore) Save(ctx context.Context, snapshot Snapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.snapshots[snapshot.StreamID] = snapshot
+	return nil
+}
+
+func (s *InMemorySnapshotStore) Latest(ctx context.Context, streamID string) (Snapshot, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	snapshot, ok := s.snapshots[streamID]
+	if tenantID, scoped := tenancy.TenantIDFromContext(ctx); ok && scoped && snapshot.TenantID != tenantID {
+		return Snapshot{}, false, nil
+	}
+	return snapshot, ok, nil
+}
+
+func (s *InMemorySnapshotStore) Delete(ctx context.Context, streamID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.snapshots, streamID)
+	return nil
+}
+
+type GormSnapshotStore struct {
+	db *gorm.DB
+}
+
+var _ SnapshotStore = (*GormSnapshotStore)(nil)
+
+func NewGormSnapshotStore(db *gorm.DB) *GormSnapshotStore {
+	return &GormSnapshotStore{db: db}
+}
+
+func (s *GormSnapshotStore) Save(ctx context.Context, snapshot Snapshot) error {
+	err := database.GetTxFromContext(ctx, s.db).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "stream_id"}}, UpdateAll: true}).
+		Create(&snapshot).Error
+	return repository.TranslateError(err)
+}
+
+func (s *GormSnapshotStore) Latest(ctx context.Context, streamID string) (Snapshot, bool, error) {
+	var snapshots []Snapshot
+	err := database.GetTxFromContext(ctx, s.db).
+		Where("stream_id = ?", streamID).
+		Limit(1).
+		Find(&snapshots).Error
+	if err != nil {
+		return Snapshot{}, false, repository.TranslateError(err)
+	}
+	if len(snapshots) == 0 {
+		return Snapshot{}, false, nil
+	}
+	return snapshots[0], true, nil
+}
+
+func (s *GormSnapshotStore) Delete(ctx context.Context, streamID string) error {
+	err := database.GetTxFromContext(ctx, s.db).
+		Where("stream_id = ?", streamID).
+		Delete(&Snapshot{}).Error
+	return repository.TranslateError(err)
+}
+
+// DiscardOutdated remove snapshots de um tipo de stream gerados com outro
+// formato de estado. Snapshots de tenants isolados ficam no schema de cada um,
+// então a limpeza de todos os tenants deve passar por database.ForEachPartition.
+func (s *GormSnapshotStore) DiscardOutdated(ctx context.Context, streamType string, schemaVersion int) (int64, error) {
+	result := database.GetTxFromContext(ctx, s.db).
+		Where("stream_type = ? AND schema_version <> ?", streamType, schemaVersion).
+		Delete(&Snapshot{})
+	return result.RowsAffected, repository.TranslateError(result.Error)
+}
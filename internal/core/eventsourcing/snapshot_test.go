@@ -0,0 +1,112 @@
+package eventsourcing
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func (a *account) SnapshotSchemaVersion() int { return 1 }
+
+func (a *account) SnapshotState() ([]byte, error) {
+	return json.Marshal(a)
+}
+
+func (a *account) RestoreSnapshot(state []byte) error {
+	return json.Unmarshal(state, a)
+}
+
+func TestRepositoryReplaysTailAfterSnapshot(t *testing.T) {
+	snapshots := NewInMemorySnapshotStore()
+	repo := newAccountRepository(NewInMemoryEventStore()).WithSnapshots(snapshots, EveryNEvents(3))
+	ctx := context.Background()
+
+	// Um evento por Save: o snapshot sai na versão 3 e os dois seguintes ficam na cauda
+	acc := openAccount(t, "acc-1", "ana")
+	if err := repo.Save(ctx, acc); err != nil {
+		t.Fatal(err)
+	}
+	for _, amount := range []int{10, 20, 30, 40} {
+		deposit(t, acc, amount)
+		if err := repo.Save(ctx, acc); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	snapshot, found, err := snapshots.Latest(ctx, "acc-1")
+	if err != nil || !found {
+		t.Fatalf("Latest = %v, %v", found, err)
+	}
+	if snapshot.Version != 3 || snapshot.StreamType != "account" {
+		t.Fatalf("snapshot = %+v, want version 3", snapshot)
+	}
+
+	// Marca o estado do snapshot para provar que os eventos até a versão 3 não são reproduzidos
+	var state account
+	if err := json.Unmarshal(snapshot.State, &state); err != nil {
+		t.Fatal(err)
+	}
+	state.Balance += 1000
+	snapshot.State, _ = json.Marshal(&state)
+	if err := snapshots.Save(ctx, snapshot); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := repo.FindByID(ctx, "acc-1")
+	if err != nil {
+		t.Fatalf("FindByID returned %v", err)
+	}
+	if loaded.Balance != 1000+10+20+30+40 || loaded.GetVersion() != 5 || loaded.Owner != "ana" {
+		t.Fatalf("loaded balance %d at version %d, want snapshot plus tail", loaded.Balance, loaded.GetVersion())
+	}
+}
+
+func TestRepositoryDiscardsOutdatedSnapshot(t *testing.T) {
+	snapshots := NewInMemorySnapshotStore()
+	repo := newAccountRepository(NewInMemoryEventStore()).WithSnapshots(snapshots, EveryNEvents(100))
+	ctx := context.Background()
+
+	acc := openAccount(t, "acc-1", "ana")
+	deposit(t, acc, 10)
+	if err := repo.Save(ctx, acc); err != nil {
+		t.Fatal(err)
+	}
+
+	// Snapshot de um formato antigo do estado é ignorado e apagado
+	err := snapshots.Save(ctx, Snapshot{StreamID: "acc-1", Version: 2, SchemaVersion: 0, State: []byte(`{"balance":999}`)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := repo.FindByID(ctx, "acc-1")
+	if err != nil {
+		t.Fatalf("FindByID returned %v", err)
+	}
+	if loaded.Balance != 10 || loaded.GetVersion() != 2 {
+		t.Fatalf("loaded balance %d at version %d, want a full replay", loaded.Balance, loaded.GetVersion())
+	}
+	if _, found, _ := snapshots.Latest(ctx, "acc-1"); found {
+		t.Fatal("outdated snapshot was not discarded")
+	}
+}
+
+func TestSnapshotPolicies(t *testing.T) {
+	every := EveryNEvents(3)
+	if every.ShouldSnapshot(Snapshot{}, false, 2) || !every.ShouldSnapshot(Snapshot{}, false, 3) {
+		t.Error("EveryNEvents(3) without a snapshot must trigger at version 3")
+	}
+	if every.ShouldSnapshot(Snapshot{Version: 3}, true, 5) || !every.ShouldSnapshot(Snapshot{Version: 3}, true, 6) {
+		t.Error("EveryNEvents(3) must count from the last snapshot")
+	}
+
+	interval := Interval(time.Hour)
+	if !interval.ShouldSnapshot(Snapshot{}, false, 1) {
+		t.Error("Interval without a snapshot must trigger")
+	}
+	recent := Snapshot{Version: 1, TakenAt: time.Now()}
+	old := Snapshot{Version: 1, TakenAt: time.Now().Add(-2 * time.Hour)}
+	if interval.ShouldSnapshot(recent, true, 5) || !interval.ShouldSnapshot(old, true, 5) || interval.ShouldSnapshot(old, true, 1) {
+		t.Error("Interval must trigger only for new events after the duration")
+	}
+}
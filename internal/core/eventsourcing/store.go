@@ -0,0 +1,92 @@
+package eventsourcing
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/domain"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/errors"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/events"
+)
+
+type StoredEvent struct {
+	ID string `json:"id" gorm:"primaryKey;type:uuid"`
+	// Position ordena todos os streams; com TxID forma o checkpoint dos replays
+	Position   int64  `json:"position" gorm:"autoIncrement;uniqueIndex;index:idx_event_store_cursor,priority:2;not null"`
+	TxID       int64  `json:"tx_id" gorm:"index:idx_event_store_cursor,priority:1;not null;default:(pg_current_xact_id()::text::bigint)"`
+	StreamID   string `json:"stream_id" gorm:"uniqueIndex:idx_event_store_stream_version;not null"`
+	StreamType string `json:"stream_type" gorm:"index;not null"`
+	Version    int64  `json:"version" gorm:"uniqueIndex:idx_event_store_stream_version;not null"`
+	TenantID   string `json:"tenant_id" gorm:"index"`
+	EventType  string `json:"event_type" gorm:"index;not null"`
+	Payload    string `json:"payload" gorm:"type:jsonb;not null"`
+	// SchemaVersion é a versão do payload no momento da gravação
+	SchemaVersion int       `json:"schema_version" gorm:"not null;default:1"`
+	OccurredAt    time.Time `json:"occurred_at" gorm:"not null"`
+	RecordedAt    time.Time `json:"recorded_at" gorm:"not null"`
+}
+
+func (StoredEvent) TableName() string {
+	return "event_store"
+}
+
+// Event reconstrói o domain event com o payload atualizado para a versão atual
+func (e StoredEvent) Event() (domain.DomainEvent, error) {
+	data, err := events.DefaultRegistry().Decode(e.EventType, e.SchemaVersion, []byte(e.Payload))
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.BaseDomainEvent{
+		EventID:     e.ID,
+		EventType:   e.EventType,
+		AggregateID: e.StreamID,
+		OccurredOn:  e.OccurredAt.UTC().Format(time.RFC3339),
+		EventData:   data,
+	}, nil
+}
+
+// EventStore guarda streams append-only por agregado. Append falha com
+// CONFLICT quando a versão atual do stream difere de expectedVersion.
+type EventStore interface {
+	Append(ctx context.Context, streamType, streamID string, expectedVersion int64, events []domain.DomainEvent) error
+	Load(ctx context.Context, streamID string, afterVersion int64) ([]StoredEvent, error)
+}
+
+func newStoredEvents(tenantID, streamType, streamID string, expectedVersion int64, domainEvents []domain.DomainEvent) ([]StoredEvent, error) {
+	now := time.Now().UTC()
+	stored := make([]StoredEvent, 0, len(domainEvents))
+	for i, event := range domainEvents {
+		payload, schemaVersion, err := events.DefaultRegistry().Encode(event)
+		if err != nil {
+			return nil, err
+		}
+		occurredAt, err := time.Parse(time.RFC3339, event.GetOccurredOn())
+		if err != nil {
+			occurredAt = now
+		}
+
+		stored = append(stored, StoredEvent{
+			ID:            event.GetEventID(),
+			StreamID:      streamID,
+			StreamType:    streamType,
+			Version:       expectedVersion + int64(i) + 1,
+			TenantID:      tenantID,
+			EventType:     event.GetEventType(),
+			Payload:       string(payload),
+			SchemaVersion: schemaVersion,
+			OccurredAt:    occurredAt,
+			RecordedAt:    now,
+		})
+	}
+	return stored, nil
+}
+
+func concurrencyError(streamID string, expected, actual int64) error {
+	return errors.NewAppErrorWithDetails(
+		errors.ErrConflict.Code,
+		errors.ErrConflict.Message,
+		fmt.Sprintf("stream %s expected at version %d but is at %d", streamID, expected, actual),
+	)
+}
@@ -0,0 +1,72 @@
+package eventsourcing
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/domain"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/events"
+)
+
+// accountCharged v2 guarda o valor em reais; a v1 gravava centavos
+type accountCharged struct {
+	Amount int `json:"amount"`
+}
+
+func (e accountCharged) applyTo(a *account, _ domain.DomainEvent) {
+	a.Balance -= e.Amount
+}
+
+func TestRepositoryUpcastsStoredEventsOnReplay(t *testing.T) {
+	events.Register[accountCharged](events.DefaultRegistry(), "account.charged", 2)
+	events.DefaultRegistry().RegisterUpcaster("account.charged", 1, func(payload json.RawMessage) (json.RawMessage, error) {
+		var v1 struct {
+			Cents int `json:"cents"`
+		}
+		if err := json.Unmarshal(payload, &v1); err != nil {
+			return nil, err
+		}
+		return json.Marshal(accountCharged{Amount: v1.Cents / 100})
+	})
+
+	store := NewInMemoryEventStore()
+	repo := newAccountRepository(store)
+	ctx := context.Background()
+
+	acc := openAccount(t, "acc-1", "ana")
+	deposit(t, acc, 100)
+	if err := repo.Save(ctx, acc); err != nil {
+		t.Fatal(err)
+	}
+
+	// Evento antigo, gravado antes da v2 do payload
+	store.streams["acc-1"] = append(store.streams["acc-1"], StoredEvent{
+		ID: "e3", StreamID: "acc-1", StreamType: "account", Version: 3,
+		EventType: "account.charged", Payload: `{"cents":2500}`, SchemaVersion: 1, OccurredAt: time.Now().UTC(),
+	})
+
+	loaded, err := repo.FindByID(ctx, "acc-1")
+	if err != nil {
+		t.Fatalf("FindByID returned %v", err)
+	}
+	if loaded.Balance != 75 || loaded.GetVersion() != 3 {
+		t.Fatalf("loaded balance %d at version %d, want 75 at 3", loaded.Balance, loaded.GetVersion())
+	}
+
+	// Eventos novos são gravados já na versão atual do schema
+	if err := Raise(loaded, domain.NewBaseDomainEvent("account.charged", "acc-1", accountCharged{Amount: 5})); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Save(ctx, loaded); err != nil {
+		t.Fatal(err)
+	}
+	stored, err := store.Load(ctx, "acc-1", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stored) != 1 || stored[0].SchemaVersion != 2 || stored[0].Payload != `{"amount":5}` {
+		t.Fatalf("appended %+v, want a v2 payload", stored)
+	}
+}
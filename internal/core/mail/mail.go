@@ -0,0 +1,78 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"net/smtp"
+	"strings"
+)
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Sender é o ponto de extensão para provedores de e-mail
+type Sender interface {
+	Send(ctx context.Context, message Message) error
+}
+
+// SenderFunc adapta uma função ao Sender
+type SenderFunc func(ctx context.Context, message Message) error
+
+func (f SenderFunc) Send(ctx context.Context, message Message) error {
+	return f(ctx, message)
+}
+
+// LogSender apenas registra as mensagens; útil em desenvolvimento, onde o
+// link do convite precisa ser copiado do log.
+type LogSender struct{}
+
+func (LogSender) Send(_ context.Context, message Message) error {
+	log.Printf("mail: to=%s subject=%q\n%s", message.To, message.Subject, message.Body)
+	return nil
+}
+
+type SMTPConfig struct {
+	// Addr no formato host:porta
+	Addr     string
+	Username string
+	Password string
+	From     string
+}
+
+type SMTPSender struct {
+	config SMTPConfig
+}
+
+func NewSMTPSender(config SMTPConfig) *SMTPSender {
+	return &SMTPSender{config: config}
+}
+
+func (s *SMTPSender) Send(_ context.Context, message Message) error {
+	if strings.ContainsAny(message.To, "\r\n") || strings.ContainsAny(message.Subject, "\r\n") {
+		return fmt.Errorf("mail: header values must not contain line breaks")
+	}
+
+	var auth smtp.Auth
+	if s.config.Username != "" {
+		host, _, err := net.SplitHostPort(s.config.Addr)
+		if err != nil {
+			return fmt.Errorf("mail: invalid smtp address: %w", err)
+		}
+		auth = smtp.PlainAuth("", s.config.Username, s.config.Password, host)
+	}
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "From: %s\r\n", s.config.From)
+	fmt.Fprintf(&body, "To: %s\r\n", message.To)
+	fmt.Fprintf(&body, "Subject: %s\r\n", message.Subject)
+	body.WriteString("MIME-Version: 1.0\r\n")
+	body.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	body.WriteString(message.Body)
+
+	return smtp.SendMail(s.config.Addr, auth, s.config.From, []string{message.To}, []byte(body.String()))
+}
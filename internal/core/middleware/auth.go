@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/auth"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/errors"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/response"
+)
+
+type AuthConfig struct {
+	Issuer *auth.TokenIssuer
+	// Optional deixa passar requisições sem Authorization; tokens inválidos continuam barrados
+	Optional bool
+}
+
+// AuthMiddleware valida o bearer token e coloca o auth.Principal no contexto da requisição.
+// Deve vir antes do TenantMiddleware, que usa PrincipalTenant como ClaimResolver.
+func AuthMiddleware(config AuthConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, ok := bearerToken(c.GetHeader("Authorization"))
+		if !ok {
+			if config.Optional {
+				c.Next()
+				return
+			}
+			c.Header("WWW-Authenticate", `Bearer`)
+			response.Error(c, errors.ErrUnauthorized)
+			c.Abort()
+			return
+		}
+
+		claims, err := config.Issuer.Verify(token)
+		if err != nil {
+			c.Header("WWW-Authenticate", `Bearer error="invalid_token"`)
+			response.Error(c, err)
+			c.Abort()
+			return
+		}
+
+		ctx := auth.WithPrincipal(c.Request.Context(), auth.PrincipalFromClaims(claims))
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// RequireRole barra principals sem nenhum dos papéis informados; vem depois do AuthMiddleware
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := auth.PrincipalFromContext(c.Request.Context())
+		if !ok {
+			c.Header("WWW-Authenticate", `Bearer`)
+			response.Error(c, errors.ErrUnauthorized)
+			c.Abort()
+			return
+		}
+
+		for _, role := range roles {
+			if principal.HasRole(role) {
+				c.Next()
+				return
+			}
+		}
+		response.Error(c, errors.ErrForbidden)
+		c.Abort()
+	}
+}
+
+// PrincipalTenant é o ClaimResolver do TenantConfig
+func PrincipalTenant(c *gin.Context) (string, bool) {
+	principal, ok := auth.PrincipalFromContext(c.Request.Context())
+	if !ok {
+		return "", false
+	}
+	return principal.TenantID, principal.TenantID != ""
+}
+
+func bearerToken(header string) (string, bool) {
+	scheme, token, found := strings.Cut(strings.TrimSpace(header), " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
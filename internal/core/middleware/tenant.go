@@ -0,0 +1,139 @@
+package middleware
+
+import (
+	stderrors "errors"
+	"net"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/errors"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/response"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/tenancy"
+)
+
+type TenantSource string
+
+const (
+	TenantFromSubdomain TenantSource = "subdomain"
+	TenantFromHeader    TenantSource = "header"
+	TenantFromPath      TenantSource = "path"
+	TenantFromJWTClaim  TenantSource = "jwt"
+)
+
+type TenantConfig struct {
+	Loader  tenancy.Loader
+	Sources []TenantSource
+	// BaseDomain é o domínio raiz usado para extrair o subdomínio (ex: "nexus.com.br")
+	BaseDomain string
+	Header     string
+	// PathPrefix é comparado com o caminho completo da requisição, inclusive o
+	// prefixo do grupo de rotas (ex: "/api/v1/t/")
+	PathPrefix string
+	// ClaimResolver devolve o tenant ID presente no token já validado da requisição
+	ClaimResolver func(c *gin.Context) (string, bool)
+}
+
+func DefaultTenantConfig(loader tenancy.Loader) TenantConfig {
+	return TenantConfig{
+		Loader:  loader,
+		Sources: []TenantSource{TenantFromJWTClaim, TenantFromHeader, TenantFromSubdomain},
+		Header:  "X-Tenant-ID",
+	}
+}
+
+func TenantMiddleware(config TenantConfig) gin.HandlerFunc {
+	if config.Header == "" {
+		config.Header = "X-Tenant-ID"
+	}
+
+	return func(c *gin.Context) {
+		tenant, err := resolveTenant(c, config)
+		if err != nil {
+			response.Error(c, err)
+			c.Abort()
+			return
+		}
+
+		if !tenant.IsActive() {
+			response.Error(c, errors.NewAppErrorWithDetails("FORBIDDEN", "Access forbidden", "tenant is not active"))
+			c.Abort()
+			return
+		}
+
+		ctx := tenancy.WithTenant(c.Request.Context(), tenant)
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+func resolveTenant(c *gin.Context, config TenantConfig) (tenancy.Tenant, error) {
+	ctx := c.Request.Context()
+
+	for _, source := range config.Sources {
+		switch source {
+		case TenantFromJWTClaim:
+			if config.ClaimResolver == nil {
+				continue
+			}
+			if id, ok := config.ClaimResolver(c); ok && id != "" {
+				return loadTenant(config.Loader.FindByID(ctx, id))
+			}
+		case TenantFromHeader:
+			if id := strings.TrimSpace(c.GetHeader(config.Header)); id != "" {
+				return loadTenant(config.Loader.FindByID(ctx, id))
+			}
+		case TenantFromSubdomain:
+			if slug := subdomain(c.Request.Host, config.BaseDomain); slug != "" {
+				return loadTenant(config.Loader.FindBySlug(ctx, slug))
+			}
+		case TenantFromPath:
+			if slug := pathSegment(c.Request.URL.Path, config.PathPrefix); slug != "" {
+				return loadTenant(config.Loader.FindBySlug(ctx, slug))
+			}
+		}
+	}
+
+	return nil, errors.NewAppErrorWithDetails("INVALID_INPUT", "Invalid input data", "tenant could not be identified")
+}
+
+// loadTenant responde 404 para um tenant identificado mas inexistente
+func loadTenant(tenant tenancy.Tenant, err error) (tenancy.Tenant, error) {
+	var appErr errors.AppError
+	if stderrors.As(err, &appErr) && appErr.Code == errors.ErrNotFound.Code {
+		return nil, errors.NewAppErrorWithDetails(errors.ErrNotFound.Code, errors.ErrNotFound.Message, "tenant not found")
+	}
+	return tenant, err
+}
+
+func subdomain(host, baseDomain string) string {
+	if baseDomain == "" {
+		return ""
+	}
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+
+	host = strings.ToLower(host)
+	suffix := "." + strings.ToLower(strings.TrimPrefix(baseDomain, "."))
+	if !strings.HasSuffix(host, suffix) {
+		return ""
+	}
+
+	sub := strings.TrimSuffix(host, suffix)
+	// Apenas o primeiro nível identifica o tenant (acme.nexus.com.br)
+	if sub == "" || strings.Contains(sub, ".") || sub == "www" {
+		return ""
+	}
+	return sub
+}
+
+func pathSegment(path, prefix string) string {
+	if prefix == "" || !strings.HasPrefix(path, prefix) {
+		return ""
+	}
+	rest := strings.TrimPrefix(path, prefix)
+	if i := strings.Index(rest, "/"); i >= 0 {
+		rest = rest[:i]
+	}
+	return rest
+}
@@ -0,0 +1,87 @@
+package outbox
+
+import (
+	"time"
+
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/domain"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/events"
+)
+
+type Status string
+
+const (
+	StatusPending   Status = "pending"
+	StatusDelivered Status = "delivered"
+	StatusFailed    Status = "failed"
+)
+
+type Message struct {
+	ID string `json:"id" gorm:"primaryKey;type:uuid"`
+	// Position é monotônica, mas só forma o cursor (Last-Event-ID) junto com
+	// TxID; veja database.Position
+	Position      int64      `json:"position" gorm:"autoIncrement;uniqueIndex;index:idx_outbox_messages_cursor,priority:2;not null"`
+	TxID          int64      `json:"tx_id" gorm:"index:idx_outbox_messages_cursor,priority:1;not null;default:(pg_current_xact_id()::text::bigint)"`
+	TenantID      string     `json:"tenant_id" gorm:"index"`
+	AggregateID   string     `json:"aggregate_id" gorm:"index;not null"`
+	EventType     string     `json:"event_type" gorm:"index;not null"`
+	Payload       string     `json:"payload" gorm:"type:jsonb;not null"`
+	SchemaVersion int        `json:"schema_version" gorm:"not null;default:1"`
+	OccurredAt    time.Time  `json:"occurred_at" gorm:"not null"`
+	Status        Status     `json:"status" gorm:"index;not null"`
+	Attempts      int        `json:"attempts" gorm:"not null;default:0"`
+	NextAttemptAt time.Time  `json:"next_attempt_at" gorm:"index;not null"`
+	LastError     string     `json:"last_error,omitempty"`
+	DeliveredAt   *time.Time `json:"delivered_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+func (Message) TableName() string {
+	return "outbox_messages"
+}
+
+// O outbox fica no schema public para que um único relay atenda todos os tenants
+func (Message) SharedTable() bool {
+	return true
+}
+
+func NewMessage(tenantID string, event domain.DomainEvent) (Message, error) {
+	payload, version, err := events.DefaultRegistry().Encode(event)
+	if err != nil {
+		return Message{}, err
+	}
+
+	occurredAt, err := time.Parse(time.RFC3339, event.GetOccurredOn())
+	if err != nil {
+		occurredAt = time.Now().UTC()
+	}
+
+	now := time.Now().UTC()
+	return Message{
+		ID:            event.GetEventID(),
+		TenantID:      tenantID,
+		AggregateID:   event.GetAggregateID(),
+		EventType:     event.GetEventType(),
+		Payload:       string(payload),
+		SchemaVersion: version,
+		OccurredAt:    occurredAt,
+		Status:        StatusPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}, nil
+}
+
+// Event reconstrói o domain event com o payload atualizado para a versão atual
+func (m Message) Event() (domain.DomainEvent, error) {
+	data, err := events.DefaultRegistry().Decode(m.EventType, m.SchemaVersion, []byte(m.Payload))
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.BaseDomainEvent{
+		EventID:     m.ID,
+		EventType:   m.EventType,
+		AggregateID: m.AggregateID,
+		OccurredOn:  m.OccurredAt.UTC().Format(time.RFC3339),
+		EventData:   data,
+	}, nil
+}
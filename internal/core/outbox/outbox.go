@@ -0,0 +1,75 @@
+package outbox
+
+import (
+	"context"
+
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/database"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/domain"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/errors"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/repository"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/tenancy"
+	"gorm.io/gorm"
+)
+
+type Outbox struct {
+	db        *gorm.DB
+	txManager database.TxManager
+}
+
+func New(db *gorm.DB, txManager database.TxManager) *Outbox {
+	return &Outbox{db: db, txManager: txManager}
+}
+
+// Record grava os eventos pendentes do agregado na transação corrente.
+// Os eventos não são limpos aqui: a transação ainda pode ser desfeita.
+func (o *Outbox) Record(ctx context.Context, aggregate domain.AggregateRoot) error {
+	if !database.InTx(ctx) {
+		return errors.NewAppErrorWithDetails(
+			errors.ErrInternalServer.Code,
+			errors.ErrInternalServer.Message,
+			"outbox records must be written inside a transaction",
+		)
+	}
+
+	events := aggregate.GetDomainEvents()
+	if len(events) == 0 {
+		return nil
+	}
+
+	tenantID, ok := tenancy.TenantIDFromContext(ctx)
+	if !ok {
+		// Eventos do próprio tenant (tenant.created, tenant.suspended...) pertencem a ele;
+		// sem tenant no contexto o plugin só aceita o tenant_id explícito em escopo de sistema
+		if tenant, isTenant := aggregate.(tenancy.Tenant); isTenant {
+			tenantID = tenant.GetID()
+			ctx = tenancy.WithSystemScope(ctx)
+		}
+	}
+
+	messages := make([]Message, 0, len(events))
+	for _, event := range events {
+		message, err := NewMessage(tenantID, event)
+		if err != nil {
+			return err
+		}
+		messages = append(messages, message)
+	}
+
+	return repository.TranslateError(database.GetTxFromContext(ctx, o.db).Create(&messages).Error)
+}
+
+// Save persiste o agregado e seus eventos atomicamente e só então limpa os eventos em memória
+func (o *Outbox) Save(ctx context.Context, aggregate domain.AggregateRoot, persist func(ctx context.Context) error) error {
+	err := o.txManager.WithTx(ctx, func(ctx context.Context) error {
+		if err := persist(ctx); err != nil {
+			return err
+		}
+		return o.Record(ctx, aggregate)
+	})
+	if err != nil {
+		return err
+	}
+
+	aggregate.ClearDomainEvents()
+	return nil
+}
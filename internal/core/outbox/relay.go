@@ -0,0 +1,179 @@
+package outbox
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/database"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/domain"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/tenancy"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Publisher interface {
+	Publish(ctx context.Context, event domain.DomainEvent) error
+}
+
+type RelayConfig struct {
+	BatchSize    int
+	PollInterval time.Duration
+	MaxAttempts  int
+	BaseBackoff  time.Duration
+	MaxBackoff   time.Duration
+}
+
+func DefaultRelayConfig() RelayConfig {
+	return RelayConfig{
+		BatchSize:    100,
+		PollInterval: time.Second,
+		MaxAttempts:  10,
+		BaseBackoff:  time.Second,
+		MaxBackoff:   10 * time.Minute,
+	}
+}
+
+// Relay publica as mensagens pendentes do outbox. Várias instâncias podem
+// rodar em paralelo: FOR UPDATE SKIP LOCKED evita entregas concorrentes.
+type Relay struct {
+	db        *gorm.DB
+	txManager *database.GormTxManager
+	publisher Publisher
+	loader    tenancy.Loader
+	config    RelayConfig
+}
+
+// NewRelay recebe um loader opcional para propagar o tenant aos subscribers
+func NewRelay(db *gorm.DB, publisher Publisher, loader tenancy.Loader, config RelayConfig) *Relay {
+	defaults := DefaultRelayConfig()
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaults.BatchSize
+	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = defaults.PollInterval
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = defaults.MaxAttempts
+	}
+	if config.BaseBackoff <= 0 {
+		config.BaseBackoff = defaults.BaseBackoff
+	}
+	if config.MaxBackoff <= 0 {
+		config.MaxBackoff = defaults.MaxBackoff
+	}
+
+	return &Relay{
+		db:        db,
+		txManager: database.NewTxManager(db),
+		publisher: publisher,
+		loader:    loader,
+		config:    config,
+	}
+}
+
+func (r *Relay) Run(ctx context.Context) {
+	for {
+		processed, err := r.ProcessBatch(ctx)
+		if err != nil {
+			log.Printf("outbox relay: %v", err)
+		}
+
+		// Lote cheio indica backlog: continua sem esperar
+		if err == nil && processed == r.config.BatchSize {
+			if ctx.Err() != nil {
+				return
+			}
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(r.config.PollInterval):
+		}
+	}
+}
+
+func (r *Relay) ProcessBatch(ctx context.Context) (int, error) {
+	processed := 0
+	systemCtx := tenancy.WithSystemScope(ctx)
+
+	// A transação de sistema também libera as policies de RLS do outbox
+	err := r.txManager.WithTx(systemCtx, func(txCtx context.Context) error {
+		tx := database.GetTxFromContext(txCtx, r.db)
+
+		var messages []Message
+		err := tx.
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", StatusPending, time.Now().UTC()).
+			Order("created_at").
+			Limit(r.config.BatchSize).
+			Find(&messages).Error
+		if err != nil {
+			return err
+		}
+
+		for i := range messages {
+			message := &messages[i]
+			r.deliver(ctx, message)
+			if err := tx.Save(message).Error; err != nil {
+				return err
+			}
+			processed++
+		}
+		return nil
+	})
+
+	return processed, err
+}
+
+func (r *Relay) deliver(ctx context.Context, message *Message) {
+	publishCtx := r.tenantContext(ctx, message.TenantID)
+
+	event, err := message.Event()
+	if err == nil {
+		err = r.publisher.Publish(publishCtx, event)
+	}
+	now := time.Now().UTC()
+	if err == nil {
+		message.Status = StatusDelivered
+		message.DeliveredAt = &now
+		message.LastError = ""
+		return
+	}
+
+	message.Attempts++
+	message.LastError = err.Error()
+	if message.Attempts >= r.config.MaxAttempts {
+		message.Status = StatusFailed
+		log.Printf("outbox relay: giving up on message %s (%s) after %d attempts: %v",
+			message.ID, message.EventType, message.Attempts, err)
+		return
+	}
+	message.NextAttemptAt = now.Add(r.backoff(message.Attempts))
+}
+
+func (r *Relay) backoff(attempts int) time.Duration {
+	delay := r.config.BaseBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= r.config.MaxBackoff {
+			return r.config.MaxBackoff
+		}
+	}
+	return delay
+}
+
+func (r *Relay) tenantContext(ctx context.Context, tenantID string) context.Context {
+	if tenantID == "" || r.loader == nil {
+		return ctx
+	}
+
+	tenant, err := r.loader.FindByID(tenancy.WithSystemScope(ctx), tenantID)
+	if err != nil {
+		log.Printf("outbox relay: failed to load tenant %s: %v", tenantID, err)
+		return ctx
+	}
+	return tenancy.WithTenant(ctx, tenant)
+}
@@ -0,0 +1,193 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	stderrors "errors"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/domain"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/events"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/tenancy"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type invoicePaid struct {
+	Amount int `json:"amount"`
+}
+
+func TestRelayClaimsPendingMessagesAndPublishes(t *testing.T) {
+	events.Register[invoicePaid](events.DefaultRegistry(), "invoice.paid", 1)
+
+	now := time.Now().UTC()
+	conn := &fakeConn{rows: [][]driver.Value{
+		{"m1", "tenant-1", "inv-1", "invoice.paid", `{"amount":10}`, int64(1), now, string(StatusPending), int64(0), now, now},
+		{"m2", "tenant-1", "inv-2", "invoice.voided", `{}`, int64(1), now, string(StatusPending), int64(0), now, now},
+	}}
+	db := newFakeDB(t, conn)
+
+	bus := events.NewInMemoryEventBus()
+	var paid []invoicePaid
+	var tenants []string
+	events.On(bus, func(ctx context.Context, event invoicePaid) error {
+		paid = append(paid, event)
+		tenantID, _ := tenancy.TenantIDFromContext(ctx)
+		tenants = append(tenants, tenantID)
+		return nil
+	})
+	bus.Subscribe("invoice.voided", func(ctx context.Context, event domain.DomainEvent) error {
+		return stderrors.New("ledger unavailable")
+	})
+
+	relay := NewRelay(db, bus, fakeLoader{}, RelayConfig{BatchSize: 50, BaseBackoff: time.Minute})
+	processed, err := relay.ProcessBatch(context.Background())
+	if err != nil {
+		t.Fatalf("ProcessBatch returned %v", err)
+	}
+	if processed != 2 {
+		t.Fatalf("processed = %d, want 2", processed)
+	}
+
+	claim := `SELECT * FROM "outbox_messages" WHERE status = $1 AND next_attempt_at <= $2 ORDER BY created_at LIMIT $3 FOR UPDATE SKIP LOCKED`
+	if conn.queries[0] != claim {
+		t.Fatalf("claim query = %s\nwant          %s", conn.queries[0], claim)
+	}
+	if !slices.Equal(paid, []invoicePaid{{Amount: 10}}) || !slices.Equal(tenants, []string{"tenant-1"}) {
+		t.Fatalf("subscriber received %v in tenants %v", paid, tenants)
+	}
+
+	// Cada mensagem é gravada na mesma transação do claim, antes do commit
+	if len(conn.updates) != 2 || !conn.committed {
+		t.Fatalf("updates = %d, committed = %v", len(conn.updates), conn.committed)
+	}
+	if delivered := conn.updates[0]; !slices.Contains(delivered, driver.Value(string(StatusDelivered))) {
+		t.Errorf("first message saved with %v, want delivered", delivered)
+	}
+	retried := conn.updates[1]
+	if !slices.Contains(retried, driver.Value(string(StatusPending))) || !slices.Contains(retried, driver.Value(int64(1))) ||
+		!slices.ContainsFunc(retried, func(v driver.Value) bool { s, ok := v.(string); return ok && strings.Contains(s, "ledger unavailable") }) {
+		t.Errorf("failed message saved with %v, want pending with one attempt and the error", retried)
+	}
+}
+
+func TestRelayBackoffIsCapped(t *testing.T) {
+	relay := NewRelay(nil, events.NewInMemoryEventBus(), nil, RelayConfig{BaseBackoff: time.Second, MaxBackoff: 5 * time.Second})
+
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for i, expected := range want {
+		if got := relay.backoff(i + 1); got != expected {
+			t.Errorf("backoff(%d) = %s, want %s", i+1, got, expected)
+		}
+	}
+}
+
+type fakeLoader struct{}
+
+func (fakeLoader) FindByID(_ context.Context, id string) (tenancy.Tenant, error) {
+	return fakeTenant(id), nil
+}
+
+func (fakeLoader) FindBySlug(_ context.Context, slug string) (tenancy.Tenant, error) {
+	return fakeTenant(slug), nil
+}
+
+type fakeTenant string
+
+func (t fakeTenant) GetID() string                   { return string(t) }
+func (t fakeTenant) GetSlug() string                 { return string(t) }
+func (t fakeTenant) IsActive() bool                  { return true }
+func (t fakeTenant) GetIsolation() tenancy.Isolation { return tenancy.IsolationSharedTable }
+func (t fakeTenant) GetSchemaName() string           { return "" }
+
+func newFakeDB(t *testing.T, conn *fakeConn) *gorm.DB {
+	t.Helper()
+	sqlDB := sql.OpenDB(fakeConnector{conn: conn})
+	t.Cleanup(func() { sqlDB.Close() })
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// fakeConn é um driver database/sql mínimo: devolve as linhas configuradas
+// para o SELECT do claim e grava os argumentos de cada UPDATE.
+type fakeConn struct {
+	mu        sync.Mutex
+	rows      [][]driver.Value
+	queries   []string
+	updates   [][]driver.Value
+	committed bool
+}
+
+var messageColumns = []string{
+	"id", "tenant_id", "aggregate_id", "event_type", "payload", "schema_version",
+	"occurred_at", "status", "attempts", "next_attempt_at", "created_at",
+}
+
+func (c *fakeConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.queries = append(c.queries, query)
+	return &fakeRows{values: c.rows}, nil
+}
+
+func (c *fakeConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if strings.HasPrefix(query, `UPDATE "outbox_messages"`) {
+		values := make([]driver.Value, len(args))
+		for i, arg := range args {
+			values[i] = arg.Value
+		}
+		c.updates = append(c.updates, values)
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (c *fakeConn) BeginTx(context.Context, driver.TxOptions) (driver.Tx, error) { return c, nil }
+func (c *fakeConn) Begin() (driver.Tx, error)                                    { return c, nil }
+func (c *fakeConn) Commit() error                                                { c.committed = true; return nil }
+func (c *fakeConn) Rollback() error                                              { return nil }
+func (c *fakeConn) Close() error                                                 { return nil }
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) {
+	return nil, stderrors.New("fake driver does not prepare statements")
+}
+
+type fakeRows struct {
+	values [][]driver.Value
+	next   int
+}
+
+func (r *fakeRows) Columns() []string { return messageColumns }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.next >= len(r.values) {
+		return io.EOF
+	}
+	copy(dest, r.values[r.next])
+	r.next++
+	return nil
+}
+
+type fakeConnector struct {
+	conn *fakeConn
+}
+
+func (c fakeConnector) Connect(context.Context) (driver.Conn, error) { return c.conn, nil }
+func (c fakeConnector) Driver() driver.Driver                        { return fakeDriver{} }
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(string) (driver.Conn, error) {
+	return nil, stderrors.New("use fakeConnector")
+}
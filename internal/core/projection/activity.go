@@ -0,0 +1,53 @@
+package projection
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// TenantActivity conta os eventos de cada tenant por dia e tipo, para os dashboards administrativos
+type TenantActivity struct {
+	TenantID  string    `json:"tenant_id" gorm:"primaryKey"`
+	Day       time.Time `json:"day" gorm:"primaryKey;type:date"`
+	EventType string    `json:"event_type" gorm:"primaryKey"`
+	Count     int64     `json:"count" gorm:"not null;default:0"`
+}
+
+func (TenantActivity) TableName() string {
+	return "tenant_event_activity"
+}
+
+type ActivityProjection struct {
+	ShadowTable
+}
+
+func NewActivityProjection() *ActivityProjection {
+	return &ActivityProjection{
+		ShadowTable: ShadowTable{Table: TenantActivity{}.TableName(), DateColumn: "day"},
+	}
+}
+
+func (p *ActivityProjection) Name() string {
+	return "tenant_activity"
+}
+
+func (p *ActivityProjection) Apply(ctx context.Context, tx *gorm.DB, record Record) error {
+	if record.TenantID == "" {
+		return nil
+	}
+
+	occurredAt, err := time.Parse(time.RFC3339, record.Event.GetOccurredOn())
+	if err != nil {
+		return fmt.Errorf("invalid occurred_on for event %s: %w", record.Event.GetEventID(), err)
+	}
+
+	shadow, _ := p.names(ctx)
+	return tx.Exec(
+		fmt.Sprintf(`INSERT INTO %s AS activity (tenant_id, day, event_type, count) VALUES (?, ?, ?, 1)
+			ON CONFLICT (tenant_id, day, event_type) DO UPDATE SET count = activity.count + 1`, shadow),
+		record.TenantID, occurredAt.UTC().Format("2006-01-02"), record.Event.GetEventType(),
+	).Error
+}
@@ -0,0 +1,42 @@
+package projection
+
+import (
+	"time"
+
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/database"
+)
+
+type CheckpointStatus string
+
+const (
+	CheckpointRunning   CheckpointStatus = "running"
+	CheckpointCompleted CheckpointStatus = "completed"
+)
+
+// Checkpoint registra até onde uma reconstrução chegou para que ela possa ser retomada.
+// O tenant do escopo não usa a coluna tenant_id para ficar fora do plugin de isolamento.
+type Checkpoint struct {
+	Key           string           `json:"key" gorm:"primaryKey"`
+	Projection    string           `json:"projection" gorm:"index;not null"`
+	Source        string           `json:"source" gorm:"not null"`
+	ScopeTenantID string           `json:"scope_tenant_id,omitempty"`
+	TxID          int64            `json:"tx_id" gorm:"not null;default:0"`
+	Position      int64            `json:"position" gorm:"not null;default:0"`
+	Events        int64            `json:"events" gorm:"not null;default:0"`
+	Status        CheckpointStatus `json:"status" gorm:"not null"`
+	StartedAt     time.Time        `json:"started_at" gorm:"not null"`
+	UpdatedAt     time.Time        `json:"updated_at"`
+	CompletedAt   *time.Time       `json:"completed_at,omitempty"`
+}
+
+func (Checkpoint) TableName() string {
+	return "projection_checkpoints"
+}
+
+func checkpointKey(projection, source string, scope Scope) string {
+	return projection + "@" + source + "/" + scope.Key()
+}
+
+func (c *Checkpoint) Cursor() database.Position {
+	return database.Position{TxID: c.TxID, Position: c.Position}
+}
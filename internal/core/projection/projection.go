@@ -0,0 +1,87 @@
+package projection
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/domain"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/domain/value_objects"
+	"gorm.io/gorm"
+)
+
+// Record é um evento armazenado com o cursor global usado como checkpoint
+type Record struct {
+	TxID     int64
+	Position int64
+	TenantID string
+	Event    domain.DomainEvent
+}
+
+// Scope limita a reconstrução a um tenant e/ou a um período de ocorrência
+type Scope struct {
+	TenantID string
+	Period   *value_objects.DateRange
+
+	// excluded são os tenants isolados, fora da partição do schema public
+	excluded []string
+}
+
+func (s Scope) IsFull() bool {
+	return s.TenantID == "" && s.Period == nil
+}
+
+func (s Scope) Key() string {
+	tenant := s.TenantID
+	if tenant == "" {
+		tenant = "*"
+	}
+	period := "*"
+	if s.Period != nil {
+		period = s.Period.StartDate.UTC().Format(time.RFC3339) + "/" + s.Period.EndDate.UTC().Format(time.RFC3339)
+	}
+	return tenant + ":" + period
+}
+
+// Projection é um read model reconstruível a partir dos eventos armazenados.
+// Begin prepara um destino paralelo (resume indica retomada de um checkpoint),
+// Apply grava cada evento nele e Swap o publica dentro de uma transação.
+type Projection interface {
+	Name() string
+	Begin(ctx context.Context, db *gorm.DB, scope Scope, resume bool) error
+	Apply(ctx context.Context, tx *gorm.DB, record Record) error
+	Swap(ctx context.Context, tx *gorm.DB, scope Scope) error
+}
+
+// Models lista as tabelas usadas pelo rebuild para database.Migrate
+func Models() []interface{} {
+	return []interface{}{&Checkpoint{}, &TenantActivity{}}
+}
+
+type Registry struct {
+	projections map[string]Projection
+}
+
+func NewRegistry(projections ...Projection) *Registry {
+	registry := &Registry{projections: make(map[string]Projection, len(projections))}
+	for _, projection := range projections {
+		registry.projections[projection.Name()] = projection
+	}
+	return registry
+}
+
+func (r *Registry) Get(name string) (Projection, error) {
+	projection, ok := r.projections[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown projection %q", name)
+	}
+	return projection, nil
+}
+
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.projections))
+	for name := range r.projections {
+		names = append(names, name)
+	}
+	return names
+}
@@ -0,0 +1,208 @@
+package projection
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/database"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/tenancy"
+	"gorm.io/gorm"
+)
+
+type Config struct {
+	BatchSize int
+}
+
+func DefaultConfig() Config {
+	return Config{BatchSize: 500}
+}
+
+type Result struct {
+	Events   int64
+	Position int64
+	Resumed  bool
+}
+
+// Rebuilder reaplica os eventos de uma Source em uma Projection. Cada lote e o
+// seu checkpoint são gravados na mesma transação, então interromper o processo
+// não perde nem duplica eventos; a próxima execução continua de onde parou.
+// Tenants isolados são reconstruídos em partições próprias, no escopo do
+// tenant; para os de banco dedicado o checkpoint fica no banco padrão e é
+// confirmado depois do lote.
+type Rebuilder struct {
+	db        *gorm.DB
+	txManager *database.GormTxManager
+	tenants   tenancy.IsolatedLister
+	source    Source
+	config    Config
+}
+
+func NewRebuilder(db *gorm.DB, tenants tenancy.IsolatedLister, source Source, config Config) *Rebuilder {
+	if config.BatchSize <= 0 {
+		config.BatchSize = DefaultConfig().BatchSize
+	}
+	return &Rebuilder{
+		db:        db,
+		txManager: database.NewTxManager(db),
+		tenants:   tenants,
+		source:    source,
+		config:    config,
+	}
+}
+
+// Rebuild reconstrói a partição do schema public, sem os tenants isolados, e
+// em seguida a de cada tenant isolado no escopo. Cada partição tem o próprio
+// checkpoint, então uma nova execução retoma a partição interrompida.
+func (r *Rebuilder) Rebuild(ctx context.Context, projection Projection, scope Scope) (Result, error) {
+	isolated, err := r.tenants.FindIsolated(tenancy.WithSystemScope(ctx))
+	if err != nil {
+		return Result{}, err
+	}
+
+	public := scope
+	var partitions []tenancy.Tenant
+	for _, tenant := range isolated {
+		if scope.TenantID == tenant.GetID() {
+			return r.rebuild(tenancy.WithTenant(ctx, tenant), projection, scope)
+		}
+		public.excluded = append(public.excluded, tenant.GetID())
+		partitions = append(partitions, tenant)
+	}
+
+	total, err := r.rebuild(tenancy.WithSystemScope(ctx), projection, public)
+	if err != nil || scope.TenantID != "" {
+		return total, err
+	}
+	for _, tenant := range partitions {
+		partition := scope
+		partition.TenantID = tenant.GetID()
+		result, err := r.rebuild(tenancy.WithTenant(ctx, tenant), projection, partition)
+		total = total.add(result)
+		if err != nil {
+			return total, fmt.Errorf("tenant %s: %w", tenant.GetID(), err)
+		}
+	}
+	return total, nil
+}
+
+func (r *Rebuilder) rebuild(ctx context.Context, projection Projection, scope Scope) (Result, error) {
+	checkpoint, resume, err := r.start(ctx, projection, scope)
+	if err != nil {
+		return Result{}, err
+	}
+	if err := projection.Begin(ctx, database.GetTenantTxFromContext(ctx, r.db), scope, resume); err != nil {
+		return Result{}, err
+	}
+	if resume {
+		log.Printf("projection %s: resuming %s from position %d", projection.Name(), scope.Key(), checkpoint.Position)
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return r.result(checkpoint, resume), err
+		}
+
+		processed := 0
+		err := r.txManager.WithTx(ctx, func(txCtx context.Context) error {
+			var err error
+			processed, err = r.applyBatch(txCtx, projection, scope, checkpoint)
+			return err
+		})
+		if err != nil {
+			return r.result(checkpoint, resume), err
+		}
+		if processed == 0 {
+			break
+		}
+		log.Printf("projection %s: applied %d events (position %d)", projection.Name(), checkpoint.Events, checkpoint.Position)
+	}
+
+	// Eventos gravados durante o último lote são aplicados na mesma transação da troca
+	err = r.txManager.WithTx(ctx, func(txCtx context.Context) error {
+		for {
+			processed, err := r.applyBatch(txCtx, projection, scope, checkpoint)
+			if err != nil {
+				return err
+			}
+			if processed == 0 {
+				break
+			}
+		}
+
+		if err := projection.Swap(txCtx, database.GetTenantTxFromContext(txCtx, r.db), scope); err != nil {
+			return err
+		}
+
+		now := time.Now().UTC()
+		checkpoint.Status = CheckpointCompleted
+		checkpoint.CompletedAt = &now
+		return database.GetTxFromContext(txCtx, r.db).Save(checkpoint).Error
+	})
+	return r.result(checkpoint, resume), err
+}
+
+func (r *Rebuilder) start(ctx context.Context, projection Projection, scope Scope) (*Checkpoint, bool, error) {
+	key := checkpointKey(projection.Name(), r.source.Name(), scope)
+	db := database.GetTxFromContext(ctx, r.db)
+
+	var checkpoint Checkpoint
+	err := db.First(&checkpoint, "key = ?", key).Error
+	if err == nil && checkpoint.Status == CheckpointRunning {
+		return &checkpoint, true, nil
+	}
+	if err != nil && !stderrors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, false, err
+	}
+
+	checkpoint = Checkpoint{
+		Key:           key,
+		Projection:    projection.Name(),
+		Source:        r.source.Name(),
+		ScopeTenantID: scope.TenantID,
+		Status:        CheckpointRunning,
+		StartedAt:     time.Now().UTC(),
+	}
+	if err := db.Save(&checkpoint).Error; err != nil {
+		return nil, false, err
+	}
+	return &checkpoint, false, nil
+}
+
+func (r *Rebuilder) applyBatch(txCtx context.Context, projection Projection, scope Scope, checkpoint *Checkpoint) (int, error) {
+	tx := database.GetTxFromContext(txCtx, r.db)
+
+	records, err := r.source.Read(txCtx, tx, checkpoint.Cursor(), scope, r.config.BatchSize)
+	if err != nil || len(records) == 0 {
+		return 0, err
+	}
+
+	target := database.GetTenantTxFromContext(txCtx, r.db)
+	for _, record := range records {
+		if err := projection.Apply(txCtx, target, record); err != nil {
+			return 0, err
+		}
+	}
+
+	last := records[len(records)-1]
+	checkpoint.TxID, checkpoint.Position = last.TxID, last.Position
+	checkpoint.Events += int64(len(records))
+	if err := tx.Save(checkpoint).Error; err != nil {
+		return 0, err
+	}
+	return len(records), nil
+}
+
+func (r *Rebuilder) result(checkpoint *Checkpoint, resumed bool) Result {
+	return Result{Events: checkpoint.Events, Position: checkpoint.Position, Resumed: resumed}
+}
+
+func (r Result) add(other Result) Result {
+	return Result{
+		Events:   r.Events + other.Events,
+		Position: max(r.Position, other.Position),
+		Resumed:  r.Resumed || other.Resumed,
+	}
+}
@@ -0,0 +1,123 @@
+package projection
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/domain"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/domain/value_objects"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/tenancy"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestShadowTableUsesTenantSchema(t *testing.T) {
+	db, pool := newRecordingDB(t)
+	ctx := tenancy.WithTenant(context.Background(), schemaTenant{})
+	projection := NewActivityProjection()
+
+	if err := projection.Begin(ctx, db.WithContext(ctx), Scope{TenantID: "t1"}, false); err != nil {
+		t.Fatal(err)
+	}
+	record := Record{TenantID: "t1", Event: domain.NewBaseDomainEvent("user.created", "u1", nil)}
+	if err := projection.Apply(ctx, db.WithContext(ctx), record); err != nil {
+		t.Fatal(err)
+	}
+	if err := projection.Swap(ctx, db.WithContext(ctx), Scope{TenantID: "t1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		`DROP TABLE IF EXISTS "tenant_acme"."tenant_event_activity_rebuild"`,
+		`CREATE TABLE IF NOT EXISTS "tenant_acme"."tenant_event_activity_rebuild" (LIKE "tenant_acme"."tenant_event_activity" INCLUDING ALL)`,
+		`INSERT INTO "tenant_acme"."tenant_event_activity_rebuild" AS activity`,
+		`DELETE FROM "tenant_acme"."tenant_event_activity" WHERE tenant_id = $1`,
+		`INSERT INTO "tenant_acme"."tenant_event_activity" SELECT * FROM "tenant_acme"."tenant_event_activity_rebuild"`,
+		`DROP TABLE "tenant_acme"."tenant_event_activity_rebuild"`,
+	}
+	if len(pool.queries) != len(want) {
+		t.Fatalf("executed %d statements, want %d: %q", len(pool.queries), len(want), pool.queries)
+	}
+	for i, prefix := range want {
+		if !strings.HasPrefix(pool.queries[i], prefix) {
+			t.Errorf("statement %d = %q, want prefix %q", i, pool.queries[i], prefix)
+		}
+	}
+}
+
+func TestShadowTableKeepsPublicTablesUnqualified(t *testing.T) {
+	db, pool := newRecordingDB(t)
+	ctx := tenancy.WithSystemScope(context.Background())
+
+	if err := NewActivityProjection().Begin(ctx, db.WithContext(ctx), Scope{}, true); err != nil {
+		t.Fatal(err)
+	}
+	want := `CREATE TABLE IF NOT EXISTS "tenant_event_activity_rebuild" (LIKE "tenant_event_activity" INCLUDING ALL)`
+	if len(pool.queries) != 1 || pool.queries[0] != want {
+		t.Fatalf("statements = %q, want %q", pool.queries, want)
+	}
+}
+
+func TestShadowTableRejectsPeriodWithoutDateColumn(t *testing.T) {
+	db, _ := newRecordingDB(t)
+	table := ShadowTable{Table: "other"}
+
+	if err := table.Begin(context.Background(), db, Scope{}, false); err != nil {
+		t.Fatalf("full rebuild returned %v", err)
+	}
+	period, err := value_objects.NewDateRange(time.Now().AddDate(0, 0, -1), time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := table.Begin(context.Background(), db, Scope{Period: &period}, false); err == nil {
+		t.Fatal("period rebuild without DateColumn returned no error")
+	}
+}
+
+type schemaTenant struct{}
+
+func (schemaTenant) GetID() string                   { return "t1" }
+func (schemaTenant) GetSlug() string                 { return "acme" }
+func (schemaTenant) IsActive() bool                  { return true }
+func (schemaTenant) GetIsolation() tenancy.Isolation { return tenancy.IsolationSchema }
+func (schemaTenant) GetSchemaName() string           { return "tenant_acme" }
+
+// recordingPool guarda o SQL executado sem precisar de um PostgreSQL
+type recordingPool struct {
+	queries []string
+}
+
+func (p *recordingPool) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return nil, sql.ErrConnDone
+}
+
+func (p *recordingPool) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	p.queries = append(p.queries, strings.Join(strings.Fields(query), " "))
+	return driverResult(1), nil
+}
+
+func (p *recordingPool) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return nil, sql.ErrConnDone
+}
+
+func (p *recordingPool) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return nil
+}
+
+type driverResult int64
+
+func (r driverResult) LastInsertId() (int64, error) { return 0, nil }
+func (r driverResult) RowsAffected() (int64, error) { return int64(r), nil }
+
+func newRecordingDB(t *testing.T) (*gorm.DB, *recordingPool) {
+	t.Helper()
+	pool := &recordingPool{}
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: pool}), &gorm.Config{SkipDefaultTransaction: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db, pool
+}
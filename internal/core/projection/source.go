@@ -0,0 +1,94 @@
+package projection
+
+import (
+	"context"
+
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/database"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/eventsourcing"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/outbox"
+	"gorm.io/gorm"
+)
+
+const (
+	SourceOutbox     = "outbox"
+	SourceEventStore = "event_store"
+)
+
+// Source lê eventos na ordem de database.Position; roda na transação da partição do rebuild
+type Source interface {
+	Name() string
+	Read(ctx context.Context, tx *gorm.DB, after database.Position, scope Scope, limit int) ([]Record, error)
+}
+
+func NewSource(name string) (Source, bool) {
+	switch name {
+	case SourceOutbox:
+		return OutboxSource{}, true
+	case SourceEventStore:
+		return EventStoreSource{}, true
+	default:
+		return nil, false
+	}
+}
+
+// OutboxSource cobre todos os agregados que publicam pelo outbox
+type OutboxSource struct{}
+
+func (OutboxSource) Name() string {
+	return SourceOutbox
+}
+
+func (OutboxSource) Read(ctx context.Context, tx *gorm.DB, after database.Position, scope Scope, limit int) ([]Record, error) {
+	var messages []outbox.Message
+	if err := scopedRead(tx, after, scope, "occurred_at", limit).Find(&messages).Error; err != nil {
+		return nil, err
+	}
+
+	records := make([]Record, 0, len(messages))
+	for _, message := range messages {
+		event, err := message.Event()
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, Record{TxID: message.TxID, Position: message.Position, TenantID: message.TenantID, Event: event})
+	}
+	return records, nil
+}
+
+// EventStoreSource cobre os agregados event-sourced
+type EventStoreSource struct{}
+
+func (EventStoreSource) Name() string {
+	return SourceEventStore
+}
+
+func (EventStoreSource) Read(ctx context.Context, tx *gorm.DB, after database.Position, scope Scope, limit int) ([]Record, error) {
+	var stored []eventsourcing.StoredEvent
+	if err := scopedRead(tx, after, scope, "occurred_at", limit).Find(&stored).Error; err != nil {
+		return nil, err
+	}
+
+	records := make([]Record, 0, len(stored))
+	for _, storedEvent := range stored {
+		event, err := storedEvent.Event()
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, Record{TxID: storedEvent.TxID, Position: storedEvent.Position, TenantID: storedEvent.TenantID, Event: event})
+	}
+	return records, nil
+}
+
+func scopedRead(tx *gorm.DB, after database.Position, scope Scope, timeColumn string, limit int) *gorm.DB {
+	db := database.After(tx, after)
+	if scope.TenantID != "" {
+		db = db.Where("tenant_id = ?", scope.TenantID)
+	}
+	if len(scope.excluded) > 0 {
+		db = db.Where("tenant_id NOT IN ?", scope.excluded)
+	}
+	if scope.Period != nil {
+		db = db.Where(timeColumn+" BETWEEN ? AND ?", scope.Period.StartDate, scope.Period.EndDate)
+	}
+	return db.Limit(limit)
+}
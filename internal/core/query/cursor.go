@@ -0,0 +1,70 @@
+package query
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+)
+
+type Cursor struct {
+	Sort     string      `json:"s"`
+	Order    string      `json:"o"`
+	Filter   string      `json:"f"`
+	Value    interface{} `json:"v"`
+	ID       string      `json:"id"`
+	Backward bool        `json:"b,omitempty"`
+}
+
+// CursorCodec assina os cursores com HMAC-SHA256 para que o cliente não
+// consiga forjar posições ou trocar a coluna de ordenação.
+type CursorCodec struct {
+	secret []byte
+}
+
+func NewCursorCodec(secret []byte) CursorCodec {
+	return CursorCodec{secret: secret}
+}
+
+func (c CursorCodec) Enabled() bool {
+	return len(c.secret) > 0
+}
+
+func (c CursorCodec) Encode(cursor Cursor) (string, error) {
+	payload, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(c.sign(encoded)), nil
+}
+
+func (c CursorCodec) Decode(token string) (Cursor, error) {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return Cursor{}, invalidInput("malformed cursor")
+	}
+
+	expected, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(expected, c.sign(encoded)) {
+		return Cursor{}, invalidInput("invalid cursor signature")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return Cursor{}, invalidInput("malformed cursor")
+	}
+
+	var cursor Cursor
+	if err := json.Unmarshal(payload, &cursor); err != nil {
+		return Cursor{}, invalidInput("malformed cursor")
+	}
+	return cursor, nil
+}
+
+func (c CursorCodec) sign(payload string) []byte {
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,119 @@
+package query
+
+import (
+	stderrors "errors"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/domain"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/errors"
+)
+
+func TestCursorCodecRoundTrip(t *testing.T) {
+	codec := NewCursorCodec([]byte("cursor-secret"))
+	cursor := Cursor{Sort: "email", Order: "asc", Filter: "abc", Value: "ana@example.com", ID: "u1", Backward: true}
+
+	token, err := codec.Encode(cursor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := codec.Decode(token)
+	if err != nil {
+		t.Fatalf("Decode returned %v", err)
+	}
+	if decoded != cursor {
+		t.Fatalf("Decode = %+v, want %+v", decoded, cursor)
+	}
+}
+
+func TestCursorCodecRejectsTamperedCursors(t *testing.T) {
+	codec := NewCursorCodec([]byte("cursor-secret"))
+	token, err := codec.Encode(Cursor{Sort: "created_at", Order: "desc", Value: "2024-01-01T00:00:00Z", ID: "u1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, signature, _ := strings.Cut(token, ".")
+
+	// Troca a coluna de ordenação mantendo a assinatura original
+	forged, err := NewCursorCodec([]byte("cursor-secret")).Encode(Cursor{Sort: "password", Order: "desc", ID: "u1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	forgedPayload, _, _ := strings.Cut(forged, ".")
+
+	tokens := map[string]string{
+		"swapped payload": forgedPayload + "." + signature,
+		"flipped byte":    payload[:len(payload)-1] + string(payload[len(payload)-1]^1) + "." + signature,
+		"no signature":    payload,
+		"wrong secret":    mustEncode(t, NewCursorCodec([]byte("other-secret")), Cursor{Sort: "created_at", ID: "u1"}),
+	}
+	for name, token := range tokens {
+		if _, err := codec.Decode(token); !isInvalidInput(err) {
+			t.Errorf("%s: Decode returned %v, want INVALID_INPUT", name, err)
+		}
+	}
+}
+
+func TestParseFilterEnforcesAllowList(t *testing.T) {
+	spec := DefaultSpec().WithFilterable("status").WithSortable("email")
+
+	filter, err := ParseFilter(url.Values{
+		"where[status]": {"active"},
+		"sort":          {"email"},
+		"order":         {"ASC"},
+		"limit":         {"500"},
+	}, spec)
+	if err != nil {
+		t.Fatalf("ParseFilter returned %v", err)
+	}
+	if filter.Sort != "email" || filter.Order != "asc" || filter.Limit != spec.MaxLimit {
+		t.Fatalf("filter = %+v", filter)
+	}
+
+	rejected := map[string]url.Values{
+		"unknown filter":   {"where[password]": {"x"}},
+		"unknown operator": {"where[status][regex]": {".*"}},
+		"unknown sort":     {"sort": {"password"}},
+		"invalid order":    {"order": {"sideways"}},
+	}
+	for name, values := range rejected {
+		if _, err := ParseFilter(values, spec); !isInvalidInput(err) {
+			t.Errorf("%s: ParseFilter returned %v, want INVALID_INPUT", name, err)
+		}
+	}
+}
+
+func TestFindPageRejectsCursorOfAnotherFilter(t *testing.T) {
+	codec := NewCursorCodec([]byte("cursor-secret"))
+	spec := DefaultSpec().WithFilterable("status").WithCursors(codec)
+
+	filter := domain.NewFilter()
+	filter.Where["status"] = map[string]interface{}{"eq": "active"}
+	filter.Cursor = mustEncode(t, codec, Cursor{Sort: "created_at", Order: "desc", Filter: "issued-for-another-filter", ID: "u1"})
+
+	if _, err := FindPage[struct{ ID string }](nil, spec, filter); !isInvalidInput(err) {
+		t.Fatalf("FindPage returned %v, want INVALID_INPUT", err)
+	}
+
+	// Recursos sem segredo não aceitam paginação por cursor
+	filter.Cursor = ""
+	filter.Pagination = domain.PaginationCursor
+	if _, err := FindPage[struct{ ID string }](nil, DefaultSpec(), filter); !isInvalidInput(err) {
+		t.Fatalf("FindPage without codec returned %v, want INVALID_INPUT", err)
+	}
+}
+
+func mustEncode(t *testing.T, codec CursorCodec, cursor Cursor) string {
+	t.Helper()
+	token, err := codec.Encode(cursor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func isInvalidInput(err error) bool {
+	var appErr errors.AppError
+	return stderrors.As(err, &appErr) && appErr.Code == errors.ErrInvalidInput.Code
+}
@@ -0,0 +1,192 @@
+package query
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Apply traduz o filtro em cláusulas parametrizadas; nenhum valor do cliente
+// é concatenado ao SQL e colunas só vêm da whitelist do Spec.
+func (s Spec) Apply(db *gorm.DB, filter domain.Filter) (*gorm.DB, error) {
+	filter, err := s.Normalize(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err = s.applyWhere(db, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	desc := filter.Order == "desc"
+	return db.
+		Order(clause.OrderByColumn{Column: column(s.Sortable[filter.Sort]), Desc: desc}).
+		Order(clause.OrderByColumn{Column: column("id"), Desc: desc}).
+		Limit(filter.Limit).
+		Offset(filter.Offset), nil
+}
+
+// ApplyWhere aplica apenas as condições, para consultas como Count
+func (s Spec) ApplyWhere(db *gorm.DB, filter domain.Filter) (*gorm.DB, error) {
+	filter, err := s.Normalize(filter)
+	if err != nil {
+		return nil, err
+	}
+	return s.applyWhere(db, filter)
+}
+
+func (s Spec) applyWhere(db *gorm.DB, filter domain.Filter) (*gorm.DB, error) {
+	// Ordena os campos para gerar SQL determinístico
+	fields := make([]string, 0, len(filter.Where))
+	for field := range filter.Where {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	for _, field := range fields {
+		col := column(s.Filterable[field])
+		conditions, err := conditionsFor(field, filter.Where[field])
+		if err != nil {
+			return nil, err
+		}
+		ops := make([]string, 0, len(conditions))
+		for op := range conditions {
+			ops = append(ops, string(op))
+		}
+		sort.Strings(ops)
+
+		for _, op := range ops {
+			expr, err := expression(col, Operator(op), conditions[Operator(op)])
+			if err != nil {
+				return nil, invalidInput(fmt.Sprintf("%s: %s", field, err.Error()))
+			}
+			db = db.Where(expr)
+		}
+	}
+	return db, nil
+}
+
+func column(name string) clause.Column {
+	return clause.Column{Table: clause.CurrentTable, Name: name}
+}
+
+// conditionsFor aceita um valor simples (igualdade) ou um mapa operador → valor
+func conditionsFor(field string, value interface{}) (map[Operator]interface{}, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		conditions := make(map[Operator]interface{}, len(v))
+		for op, opValue := range v {
+			operator := Operator(strings.ToLower(op))
+			if !operators[operator] {
+				return nil, invalidInput(fmt.Sprintf("%s: unsupported operator %q", field, op))
+			}
+			conditions[operator] = opValue
+		}
+		return conditions, nil
+	case map[string]string:
+		generic := make(map[string]interface{}, len(v))
+		for op, opValue := range v {
+			generic[op] = opValue
+		}
+		return conditionsFor(field, generic)
+	default:
+		return map[Operator]interface{}{OpEq: value}, nil
+	}
+}
+
+func expression(col clause.Column, op Operator, value interface{}) (clause.Expression, error) {
+	switch op {
+	case OpEq:
+		return clause.Eq{Column: col, Value: value}, nil
+	case OpNe:
+		return clause.Neq{Column: col, Value: value}, nil
+	case OpGt:
+		return clause.Gt{Column: col, Value: value}, nil
+	case OpGte:
+		return clause.Gte{Column: col, Value: value}, nil
+	case OpLt:
+		return clause.Lt{Column: col, Value: value}, nil
+	case OpLte:
+		return clause.Lte{Column: col, Value: value}, nil
+	case OpIn:
+		values := listValues(value)
+		if len(values) == 0 {
+			return nil, fmt.Errorf("in requires at least one value")
+		}
+		return clause.IN{Column: col, Values: values}, nil
+	case OpLike:
+		text, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("like requires a text value")
+		}
+		return clause.Expr{SQL: "? ILIKE ?", Vars: []interface{}{col, "%" + escapeLike(text) + "%"}}, nil
+	case OpBetween:
+		values := listValues(value)
+		if len(values) != 2 {
+			return nil, fmt.Errorf("between requires exactly two values")
+		}
+		return clause.Expr{SQL: "? BETWEEN ? AND ?", Vars: []interface{}{col, values[0], values[1]}}, nil
+	case OpIsNull:
+		isNull, err := boolValue(value)
+		if err != nil {
+			return nil, err
+		}
+		if isNull {
+			return clause.Expr{SQL: "? IS NULL", Vars: []interface{}{col}}, nil
+		}
+		return clause.Expr{SQL: "? IS NOT NULL", Vars: []interface{}{col}}, nil
+	default:
+		return nil, fmt.Errorf("unsupported operator %q", op)
+	}
+}
+
+// listValues aceita slices ou texto separado por vírgula (?where[status][in]=a,b)
+func listValues(value interface{}) []interface{} {
+	if text, ok := value.(string); ok {
+		var values []interface{}
+		for _, part := range strings.Split(text, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				values = append(values, part)
+			}
+		}
+		return values
+	}
+
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		if value == nil {
+			return nil
+		}
+		return []interface{}{value}
+	}
+	values := make([]interface{}, rv.Len())
+	for i := range values {
+		values[i] = rv.Index(i).Interface()
+	}
+	return values
+}
+
+func boolValue(value interface{}) (bool, error) {
+	switch v := value.(type) {
+	case bool:
+		return v, nil
+	case string:
+		switch strings.ToLower(strings.TrimSpace(v)) {
+		case "true", "1", "":
+			return true, nil
+		case "false", "0":
+			return false, nil
+		}
+	}
+	return false, fmt.Errorf("is_null requires true or false")
+}
+
+func escapeLike(text string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(text)
+}
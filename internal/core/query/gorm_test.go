@@ -0,0 +1,109 @@
+package query
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"testing"
+
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/domain"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type person struct {
+	ID        string
+	FullName  string
+	Age       int
+	Role      string
+	DeletedAt *string
+}
+
+func TestSpecApplyTranslatesFilter(t *testing.T) {
+	spec := DefaultSpec().WithFilterable("age", "role", "deleted_at")
+	spec.Filterable["name"] = "full_name"
+
+	filter := domain.NewFilter()
+	filter.Sort, filter.Order, filter.Limit, filter.Offset = "created_at", "asc", 20, 40
+	filter.Where = map[string]interface{}{
+		"name":       map[string]interface{}{"like": `50%_off`},
+		"age":        map[string]interface{}{"gte": 18, "lt": 65},
+		"role":       map[string]interface{}{"in": "admin, owner"},
+		"deleted_at": map[string]interface{}{"is_null": "true"},
+	}
+
+	query, err := spec.Apply(newDryRunDB(t).Model(&person{}), filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var people []person
+	stmt := query.Find(&people).Statement
+
+	sql := `SELECT * FROM "people" WHERE "people"."age" >= $1 AND "people"."age" < $2 AND "people"."deleted_at" IS NULL ` +
+		`AND "people"."full_name" ILIKE $3 AND "people"."role" IN ($4,$5) ` +
+		`ORDER BY "people"."created_at","people"."id" LIMIT $6 OFFSET $7`
+	if got := stmt.SQL.String(); got != sql {
+		t.Fatalf("SQL = %s\nwant  %s", got, sql)
+	}
+	vars := []interface{}{18, 65, `%50\%\_off%`, "admin", "owner", 20, 40}
+	if !reflect.DeepEqual(stmt.Vars, vars) {
+		t.Fatalf("vars = %#v, want %#v", stmt.Vars, vars)
+	}
+}
+
+func TestSpecApplyRejectsInvalidConditions(t *testing.T) {
+	spec := DefaultSpec().WithFilterable("age", "role", "deleted_at")
+
+	rejected := map[string]interface{}{
+		"age":        map[string]interface{}{"between": "18"},
+		"role":       map[string]interface{}{"in": ""},
+		"deleted_at": map[string]interface{}{"is_null": "maybe"},
+	}
+	for field, condition := range rejected {
+		filter := domain.NewFilter()
+		filter.Where[field] = condition
+		if _, err := spec.Apply(newDryRunDB(t), filter); !isInvalidInput(err) {
+			t.Errorf("%s %v: Apply returned %v, want INVALID_INPUT", field, condition, err)
+		}
+	}
+
+	// Operadores desconhecidos e nomes de coluna fora da whitelist nunca chegam ao SQL
+	filter := domain.NewFilter()
+	filter.Where["age"] = map[string]interface{}{"; DROP TABLE people": 1}
+	if _, err := spec.Apply(newDryRunDB(t), filter); !isInvalidInput(err) {
+		t.Errorf("Apply with an unknown operator returned %v, want INVALID_INPUT", err)
+	}
+	filter = domain.NewFilter()
+	filter.Where["password"] = "x"
+	if _, err := spec.Apply(newDryRunDB(t), filter); !isInvalidInput(err) {
+		t.Errorf("Apply with an unknown field returned %v, want INVALID_INPUT", err)
+	}
+}
+
+// newDryRunDB monta o SQL sem conexão com o PostgreSQL
+func newDryRunDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: unavailablePool{}}), &gorm.Config{DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+type unavailablePool struct{}
+
+func (unavailablePool) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return nil, sql.ErrConnDone
+}
+
+func (unavailablePool) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return nil, sql.ErrConnDone
+}
+
+func (unavailablePool) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return nil, sql.ErrConnDone
+}
+
+func (unavailablePool) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return nil
+}
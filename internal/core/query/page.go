@@ -0,0 +1,166 @@
+package query
+
+import (
+	"crypto/sha256"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// FindPage executa a consulta paginada por offset ou por keyset (cursor),
+// conforme o modo do filtro.
+func FindPage[T any](db *gorm.DB, spec Spec, filter domain.Filter) (domain.Page[T], error) {
+	if !filter.IsCursorPagination() {
+		query, err := spec.Apply(db, filter)
+		if err != nil {
+			return domain.Page[T]{}, err
+		}
+		var items []T
+		if err := query.Find(&items).Error; err != nil {
+			return domain.Page[T]{}, err
+		}
+		return domain.Page[T]{Items: items}, nil
+	}
+
+	if !spec.Cursors.Enabled() {
+		return domain.Page[T]{}, invalidInput("cursor pagination is not enabled for this resource")
+	}
+
+	var cursor *Cursor
+	if filter.Cursor != "" {
+		decoded, err := spec.Cursors.Decode(filter.Cursor)
+		if err != nil {
+			return domain.Page[T]{}, err
+		}
+		// O cursor fixa a ordenação da listagem em que foi emitido
+		filter.Sort, filter.Order = decoded.Sort, decoded.Order
+		cursor = &decoded
+	}
+
+	filter, err := spec.Normalize(filter)
+	if err != nil {
+		return domain.Page[T]{}, err
+	}
+	fingerprint, err := filterFingerprint(filter)
+	if err != nil {
+		return domain.Page[T]{}, err
+	}
+	if cursor != nil && cursor.Filter != fingerprint {
+		return domain.Page[T]{}, invalidInput("cursor does not match the current filter")
+	}
+
+	query, err := spec.applyWhere(db, filter)
+	if err != nil {
+		return domain.Page[T]{}, err
+	}
+
+	sortColumn := spec.Sortable[filter.Sort]
+	backward := cursor != nil && cursor.Backward
+	// Ao voltar uma página a varredura acontece na ordem inversa
+	scanDesc := (filter.Order == "desc") != backward
+
+	if cursor != nil {
+		op := ">"
+		if scanDesc {
+			op = "<"
+		}
+		query = query.Where(clause.Expr{
+			SQL:  fmt.Sprintf("(?, ?) %s (?, ?)", op),
+			Vars: []interface{}{column(sortColumn), column("id"), cursor.Value, cursor.ID},
+		})
+	}
+
+	var items []T
+	result := query.
+		Order(clause.OrderByColumn{Column: column(sortColumn), Desc: scanDesc}).
+		Order(clause.OrderByColumn{Column: column("id"), Desc: scanDesc}).
+		Limit(filter.Limit + 1).
+		Find(&items)
+	if result.Error != nil {
+		return domain.Page[T]{}, result.Error
+	}
+
+	hasMore := len(items) > filter.Limit
+	if hasMore {
+		items = items[:filter.Limit]
+	}
+	if backward {
+		slices.Reverse(items)
+	}
+
+	page := domain.Page[T]{Items: items}
+	if len(items) == 0 {
+		return page, nil
+	}
+
+	encode := func(item T, backward bool) (string, error) {
+		value, id, err := keysetValues(result, item, sortColumn)
+		if err != nil {
+			return "", err
+		}
+		return spec.Cursors.Encode(Cursor{Sort: filter.Sort, Order: filter.Order, Filter: fingerprint, Value: value, ID: id, Backward: backward})
+	}
+
+	if hasMore || backward {
+		if page.NextCursor, err = encode(items[len(items)-1], false); err != nil {
+			return domain.Page[T]{}, err
+		}
+	}
+	if (cursor != nil && !backward) || (backward && hasMore) {
+		if page.PrevCursor, err = encode(items[0], true); err != nil {
+			return domain.Page[T]{}, err
+		}
+	}
+	return page, nil
+}
+
+func keysetValues(result *gorm.DB, item interface{}, sortColumn string) (interface{}, string, error) {
+	schema := result.Statement.Schema
+	if schema == nil {
+		return nil, "", fmt.Errorf("cursor pagination requires a model")
+	}
+	sortField, idField := schema.LookUpField(sortColumn), schema.LookUpField("id")
+	if sortField == nil || idField == nil {
+		return nil, "", fmt.Errorf("cursor pagination requires %s and id columns", sortColumn)
+	}
+
+	rv := reflect.Indirect(reflect.ValueOf(item))
+	value, _ := sortField.ValueOf(result.Statement.Context, rv)
+	id, _ := idField.ValueOf(result.Statement.Context, rv)
+
+	// Value objects (Email, Slug...) não serializam os campos internos; o cursor
+	// guarda o mesmo valor que vai para o banco
+	if valuer, ok := value.(driver.Valuer); ok {
+		dbValue, err := valuer.Value()
+		if err != nil {
+			return nil, "", err
+		}
+		value = dbValue
+	}
+	return value, fmt.Sprint(id), nil
+}
+
+// filterFingerprint identifica filtro e ordenação da listagem que emitiu o
+// cursor, para que ele não seja reaproveitado em outra consulta
+func filterFingerprint(filter domain.Filter) (string, error) {
+	if len(filter.Where) == 0 {
+		filter.Where = nil
+	}
+	payload, err := json.Marshal(struct {
+		Where map[string]interface{} `json:"w"`
+		Sort  string                 `json:"s"`
+		Order string                 `json:"o"`
+	}{filter.Where, filter.Sort, filter.Order})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(payload)
+	return base64.RawURLEncoding.EncodeToString(sum[:16]), nil
+}
@@ -0,0 +1,86 @@
+package query
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/domain"
+)
+
+var whereKeyRegex = regexp.MustCompile(`^where\[([a-zA-Z0-9_]+)\](?:\[([a-zA-Z_]+)\])?$`)
+
+// ParseFilter lê filtros no formato ?where[status]=active&where[age][gt]=18&sort=email&order=asc&limit=20&offset=40;
+// ?pagination=cursor ou ?cursor=<token> ativam a paginação por keyset.
+func ParseFilter(values url.Values, spec Spec) (domain.Filter, error) {
+	filter := domain.NewFilter()
+	filter.Sort = ""
+	filter.Order = ""
+	filter.Limit = 0
+
+	for key, vals := range values {
+		if len(vals) == 0 {
+			continue
+		}
+		value := vals[len(vals)-1]
+
+		switch key {
+		case "sort":
+			filter.Sort = value
+			continue
+		case "order":
+			filter.Order = value
+			continue
+		case "limit":
+			limit, err := strconv.Atoi(value)
+			if err != nil {
+				return filter, invalidInput("limit must be a number")
+			}
+			filter.Limit = limit
+			continue
+		case "cursor":
+			filter.Cursor = value
+			filter.Pagination = domain.PaginationCursor
+			continue
+		case "pagination":
+			mode := domain.PaginationMode(value)
+			if mode != domain.PaginationOffset && mode != domain.PaginationCursor {
+				return filter, invalidInput("pagination must be offset or cursor")
+			}
+			filter.Pagination = mode
+			continue
+		case "offset":
+			offset, err := strconv.Atoi(value)
+			if err != nil {
+				return filter, invalidInput("offset must be a number")
+			}
+			filter.Offset = offset
+			continue
+		}
+
+		matches := whereKeyRegex.FindStringSubmatch(key)
+		if matches == nil {
+			continue
+		}
+		field, op := matches[1], matches[2]
+		if _, ok := spec.Filterable[field]; !ok {
+			return filter, invalidInput(fmt.Sprintf("cannot filter by %q", field))
+		}
+
+		if op == "" {
+			op = string(OpEq)
+		}
+		if !operators[Operator(op)] {
+			return filter, invalidInput(fmt.Sprintf("%s: unsupported operator %q", field, op))
+		}
+		conditions, ok := filter.Where[field].(map[string]interface{})
+		if !ok {
+			conditions = map[string]interface{}{}
+		}
+		conditions[op] = value
+		filter.Where[field] = conditions
+	}
+
+	return spec.Normalize(filter)
+}
@@ -0,0 +1,129 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/domain"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/errors"
+)
+
+type Operator string
+
+const (
+	OpEq      Operator = "eq"
+	OpNe      Operator = "ne"
+	OpGt      Operator = "gt"
+	OpGte     Operator = "gte"
+	OpLt      Operator = "lt"
+	OpLte     Operator = "lte"
+	OpIn      Operator = "in"
+	OpLike    Operator = "like"
+	OpBetween Operator = "between"
+	OpIsNull  Operator = "is_null"
+)
+
+var operators = map[Operator]bool{
+	OpEq: true, OpNe: true, OpGt: true, OpGte: true, OpLt: true, OpLte: true,
+	OpIn: true, OpLike: true, OpBetween: true, OpIsNull: true,
+}
+
+// Spec é a whitelist de campos que uma entidade expõe para filtro e ordenação.
+// As chaves são os nomes públicos (usados na query string) e os valores as colunas.
+type Spec struct {
+	Filterable   map[string]string
+	Sortable     map[string]string
+	DefaultSort  string
+	DefaultOrder string
+	DefaultLimit int
+	MaxLimit     int
+	Cursors      CursorCodec
+}
+
+func DefaultSpec() Spec {
+	return Spec{
+		Filterable: map[string]string{},
+		Sortable: map[string]string{
+			"created_at": "created_at",
+			"updated_at": "updated_at",
+		},
+		DefaultSort:  "created_at",
+		DefaultOrder: "desc",
+		DefaultLimit: 10,
+		MaxLimit:     100,
+	}
+}
+
+func (s Spec) WithFilterable(fields ...string) Spec {
+	s.Filterable = extend(s.Filterable, fields)
+	return s
+}
+
+func (s Spec) WithCursors(codec CursorCodec) Spec {
+	s.Cursors = codec
+	return s
+}
+
+func (s Spec) WithSortable(fields ...string) Spec {
+	s.Sortable = extend(s.Sortable, fields)
+	return s
+}
+
+func extend(base map[string]string, fields []string) map[string]string {
+	extended := make(map[string]string, len(base)+len(fields))
+	for name, column := range base {
+		extended[name] = column
+	}
+	for _, field := range fields {
+		extended[field] = field
+	}
+	return extended
+}
+
+// Normalize valida ordenação e campos do filtro e limita a paginação
+func (s Spec) Normalize(filter domain.Filter) (domain.Filter, error) {
+	defaultLimit, maxLimit := s.DefaultLimit, s.MaxLimit
+	if defaultLimit <= 0 {
+		defaultLimit = 10
+	}
+	if maxLimit <= 0 {
+		maxLimit = 100
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = defaultLimit
+	}
+	if filter.Limit > maxLimit {
+		filter.Limit = maxLimit
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	if filter.Sort == "" {
+		filter.Sort = s.DefaultSort
+	}
+	if _, ok := s.Sortable[filter.Sort]; !ok {
+		return filter, invalidInput(fmt.Sprintf("cannot sort by %q", filter.Sort))
+	}
+
+	filter.Order = strings.ToLower(strings.TrimSpace(filter.Order))
+	if filter.Order == "" {
+		filter.Order = strings.ToLower(s.DefaultOrder)
+	}
+	if filter.Order != "asc" && filter.Order != "desc" {
+		return filter, invalidInput("order must be asc or desc")
+	}
+
+	for field := range filter.Where {
+		if _, ok := s.Filterable[field]; !ok {
+			return filter, invalidInput(fmt.Sprintf("cannot filter by %q", field))
+		}
+	}
+
+	return filter, nil
+}
+
+func invalidInput(details string) error {
+	return errors.NewAppErrorWithDetails(errors.ErrInvalidInput.Code, errors.ErrInvalidInput.Message, details)
+}
@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"reflect"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/database"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/domain"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/errors"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/query"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const uniqueViolationCode = "23505"
+
+var (
+	_ domain.Repository[domain.AggregateRoot]         = (*GormRepository[domain.AggregateRoot])(nil)
+	_ domain.ReadOnlyRepository[domain.AggregateRoot] = (*GormRepository[domain.AggregateRoot])(nil)
+)
+
+// GormRepository implementa Repository e ReadOnlyRepository para agregados
+// persistidos como linhas; T deve ser um ponteiro para struct (ex: *User).
+type GormRepository[T domain.AggregateRoot] struct {
+	db    *gorm.DB
+	spec  query.Spec
+	scope Scope
+}
+
+// Scope restringe todas as consultas do repositório, além do filtro de tenant do plugin
+type Scope func(ctx context.Context, db *gorm.DB) *gorm.DB
+
+func NewGormRepository[T domain.AggregateRoot](db *gorm.DB, spec query.Spec) *GormRepository[T] {
+	return &GormRepository[T]{db: db, spec: spec}
+}
+
+func (r *GormRepository[T]) WithScope(scope Scope) *GormRepository[T] {
+	r.scope = scope
+	return r
+}
+
+// DB devolve a conexão ligada ao contexto, respeitando a transação corrente
+func (r *GormRepository[T]) DB(ctx context.Context) *gorm.DB {
+	db := database.GetTxFromContext(ctx, r.db)
+	if r.scope != nil {
+		db = r.scope(ctx, db)
+	}
+	return db
+}
+
+type versioned interface {
+	SetVersion(version int64)
+}
+
+// Save insere agregados novos (versão 0) e atualiza os existentes com
+// compare-and-swap na versão, devolvendo ErrConflict quando ela está defasada.
+func (r *GormRepository[T]) Save(ctx context.Context, entity T) error {
+	v, ok := any(entity).(versioned)
+	if !ok {
+		return TranslateError(r.DB(ctx).Save(entity).Error)
+	}
+
+	current := entity.GetVersion()
+	v.SetVersion(current + 1)
+
+	if current == 0 {
+		if err := r.DB(ctx).Create(entity).Error; err != nil {
+			v.SetVersion(current)
+			return TranslateError(err)
+		}
+		return nil
+	}
+
+	result := r.DB(ctx).Model(entity).
+		Where(clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: "version"}, Value: current}).
+		Select("*").
+		Updates(entity)
+	if result.Error != nil {
+		v.SetVersion(current)
+		return TranslateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		v.SetVersion(current)
+		return errors.NewAppErrorWithDetails(
+			errors.ErrConflict.Code,
+			errors.ErrConflict.Message,
+			fmt.Sprintf("aggregate %s was modified concurrently (expected version %d)", entity.GetID(), current),
+		)
+	}
+	return nil
+}
+
+func (r *GormRepository[T]) FindByID(ctx context.Context, id string) (T, error) {
+	model := r.newModel()
+	if err := r.DB(ctx).First(model, "id = ?", id).Error; err != nil {
+		var zero T
+		return zero, TranslateError(err)
+	}
+	return model, nil
+}
+
+func (r *GormRepository[T]) Delete(ctx context.Context, id string) error {
+	result := r.DB(ctx).Delete(r.newModel(), "id = ?", id)
+	if result.Error != nil {
+		return TranslateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return errors.ErrNotFound
+	}
+	return nil
+}
+
+func (r *GormRepository[T]) Exists(ctx context.Context, id string) (bool, error) {
+	var count int64
+	if err := r.DB(ctx).Model(r.newModel()).Where("id = ?", id).Count(&count).Error; err != nil {
+		return false, TranslateError(err)
+	}
+	return count > 0, nil
+}
+
+func (r *GormRepository[T]) FindAll(ctx context.Context, filter domain.Filter) (domain.Page[T], error) {
+	page, err := query.FindPage[T](r.DB(ctx).Model(r.newModel()), r.spec, filter)
+	if err != nil {
+		return domain.Page[T]{}, TranslateError(err)
+	}
+	return page, nil
+}
+
+func (r *GormRepository[T]) Count(ctx context.Context, filter domain.Filter) (int64, error) {
+	db, err := r.spec.ApplyWhere(r.DB(ctx).Model(r.newModel()), filter)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	if err := db.Count(&count).Error; err != nil {
+		return 0, TranslateError(err)
+	}
+	return count, nil
+}
+
+func (r *GormRepository[T]) newModel() T {
+	var zero T
+	return reflect.New(reflect.TypeOf(zero).Elem()).Interface().(T)
+}
+
+// TranslateError converte erros do GORM/PostgreSQL em errors.AppError
+func TranslateError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var appErr errors.AppError
+	if stderrors.As(err, &appErr) {
+		return appErr
+	}
+
+	if stderrors.Is(err, gorm.ErrRecordNotFound) {
+		return errors.ErrNotFound
+	}
+
+	if stderrors.Is(err, gorm.ErrDuplicatedKey) {
+		return errors.ErrConflict
+	}
+
+	var pgErr *pgconn.PgError
+	if stderrors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode {
+		return errors.NewAppErrorWithDetails(errors.ErrConflict.Code, errors.ErrConflict.Message, pgErr.ConstraintName)
+	}
+
+	return err
+}
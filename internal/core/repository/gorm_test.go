@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	stderrors "errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/domain"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/errors"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/query"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestGormRepositorySaveComparesVersion(t *testing.T) {
+	pool := &affectedPool{rows: 1}
+	repo := newTestRepository(t, pool)
+
+	document := &document{Title: "draft"}
+	document.Initialize()
+	document.SetVersion(3)
+
+	if err := repo.Save(context.Background(), document); err != nil {
+		t.Fatalf("Save returned %v", err)
+	}
+	if document.GetVersion() != 4 {
+		t.Fatalf("version = %d, want 4", document.GetVersion())
+	}
+	where := `WHERE "documents"."version" = $5 AND "id" = $6`
+	if !strings.HasSuffix(pool.query, where) || pool.args[4] != int64(3) || pool.args[5] != document.GetID() {
+		t.Fatalf("update did not compare the version: %s %v", pool.query, pool.args)
+	}
+}
+
+func TestGormRepositorySaveRejectsStaleVersion(t *testing.T) {
+	repo := newTestRepository(t, &affectedPool{rows: 0})
+
+	document := &document{Title: "draft"}
+	document.Initialize()
+	document.SetVersion(3)
+
+	err := repo.Save(context.Background(), document)
+	var appErr errors.AppError
+	if !stderrors.As(err, &appErr) || appErr.Code != errors.ErrConflict.Code {
+		t.Fatalf("Save of a stale version returned %v, want CONFLICT", err)
+	}
+	if document.GetVersion() != 3 {
+		t.Fatalf("version = %d after a conflict, want it restored to 3", document.GetVersion())
+	}
+}
+
+func TestGormRepositorySaveInsertsNewAggregates(t *testing.T) {
+	pool := &affectedPool{rows: 1}
+	repo := newTestRepository(t, pool)
+
+	document := &document{Title: "draft"}
+	document.Initialize()
+
+	if err := repo.Save(context.Background(), document); err != nil {
+		t.Fatalf("Save returned %v", err)
+	}
+	if !strings.HasPrefix(pool.query, `INSERT INTO "documents"`) || document.GetVersion() != 1 {
+		t.Fatalf("Save of a new aggregate ran %s and left version %d", pool.query, document.GetVersion())
+	}
+}
+
+func TestGormRepositoryDeleteMissingIsNotFound(t *testing.T) {
+	pool := &affectedPool{rows: 0}
+	repo := newTestRepository(t, pool)
+
+	err := repo.Delete(context.Background(), "missing")
+	if err != errors.ErrNotFound {
+		t.Fatalf("Delete of a missing row returned %v, want NOT_FOUND", err)
+	}
+	if pool.query != `DELETE FROM "documents" WHERE id = $1` {
+		t.Fatalf("Delete ran %s", pool.query)
+	}
+}
+
+func TestTranslateError(t *testing.T) {
+	unique := &pgconn.PgError{Code: uniqueViolationCode, ConstraintName: "idx_users_email"}
+	cases := []struct {
+		name    string
+		err     error
+		code    string
+		details string
+	}{
+		{"record not found", gorm.ErrRecordNotFound, errors.ErrNotFound.Code, ""},
+		{"duplicated key", gorm.ErrDuplicatedKey, errors.ErrConflict.Code, ""},
+		{"unique violation", fmt.Errorf("insert: %w", unique), errors.ErrConflict.Code, "idx_users_email"},
+		{"app error", errors.ErrForbidden, errors.ErrForbidden.Code, ""},
+	}
+	for _, tc := range cases {
+		var appErr errors.AppError
+		if !stderrors.As(TranslateError(tc.err), &appErr) || appErr.Code != tc.code || appErr.Details != tc.details {
+			t.Errorf("%s: TranslateError = %v, want %s %q", tc.name, TranslateError(tc.err), tc.code, tc.details)
+		}
+	}
+
+	// Erros desconhecidos seguem intactos, para virar 500 no handler
+	other := stderrors.New("connection reset")
+	if TranslateError(other) != other || TranslateError(nil) != nil {
+		t.Fatal("TranslateError changed an unknown error")
+	}
+}
+
+type document struct {
+	domain.BaseAggregateRoot
+	Title string
+}
+
+func newTestRepository(t *testing.T, pool *affectedPool) *GormRepository[*document] {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: pool}), &gorm.Config{SkipDefaultTransaction: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewGormRepository[*document](db, query.DefaultSpec())
+}
+
+// affectedPool grava o último comando e responde com o número de linhas configurado
+type affectedPool struct {
+	rows  int64
+	query string
+	args  []interface{}
+}
+
+func (p *affectedPool) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return nil, sql.ErrConnDone
+}
+
+func (p *affectedPool) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	p.query, p.args = query, args
+	return driver.RowsAffected(p.rows), nil
+}
+
+func (p *affectedPool) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return nil, sql.ErrConnDone
+}
+
+func (p *affectedPool) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return nil
+}
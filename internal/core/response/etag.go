@@ -0,0 +1,46 @@
+package response
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/errors"
+)
+
+func ETag(version int64) string {
+	return fmt.Sprintf(`"%d"`, version)
+}
+
+func SetETag(c *gin.Context, version int64) {
+	c.Header("ETag", ETag(version))
+}
+
+// IfMatchVersion lê a versão esperada do header If-Match; zero significa sem pré-condição
+func IfMatchVersion(c *gin.Context) (int64, error) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" || header == "*" {
+		return 0, nil
+	}
+
+	// If-Match exige comparação forte (RFC 9110), então ETags fracas nunca casam
+	if strings.HasPrefix(header, "W/") {
+		return 0, errors.NewAppErrorWithDetails(
+			errors.ErrPreconditionFailed.Code,
+			errors.ErrPreconditionFailed.Message,
+			"If-Match does not accept weak ETags",
+		)
+	}
+
+	tag := strings.Trim(header, `"`)
+	version, err := strconv.ParseInt(tag, 10, 64)
+	if err != nil || version <= 0 {
+		return 0, errors.NewAppErrorWithDetails(
+			errors.ErrPreconditionFailed.Code,
+			errors.ErrPreconditionFailed.Message,
+			"If-Match must carry an ETag returned by this API",
+		)
+	}
+	return version, nil
+}
@@ -4,13 +4,23 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
-	"github.com/williamkoller/multi-tenant-nexus-manager/internal/shared/errors"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/domain"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/errors"
 )
 
 type Response struct {
 	Success bool        `json:"success"`
 	Data    interface{} `json:"data,omitempty"`
 	Error   *ErrorData  `json:"error,omitempty"`
+	Meta    *Meta       `json:"meta,omitempty"`
+}
+
+type Meta struct {
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+	Limit      int    `json:"limit,omitempty"`
+	Offset     int    `json:"offset,omitempty"`
+	Total      *int64 `json:"total,omitempty"`
 }
 
 type ErrorData struct {
@@ -26,6 +36,27 @@ func Success(c *gin.Context, data interface{}) {
 	})
 }
 
+func Paginated(c *gin.Context, data interface{}, meta Meta) {
+	c.JSON(http.StatusOK, Response{
+		Success: true,
+		Data:    data,
+		Meta:    &meta,
+	})
+}
+
+// PageMeta monta os metadados de paginação a partir do filtro e da página retornada
+func PageMeta[T any](filter domain.Filter, page domain.Page[T]) Meta {
+	meta := Meta{
+		NextCursor: page.NextCursor,
+		PrevCursor: page.PrevCursor,
+		Limit:      filter.Limit,
+	}
+	if !filter.IsCursorPagination() {
+		meta.Offset = filter.Offset
+	}
+	return meta
+}
+
 func Created(c *gin.Context, data interface{}) {
 	c.JSON(http.StatusCreated, Response{
 		Success: true,
@@ -70,6 +101,8 @@ func getStatusCodeFromError(code string) int {
 		return http.StatusForbidden
 	case "CONFLICT":
 		return http.StatusConflict
+	case "PRECONDITION_FAILED":
+		return http.StatusPreconditionFailed
 	default:
 		return http.StatusInternalServerError
 	}
@@ -0,0 +1,172 @@
+package stream
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"io"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/database"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/errors"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/outbox"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/response"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/tenancy"
+)
+
+const lastEventIDHeader = "Last-Event-ID"
+
+type eventEnvelope struct {
+	ID          string      `json:"id"`
+	Type        string      `json:"type"`
+	AggregateID string      `json:"aggregate_id"`
+	OccurredOn  string      `json:"occurred_on"`
+	Data        interface{} `json:"data"`
+}
+
+// Handler transmite os eventos do tenant resolvido pelo TenantMiddleware.
+// Aceita ?event_type=user.*&event_type=tenant.suspended, ?aggregate_id= e
+// retoma a partir do Last-Event-ID (ou ?last_event_id=) enviado na reconexão.
+func (s *Stream) Handler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		if _, ok := tenancy.FromContext(ctx); !ok {
+			response.Error(c, errors.ErrForbidden)
+			return
+		}
+
+		position, err := s.lastEventID(c)
+		if err != nil {
+			response.Error(c, err)
+			return
+		}
+		filter := Filter{
+			EventTypes:  splitValues(c.QueryArray("event_type")),
+			AggregateID: c.Query("aggregate_id"),
+		}
+
+		header := c.Writer.Header()
+		header.Set("Content-Type", sse.ContentType)
+		header.Set("Cache-Control", "no-cache")
+		header.Set("Connection", "keep-alive")
+		// Evita que proxies (nginx) segurem o stream em buffer
+		header.Set("X-Accel-Buffering", "no")
+		c.Status(200)
+		c.Writer.Flush()
+
+		lastWrite := time.Now()
+		c.Stream(func(w io.Writer) bool {
+			select {
+			case <-s.closed:
+				return false
+			default:
+			}
+
+			messages, err := s.Since(ctx, position, filter)
+			if err != nil {
+				if ctx.Err() == nil {
+					log.Printf("event stream: %v", err)
+				}
+				return false
+			}
+
+			for _, message := range messages {
+				if err := writeMessage(w, message); err != nil {
+					log.Printf("event stream: skipping message %s: %v", message.ID, err)
+				}
+				position = database.Position{TxID: message.TxID, Position: message.Position}
+				lastWrite = time.Now()
+			}
+			if len(messages) == s.config.BatchSize {
+				return true
+			}
+
+			if time.Since(lastWrite) >= s.config.Heartbeat {
+				if _, err := io.WriteString(w, ": heartbeat\n\n"); err != nil {
+					return false
+				}
+				lastWrite = time.Now()
+			}
+
+			select {
+			case <-ctx.Done():
+				return false
+			case <-s.closed:
+				return false
+			case <-time.After(s.config.PollInterval):
+				return true
+			}
+		})
+	}
+}
+
+func writeMessage(w io.Writer, message outbox.Message) error {
+	event, err := message.Event()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(eventEnvelope{
+		ID:          event.GetEventID(),
+		Type:        event.GetEventType(),
+		AggregateID: event.GetAggregateID(),
+		OccurredOn:  event.GetOccurredOn(),
+		Data:        event.GetEventData(),
+	})
+	if err != nil {
+		return err
+	}
+
+	return sse.Encode(w, sse.Event{
+		Id:    database.Position{TxID: message.TxID, Position: message.Position}.String(),
+		Event: message.EventType,
+		Data:  string(data),
+	})
+}
+
+func (s *Stream) lastEventID(c *gin.Context) (database.Position, error) {
+	value := c.GetHeader(lastEventIDHeader)
+	if value == "" {
+		value = c.Query("last_event_id")
+	}
+	if value == "" {
+		return database.Position{}, nil
+	}
+
+	invalid := errors.NewAppErrorWithDetails("INVALID_INPUT", "Invalid input data", "Last-Event-ID must be an id sent by this stream")
+
+	// IDs emitidos antes do cursor composto traziam só a posição
+	if legacy, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if legacy <= 0 {
+			return database.Position{}, nil
+		}
+		position, err := s.Resolve(c.Request.Context(), legacy)
+		var appErr errors.AppError
+		if stderrors.As(err, &appErr) && appErr.Code == errors.ErrNotFound.Code {
+			return database.Position{}, invalid
+		}
+		return position, err
+	}
+
+	position, err := database.ParsePosition(value)
+	if err != nil {
+		return database.Position{}, invalid
+	}
+	return position, nil
+}
+
+func splitValues(values []string) []string {
+	var split []string
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				split = append(split, part)
+			}
+		}
+	}
+	return split
+}
@@ -0,0 +1,128 @@
+package stream
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/database"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/outbox"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/repository"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Config struct {
+	BatchSize    int
+	PollInterval time.Duration
+	Heartbeat    time.Duration
+}
+
+func DefaultConfig() Config {
+	return Config{
+		BatchSize:    100,
+		PollInterval: time.Second,
+		Heartbeat:    15 * time.Second,
+	}
+}
+
+// Filter restringe o stream; EventTypes aceita os mesmos padrões do event bus ("user.*")
+type Filter struct {
+	EventTypes  []string
+	AggregateID string
+}
+
+// Stream lê o outbox do tenant no contexto na ordem de database.Position
+type Stream struct {
+	db        *gorm.DB
+	txManager database.TxManager
+	config    Config
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func New(db *gorm.DB, txManager database.TxManager, config Config) *Stream {
+	defaults := DefaultConfig()
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaults.BatchSize
+	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = defaults.PollInterval
+	}
+	if config.Heartbeat <= 0 {
+		config.Heartbeat = defaults.Heartbeat
+	}
+
+	return &Stream{db: db, txManager: txManager, config: config, closed: make(chan struct{})}
+}
+
+// Close encerra as conexões abertas pelo Handler. http.Server.Shutdown não
+// cancela o contexto das requisições, então sem isso o desligamento esperaria
+// cada cliente desconectar; registre com http.Server.RegisterOnShutdown.
+func (s *Stream) Close() {
+	s.closeOnce.Do(func() { close(s.closed) })
+}
+
+// Since devolve as mensagens confirmadas posteriores ao cursor informado
+func (s *Stream) Since(ctx context.Context, after database.Position, filter Filter) ([]outbox.Message, error) {
+	var messages []outbox.Message
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		db := database.After(database.GetTxFromContext(ctx, s.db), after)
+
+		if filter.AggregateID != "" {
+			db = db.Where("aggregate_id = ?", filter.AggregateID)
+		}
+		if expression := eventTypeExpression(filter.EventTypes); expression != nil {
+			db = db.Where(expression)
+		}
+
+		return db.Limit(s.config.BatchSize).Find(&messages).Error
+	})
+	if err != nil {
+		return nil, repository.TranslateError(err)
+	}
+	return messages, nil
+}
+
+// Resolve converte um Last-Event-ID antigo, só com a posição, no cursor completo
+func (s *Stream) Resolve(ctx context.Context, position int64) (database.Position, error) {
+	var message outbox.Message
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		return database.GetTxFromContext(ctx, s.db).
+			Select("tx_id", "position").
+			First(&message, "position = ?", position).Error
+	})
+	if err != nil {
+		return database.Position{}, repository.TranslateError(err)
+	}
+	return database.Position{TxID: message.TxID, Position: message.Position}, nil
+}
+
+// eventTypeExpression traduz os padrões do bus em igualdade ou LIKE por prefixo
+func eventTypeExpression(patterns []string) clause.Expression {
+	column := clause.Column{Name: "event_type"}
+	var expressions []clause.Expression
+	for _, pattern := range patterns {
+		pattern = strings.TrimSpace(pattern)
+		switch {
+		case pattern == "":
+			continue
+		case pattern == "*":
+			return nil
+		case strings.HasSuffix(pattern, ".*"):
+			prefix := strings.TrimSuffix(pattern, "*")
+			expressions = append(expressions, clause.Like{Column: column, Value: escapeLike(prefix) + "%"})
+		default:
+			expressions = append(expressions, clause.Eq{Column: column, Value: pattern})
+		}
+	}
+	if len(expressions) == 0 {
+		return nil
+	}
+	return clause.Or(expressions...)
+}
+
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
@@ -0,0 +1,60 @@
+package tenancy
+
+import (
+	"context"
+)
+
+type Tenant interface {
+	GetID() string
+	GetSlug() string
+	IsActive() bool
+	GetIsolation() Isolation
+	GetSchemaName() string
+}
+
+type Loader interface {
+	FindByID(ctx context.Context, id string) (Tenant, error)
+	FindBySlug(ctx context.Context, slug string) (Tenant, error)
+}
+
+// IsolatedLister lista os tenants cujas tabelas ficam fora do schema public do
+// banco padrão, para os processos que atendem todos os tenants
+type IsolatedLister interface {
+	FindIsolated(ctx context.Context) ([]Tenant, error)
+}
+
+type tenantKeyType struct{}
+
+var tenantKey = tenantKeyType{}
+
+func WithTenant(ctx context.Context, tenant Tenant) context.Context {
+	return context.WithValue(ctx, tenantKey, tenant)
+}
+
+func FromContext(ctx context.Context) (Tenant, bool) {
+	tenant, ok := ctx.Value(tenantKey).(Tenant)
+	return tenant, ok && tenant != nil
+}
+
+func TenantIDFromContext(ctx context.Context) (string, bool) {
+	tenant, ok := FromContext(ctx)
+	if !ok {
+		return "", false
+	}
+	return tenant.GetID(), true
+}
+
+type systemScopeKeyType struct{}
+
+var systemScopeKey = systemScopeKeyType{}
+
+// WithSystemScope marca operações de sistema que podem atravessar tenants
+// (jobs, migrações, relays). Use com parcimônia.
+func WithSystemScope(ctx context.Context) context.Context {
+	return context.WithValue(ctx, systemScopeKey, true)
+}
+
+func IsSystemScope(ctx context.Context) bool {
+	system, _ := ctx.Value(systemScopeKey).(bool)
+	return system
+}
@@ -0,0 +1,46 @@
+package tenancy
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+type Isolation string
+
+const (
+	IsolationSharedTable Isolation = "shared_table"
+	IsolationSchema      Isolation = "schema"
+	IsolationDatabase    Isolation = "database"
+)
+
+func ParseIsolation(value string) (Isolation, error) {
+	switch Isolation(strings.ToLower(strings.TrimSpace(value))) {
+	case "", IsolationSharedTable:
+		return IsolationSharedTable, nil
+	case IsolationSchema:
+		return IsolationSchema, nil
+	case IsolationDatabase:
+		return IsolationDatabase, nil
+	default:
+		return "", fmt.Errorf("invalid isolation strategy: %s", value)
+	}
+}
+
+var schemaNameRegex = regexp.MustCompile(`^[a-z_][a-z0-9_]{0,62}$`)
+
+// SchemaNameForSlug gera um identificador PostgreSQL válido a partir do slug do tenant
+func SchemaNameForSlug(slug string) (string, error) {
+	name := "tenant_" + strings.ReplaceAll(strings.ToLower(slug), "-", "_")
+	if len(name) > 63 {
+		name = name[:63]
+	}
+	if !IsValidSchemaName(name) {
+		return "", fmt.Errorf("invalid schema name: %s", name)
+	}
+	return name, nil
+}
+
+func IsValidSchemaName(name string) bool {
+	return schemaNameRegex.MatchString(name)
+}
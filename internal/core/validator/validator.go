@@ -5,7 +5,9 @@ import (
 	"reflect"
 	"strings"
 
+	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/errors"
 )
 
 type Validator struct {
@@ -28,11 +30,22 @@ func New() *Validator {
 
 func (v *Validator) Validate(i interface{}) error {
 	if err := v.validate.Struct(i); err != nil {
-		var errors []string
+		var messages []string
 		for _, err := range err.(validator.ValidationErrors) {
-			errors = append(errors, fmt.Sprintf("%s is %s", err.Field(), err.Tag()))
+			messages = append(messages, fmt.Sprintf("%s is %s", err.Field(), err.Tag()))
 		}
-		return fmt.Errorf("validation failed: %s", strings.Join(errors, ", "))
+		return fmt.Errorf("validation failed: %s", strings.Join(messages, ", "))
+	}
+	return nil
+}
+
+// BindJSON decodifica o corpo da requisição e valida o resultado; falhas viram INVALID_INPUT
+func (v *Validator) BindJSON(c *gin.Context, request interface{}) error {
+	if err := c.ShouldBindJSON(request); err != nil {
+		return errors.NewAppErrorWithDetails("INVALID_INPUT", "Invalid input data", err.Error())
+	}
+	if err := v.Validate(request); err != nil {
+		return errors.NewAppErrorWithDetails("INVALID_INPUT", "Invalid input data", err.Error())
 	}
 	return nil
 }
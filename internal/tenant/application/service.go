@@ -0,0 +1,118 @@
+package application_tenant
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/database"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/errors"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/outbox"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/tenancy"
+	domain_tenant "github.com/williamkoller/multi-tenant-nexus-manager/internal/tenant/domain"
+)
+
+// Provisioner prepara a estrutura de um tenant isolado e deve ser idempotente
+type Provisioner interface {
+	Provision(ctx context.Context, tenant tenancy.Tenant) error
+}
+
+type CreateTenantInput struct {
+	Name         string
+	Slug         string
+	CNPJ         string
+	PrimaryColor string
+	// Isolation vazio assume shared_table
+	Isolation string
+}
+
+// Service cria tenants e só os ativa depois do provisionamento. O cadastro de
+// tenants não pertence a nenhum tenant, por isso tudo roda em escopo de sistema.
+type Service struct {
+	tenants     domain_tenant.Repository
+	txManager   database.TxManager
+	outbox      *outbox.Outbox
+	provisioner Provisioner
+}
+
+func NewService(tenants domain_tenant.Repository, txManager database.TxManager, outbox *outbox.Outbox, provisioner Provisioner) *Service {
+	return &Service{
+		tenants:     tenants,
+		txManager:   txManager,
+		outbox:      outbox,
+		provisioner: provisioner,
+	}
+}
+
+// Create grava o tenant em provisionamento antes de criar o schema; se o
+// provisionamento falhar, o tenant continua inativo e Provision pode retomá-lo.
+func (s *Service) Create(ctx context.Context, input CreateTenantInput) (*domain_tenant.Tenant, error) {
+	tenant, err := domain_tenant.NewTenant(input.Name, input.Slug, input.CNPJ, input.PrimaryColor)
+	if err != nil {
+		return nil, err
+	}
+	if err := tenant.UseIsolation(tenancy.Isolation(input.Isolation)); err != nil {
+		return nil, err
+	}
+
+	ctx = tenancy.WithSystemScope(ctx)
+	if err := s.save(ctx, tenant); err != nil {
+		return nil, err
+	}
+	return tenant, s.finishProvisioning(ctx, tenant)
+}
+
+func (s *Service) Provision(ctx context.Context, id string) (*domain_tenant.Tenant, error) {
+	ctx = tenancy.WithSystemScope(ctx)
+
+	var tenant *domain_tenant.Tenant
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		found, err := s.tenants.FindByID(ctx, id)
+		tenant = found
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if tenant.Status != domain_tenant.StatusProvisioning {
+		return nil, errors.NewAppErrorWithDetails("CONFLICT", "Resource conflict", "tenant is not provisioning")
+	}
+	return tenant, s.finishProvisioning(ctx, tenant)
+}
+
+// MigrateIsolated reaplica as migrações nos schemas e bancos dos tenants isolados
+func (s *Service) MigrateIsolated(ctx context.Context) error {
+	ctx = tenancy.WithSystemScope(ctx)
+
+	var tenants []*domain_tenant.Tenant
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		found, err := s.tenants.FindIsolated(ctx)
+		tenants = found
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, tenant := range tenants {
+		if err := s.provisioner.Provision(ctx, tenant); err != nil {
+			return fmt.Errorf("failed to migrate tenant %s: %w", tenant.GetID(), err)
+		}
+	}
+	return nil
+}
+
+func (s *Service) finishProvisioning(ctx context.Context, tenant *domain_tenant.Tenant) error {
+	if err := s.provisioner.Provision(ctx, tenant); err != nil {
+		return err
+	}
+	if err := tenant.Activate(); err != nil {
+		return err
+	}
+	return s.save(ctx, tenant)
+}
+
+func (s *Service) save(ctx context.Context, tenant *domain_tenant.Tenant) error {
+	return s.outbox.Save(ctx, tenant, func(ctx context.Context) error {
+		return s.tenants.Save(ctx, tenant)
+	})
+}
@@ -0,0 +1,51 @@
+package domain_tenant
+
+import (
+	"time"
+
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/events"
+)
+
+const (
+	EventTenantCreated         = "tenant.created"
+	EventTenantActivated       = "tenant.activated"
+	EventTenantSuspended       = "tenant.suspended"
+	EventTenantArchived        = "tenant.archived"
+	EventTenantBrandingChanged = "tenant.branding_changed"
+)
+
+type TenantCreated struct {
+	Name      string    `json:"name"`
+	Slug      string    `json:"slug"`
+	CNPJ      string    `json:"cnpj"`
+	Status    Status    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type TenantActivated struct {
+	PreviousStatus Status    `json:"previous_status"`
+	ActivatedAt    time.Time `json:"activated_at"`
+}
+
+type TenantSuspended struct {
+	Reason      string    `json:"reason"`
+	SuspendedAt time.Time `json:"suspended_at"`
+}
+
+type TenantArchived struct {
+	PreviousStatus Status    `json:"previous_status"`
+	ArchivedAt     time.Time `json:"archived_at"`
+}
+
+type TenantBrandingChanged struct {
+	PrimaryColor string `json:"primary_color"`
+}
+
+// RegisterEvents registra os payloads do contexto de tenant no registry
+func RegisterEvents(registry *events.Registry) {
+	events.Register[TenantCreated](registry, EventTenantCreated, 1)
+	events.Register[TenantActivated](registry, EventTenantActivated, 1)
+	events.Register[TenantSuspended](registry, EventTenantSuspended, 1)
+	events.Register[TenantArchived](registry, EventTenantArchived, 1)
+	events.Register[TenantBrandingChanged](registry, EventTenantBrandingChanged, 1)
+}
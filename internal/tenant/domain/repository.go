@@ -0,0 +1,13 @@
+package domain_tenant
+
+import (
+	"context"
+
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/domain"
+)
+
+type Repository interface {
+	domain.Repository[*Tenant]
+	// FindIsolated lista os tenants não arquivados que têm schema ou banco próprio
+	FindIsolated(ctx context.Context) ([]*Tenant, error)
+}
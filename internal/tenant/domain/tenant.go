@@ -0,0 +1,246 @@
+package domain_tenant
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/domain"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/domain/value_objects"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/errors"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/tenancy"
+)
+
+type Status string
+
+const (
+	StatusProvisioning Status = "provisioning"
+	StatusActive       Status = "active"
+	StatusSuspended    Status = "suspended"
+	StatusArchived     Status = "archived"
+)
+
+// Transições permitidas no ciclo de vida do tenant
+var allowedTransitions = map[Status][]Status{
+	StatusProvisioning: {StatusActive, StatusArchived},
+	StatusActive:       {StatusSuspended, StatusArchived},
+	StatusSuspended:    {StatusActive, StatusArchived},
+	StatusArchived:     {},
+}
+
+func (s Status) CanTransitionTo(next Status) bool {
+	for _, allowed := range allowedTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	_ domain.AggregateRoot = (*Tenant)(nil)
+	_ tenancy.Tenant       = (*Tenant)(nil)
+)
+
+type Tenant struct {
+	domain.BaseAggregateRoot
+	Name             string              `json:"name" gorm:"not null"`
+	Slug             value_objects.Slug  `json:"slug" gorm:"uniqueIndex;not null"`
+	CNPJ             value_objects.CNPJ  `json:"cnpj" gorm:"index;not null"`
+	PrimaryColor     value_objects.Color `json:"primary_color"`
+	Status           Status              `json:"status" gorm:"index;not null"`
+	SuspensionReason string              `json:"suspension_reason,omitempty"`
+	Isolation        tenancy.Isolation   `json:"isolation" gorm:"not null;default:shared_table"`
+	SchemaName       string              `json:"schema_name,omitempty"`
+}
+
+func (Tenant) TableName() string {
+	return "tenants"
+}
+
+func NewTenant(name, slug, cnpj, primaryColor string) (*Tenant, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, errors.NewAppErrorWithDetails("INVALID_INPUT", "Invalid input data", "tenant name is required")
+	}
+
+	slugVO, err := value_objects.NewSlug(slug)
+	if err != nil {
+		return nil, errors.NewAppErrorWithDetails("INVALID_INPUT", "Invalid input data", err.Error())
+	}
+
+	cnpjVO, err := value_objects.NewCNPJ(cnpj)
+	if err != nil {
+		return nil, errors.NewAppErrorWithDetails("INVALID_INPUT", "Invalid input data", err.Error())
+	}
+
+	colorVO, err := value_objects.NewColor(primaryColor)
+	if err != nil {
+		return nil, errors.NewAppErrorWithDetails("INVALID_INPUT", "Invalid input data", err.Error())
+	}
+
+	tenant := &Tenant{
+		Name:         name,
+		Slug:         slugVO,
+		CNPJ:         cnpjVO,
+		PrimaryColor: colorVO,
+		Status:       StatusProvisioning,
+		Isolation:    tenancy.IsolationSharedTable,
+	}
+
+	event := domain.NewBaseDomainEvent(
+		EventTenantCreated,
+		tenant.GetID(),
+		TenantCreated{
+			Name:      tenant.Name,
+			Slug:      tenant.Slug.String(),
+			CNPJ:      tenant.CNPJ.String(),
+			Status:    tenant.Status,
+			CreatedAt: time.Now().UTC(),
+		},
+	)
+	tenant.RaiseDomainEvent(event)
+
+	return tenant, nil
+}
+
+func (t *Tenant) Activate() error {
+	previous := t.Status
+	if err := t.transitionTo(StatusActive); err != nil {
+		return err
+	}
+	t.SuspensionReason = ""
+
+	event := domain.NewBaseDomainEvent(
+		EventTenantActivated,
+		t.GetID(),
+		TenantActivated{
+			PreviousStatus: previous,
+			ActivatedAt:    time.Now().UTC(),
+		},
+	)
+	t.RaiseDomainEvent(event)
+	return nil
+}
+
+func (t *Tenant) Suspend(reason string) error {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return errors.NewAppErrorWithDetails("INVALID_INPUT", "Invalid input data", "suspension reason is required")
+	}
+	if err := t.transitionTo(StatusSuspended); err != nil {
+		return err
+	}
+	t.SuspensionReason = reason
+
+	event := domain.NewBaseDomainEvent(
+		EventTenantSuspended,
+		t.GetID(),
+		TenantSuspended{
+			Reason:      reason,
+			SuspendedAt: time.Now().UTC(),
+		},
+	)
+	t.RaiseDomainEvent(event)
+	return nil
+}
+
+func (t *Tenant) Archive() error {
+	previous := t.Status
+	if err := t.transitionTo(StatusArchived); err != nil {
+		return err
+	}
+
+	event := domain.NewBaseDomainEvent(
+		EventTenantArchived,
+		t.GetID(),
+		TenantArchived{
+			PreviousStatus: previous,
+			ArchivedAt:     time.Now().UTC(),
+		},
+	)
+	t.RaiseDomainEvent(event)
+	return nil
+}
+
+// UseIsolation define a estratégia de isolamento; só pode mudar durante o provisionamento
+func (t *Tenant) UseIsolation(isolation tenancy.Isolation) error {
+	if t.Status != StatusProvisioning {
+		return errors.NewAppErrorWithDetails("CONFLICT", "Resource conflict", "isolation can only be chosen while provisioning")
+	}
+
+	isolation, err := tenancy.ParseIsolation(string(isolation))
+	if err != nil {
+		return errors.NewAppErrorWithDetails("INVALID_INPUT", "Invalid input data", err.Error())
+	}
+
+	schemaName := ""
+	if isolation == tenancy.IsolationSchema {
+		name, err := tenancy.SchemaNameForSlug(t.Slug.String())
+		if err != nil {
+			return errors.NewAppErrorWithDetails("INVALID_INPUT", "Invalid input data", err.Error())
+		}
+		schemaName = name
+	}
+
+	t.Isolation = isolation
+	t.SchemaName = schemaName
+	return nil
+}
+
+func (t *Tenant) ChangeBranding(primaryColor string) error {
+	if t.Status == StatusArchived {
+		return errors.NewAppErrorWithDetails("CONFLICT", "Resource conflict", "archived tenants cannot be changed")
+	}
+
+	colorVO, err := value_objects.NewColor(primaryColor)
+	if err != nil {
+		return errors.NewAppErrorWithDetails("INVALID_INPUT", "Invalid input data", err.Error())
+	}
+	if colorVO == t.PrimaryColor {
+		return nil
+	}
+	t.PrimaryColor = colorVO
+
+	event := domain.NewBaseDomainEvent(
+		EventTenantBrandingChanged,
+		t.GetID(),
+		TenantBrandingChanged{
+			PrimaryColor: colorVO.String(),
+		},
+	)
+	t.RaiseDomainEvent(event)
+	return nil
+}
+
+func (t *Tenant) GetSlug() string {
+	return t.Slug.String()
+}
+
+func (t *Tenant) IsActive() bool {
+	return t.Status == StatusActive
+}
+
+func (t *Tenant) GetIsolation() tenancy.Isolation {
+	if t.Isolation == "" {
+		return tenancy.IsolationSharedTable
+	}
+	return t.Isolation
+}
+
+func (t *Tenant) GetSchemaName() string {
+	return t.SchemaName
+}
+
+func (t *Tenant) transitionTo(next Status) error {
+	if !t.Status.CanTransitionTo(next) {
+		return errors.NewAppErrorWithDetails(
+			"CONFLICT",
+			"Invalid tenant status transition",
+			fmt.Sprintf("cannot transition tenant from %s to %s", t.Status, next),
+		)
+	}
+	t.Status = next
+	t.UpdatedAt = time.Now()
+	return nil
+}
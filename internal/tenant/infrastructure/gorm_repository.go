@@ -0,0 +1,93 @@
+package infrastructure_tenant
+
+import (
+	"context"
+
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/query"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/repository"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/tenancy"
+	domain_tenant "github.com/williamkoller/multi-tenant-nexus-manager/internal/tenant/domain"
+	"gorm.io/gorm"
+)
+
+var (
+	_ domain_tenant.Repository = (*Repository)(nil)
+	_ tenancy.Loader           = (*Loader)(nil)
+	_ tenancy.IsolatedLister   = (*Loader)(nil)
+)
+
+// Models lista as tabelas do contexto para database.Migrate
+func Models() []interface{} {
+	return []interface{}{&domain_tenant.Tenant{}}
+}
+
+func Spec() query.Spec {
+	return query.DefaultSpec().
+		WithFilterable("name", "slug", "status", "isolation").
+		WithSortable("name", "slug")
+}
+
+type Repository struct {
+	*repository.GormRepository[*domain_tenant.Tenant]
+}
+
+func NewRepository(db *gorm.DB, spec query.Spec) *Repository {
+	return &Repository{
+		GormRepository: repository.NewGormRepository[*domain_tenant.Tenant](db, spec),
+	}
+}
+
+func (r *Repository) FindIsolated(ctx context.Context) ([]*domain_tenant.Tenant, error) {
+	var tenants []*domain_tenant.Tenant
+	err := r.DB(ctx).
+		Where("isolation <> ? AND status <> ?", tenancy.IsolationSharedTable, domain_tenant.StatusArchived).
+		Order("created_at").
+		Find(&tenants).Error
+	if err != nil {
+		return nil, repository.TranslateError(err)
+	}
+	return tenants, nil
+}
+
+// Loader resolve tenants para o TenantMiddleware e para o relay do outbox.
+// O cadastro de tenants fica sempre no banco padrão, nunca no banco do tenant.
+type Loader struct {
+	db *gorm.DB
+}
+
+func NewLoader(db *gorm.DB) *Loader {
+	return &Loader{db: db}
+}
+
+func (l *Loader) FindByID(ctx context.Context, id string) (tenancy.Tenant, error) {
+	return l.find(ctx, "id = ?", id)
+}
+
+func (l *Loader) FindBySlug(ctx context.Context, slug string) (tenancy.Tenant, error) {
+	return l.find(ctx, "slug = ?", slug)
+}
+
+func (l *Loader) FindIsolated(ctx context.Context) ([]tenancy.Tenant, error) {
+	var tenants []*domain_tenant.Tenant
+	err := l.db.WithContext(ctx).
+		Where("isolation <> ? AND status <> ?", tenancy.IsolationSharedTable, domain_tenant.StatusArchived).
+		Order("created_at").
+		Find(&tenants).Error
+	if err != nil {
+		return nil, repository.TranslateError(err)
+	}
+
+	isolated := make([]tenancy.Tenant, 0, len(tenants))
+	for _, tenant := range tenants {
+		isolated = append(isolated, tenant)
+	}
+	return isolated, nil
+}
+
+func (l *Loader) find(ctx context.Context, condition string, value string) (tenancy.Tenant, error) {
+	var tenant domain_tenant.Tenant
+	if err := l.db.WithContext(ctx).First(&tenant, condition, value).Error; err != nil {
+		return nil, repository.TranslateError(err)
+	}
+	return &tenant, nil
+}
@@ -0,0 +1,393 @@
+package application_user
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/auth"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/database"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/domain"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/errors"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/tenancy"
+	domain_user "github.com/williamkoller/multi-tenant-nexus-manager/internal/user/domain"
+)
+
+// ErrPasswordExpired só é devolvido depois que a senha foi conferida
+var ErrPasswordExpired = errors.NewAppErrorWithDetails(
+	errors.ErrUnauthorized.Code,
+	errors.ErrUnauthorized.Message,
+	"password has expired and must be changed",
+)
+
+type AuthConfig struct {
+	MaxFailedAttempts int
+	LockoutDuration   time.Duration
+}
+
+func DefaultAuthConfig() AuthConfig {
+	return AuthConfig{
+		MaxFailedAttempts: 5,
+		LockoutDuration:   15 * time.Minute,
+	}
+}
+
+type LoginResult struct {
+	User    *domain_user.User
+	Tenants []TenantAccess
+}
+
+type ChangePasswordInput struct {
+	Email           string
+	CurrentPassword string
+	NewPassword     string
+}
+
+// AuthService autentica identidades globais. Toda falha de login vira
+// errors.ErrUnauthorized, e e-mails inexistentes também pagam o custo do hash
+// para que o tempo de resposta não revele quais contas existem.
+type AuthService struct {
+	users       domain_user.Repository
+	memberships domain_user.MembershipRepository
+	credentials domain_user.CredentialRepository
+	policies    domain_user.PasswordPolicyRepository
+	tenants     tenancy.Loader
+	txManager   database.TxManager
+	hasher      *auth.PasswordHasher
+	config      AuthConfig
+	dummyHash   string
+}
+
+func NewAuthService(
+	users domain_user.Repository,
+	memberships domain_user.MembershipRepository,
+	credentials domain_user.CredentialRepository,
+	policies domain_user.PasswordPolicyRepository,
+	tenants tenancy.Loader,
+	txManager database.TxManager,
+	hasher *auth.PasswordHasher,
+	config AuthConfig,
+) *AuthService {
+	defaults := DefaultAuthConfig()
+	if config.MaxFailedAttempts <= 0 {
+		config.MaxFailedAttempts = defaults.MaxFailedAttempts
+	}
+	if config.LockoutDuration <= 0 {
+		config.LockoutDuration = defaults.LockoutDuration
+	}
+
+	dummyHash, err := hasher.Hash("timing-equalizer")
+	if err != nil {
+		log.Printf("auth: failed to prepare dummy hash: %v", err)
+	}
+
+	return &AuthService{
+		users:       users,
+		memberships: memberships,
+		credentials: credentials,
+		policies:    policies,
+		tenants:     tenants,
+		txManager:   txManager,
+		hasher:      hasher,
+		config:      config,
+		dummyHash:   dummyHash,
+	}
+}
+
+// Login devolve a identidade e os tenants em que ela pode entrar
+func (s *AuthService) Login(ctx context.Context, email, password string) (*LoginResult, error) {
+	user, credential, err := s.authenticate(ctx, email, password)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsActive {
+		return nil, errors.ErrUnauthorized
+	}
+
+	policy, err := s.policyFor(ctx, user.GetID())
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	if credential.IsExpired(policy.MaxAge(), now) {
+		return nil, ErrPasswordExpired
+	}
+
+	tenants, err := accessibleTenants(ctx, s.txManager, s.memberships, s.tenants, user.GetID())
+	if err != nil {
+		return nil, err
+	}
+
+	credential.RecordSuccess(now)
+	if s.hasher.NeedsRehash(credential.PasswordHash) {
+		if hash, err := s.hasher.Hash(password); err == nil {
+			credential.Rehash(hash, now)
+		}
+	}
+	if err := s.saveCredential(ctx, credential); err != nil {
+		return nil, err
+	}
+
+	return &LoginResult{User: user, Tenants: tenants}, nil
+}
+
+// ChangePassword é autenticado pela senha atual e funciona mesmo com a senha expirada
+func (s *AuthService) ChangePassword(ctx context.Context, input ChangePasswordInput) error {
+	user, credential, err := s.authenticate(ctx, input.Email, input.CurrentPassword)
+	if err != nil {
+		return err
+	}
+	return s.replacePassword(ctx, user.GetID(), credential, input.NewPassword)
+}
+
+// SetPassword é usado pelo administrador do tenant que detém sozinho a
+// identidade e também desbloqueia a credencial. Identidades compartilhadas
+// com outros tenants só trocam a senha pelo próprio usuário.
+func (s *AuthService) SetPassword(ctx context.Context, userID, password string) error {
+	if err := administerIdentity(ctx, s.txManager, s.users, s.memberships, userID); err != nil {
+		return err
+	}
+
+	credential, err := s.findCredential(ctx, userID)
+	if err != nil && !isNotFound(err) {
+		return err
+	}
+	return s.replacePassword(ctx, userID, credential, password)
+}
+
+// InitialCredential prepara a primeira senha de quem aceita um convite para
+// tenantID, sem gravá-la; devolve nil quando a identidade já tem senha.
+// Deve rodar fora da transação do tenant, cujo RLS esconderia as demais políticas.
+func (s *AuthService) InitialCredential(ctx context.Context, tenantID, userID, password string) (*domain_user.Credential, error) {
+	_, err := s.findCredential(ctx, userID)
+	if err == nil {
+		return nil, nil
+	}
+	if !isNotFound(err) {
+		return nil, err
+	}
+	if password == "" {
+		return nil, errors.NewAppErrorWithDetails("INVALID_INPUT", "Invalid input data", "password is required")
+	}
+
+	policy, err := s.policyFor(ctx, userID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if err := policy.Validate(password); err != nil {
+		return nil, err
+	}
+
+	hash, err := s.hasher.Hash(password)
+	if err != nil {
+		return nil, err
+	}
+	return domain_user.NewCredential(userID, hash, time.Now().UTC()), nil
+}
+
+// SaveCredential grava a credencial preparada por InitialCredential
+func (s *AuthService) SaveCredential(ctx context.Context, credential *domain_user.Credential) error {
+	return s.saveCredential(ctx, credential)
+}
+
+// GetPolicy devolve a política padrão (versão 0) enquanto o tenant não definir a sua
+func (s *AuthService) GetPolicy(ctx context.Context) (*domain_user.PasswordPolicy, error) {
+	var policy *domain_user.PasswordPolicy
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		found, err := s.currentPolicy(ctx)
+		policy = found
+		return err
+	})
+	return policy, err
+}
+
+func (s *AuthService) UpdatePolicy(ctx context.Context, expectedVersion int64, rules domain_user.PasswordPolicyRules) (*domain_user.PasswordPolicy, error) {
+	var policy *domain_user.PasswordPolicy
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		found, err := s.currentPolicy(ctx)
+		if err != nil {
+			return err
+		}
+		if err := domain.ExpectVersion(found, expectedVersion); err != nil {
+			return err
+		}
+		if err := found.Update(rules); err != nil {
+			return err
+		}
+
+		policy = found
+		return s.policies.Save(ctx, found)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// authenticate confere a senha e registra falhas; qualquer erro de credencial vira ErrUnauthorized
+func (s *AuthService) authenticate(ctx context.Context, email, password string) (*domain_user.User, *domain_user.Credential, error) {
+	systemCtx := tenancy.WithSystemScope(ctx)
+
+	var user *domain_user.User
+	var credential *domain_user.Credential
+	err := s.txManager.WithTx(systemCtx, func(ctx context.Context) error {
+		found, err := s.users.FindByEmail(ctx, email)
+		if err != nil {
+			return err
+		}
+		user = found
+
+		credential, err = s.credentials.FindByUser(ctx, found.GetID())
+		return err
+	})
+	if isNotFound(err) {
+		s.equalizeTiming(password)
+		return nil, nil, errors.ErrUnauthorized
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now().UTC()
+	if credential.IsLocked(now) {
+		s.equalizeTiming(password)
+		return nil, nil, errors.ErrUnauthorized
+	}
+
+	ok, err := s.hasher.Verify(password, credential.PasswordHash)
+	if err != nil {
+		log.Printf("auth: unreadable password hash for user %s: %v", user.GetID(), err)
+	}
+	if !ok {
+		if err := s.recordFailure(ctx, user.GetID(), now); err != nil {
+			log.Printf("auth: failed to record login failure for user %s: %v", user.GetID(), err)
+		}
+		return nil, nil, errors.ErrUnauthorized
+	}
+	return user, credential, nil
+}
+
+// recordFailure relê a credencial com FOR UPDATE: a cópia lida antes do hash
+// pode estar desatualizada, e tentativas simultâneas não podem se perder
+func (s *AuthService) recordFailure(ctx context.Context, userID string, now time.Time) error {
+	return s.txManager.WithTx(tenancy.WithSystemScope(ctx), func(ctx context.Context) error {
+		credential, err := s.credentials.LockByUser(ctx, userID)
+		if err != nil {
+			return err
+		}
+		credential.RecordFailure(s.config.MaxFailedAttempts, s.config.LockoutDuration, now)
+		return s.credentials.Save(ctx, credential)
+	})
+}
+
+// replacePassword aplica a política mais restritiva entre os tenants do usuário;
+// credential nil cria a primeira senha.
+func (s *AuthService) replacePassword(ctx context.Context, userID string, credential *domain_user.Credential, password string) error {
+	policy, err := s.policyFor(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if err := policy.Validate(password); err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	hash, err := s.hasher.Hash(password)
+	if err != nil {
+		return err
+	}
+
+	if credential == nil {
+		return s.saveCredential(ctx, domain_user.NewCredential(userID, hash, now))
+	}
+
+	for _, previous := range credential.RecentHashes(policy.HistorySize) {
+		reused, err := s.hasher.Verify(password, previous)
+		if err != nil {
+			continue
+		}
+		if reused {
+			return errors.NewAppErrorWithDetails("INVALID_INPUT", "Invalid input data", "password was used recently")
+		}
+	}
+
+	credential.ChangePassword(hash, policy.HistorySize, now)
+	return s.saveCredential(ctx, credential)
+}
+
+// policyFor combina as políticas de todos os tenants em que o usuário tem acesso
+// e as dos tenants em que ele está entrando (joining)
+func (s *AuthService) policyFor(ctx context.Context, userID string, joining ...string) (*domain_user.PasswordPolicy, error) {
+	systemCtx := tenancy.WithSystemScope(ctx)
+
+	var memberships []*domain_user.Membership
+	err := s.txManager.WithTx(systemCtx, func(ctx context.Context) error {
+		found, err := s.memberships.FindActiveByUser(ctx, userID)
+		memberships = found
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tenantIDs := append(make([]string, 0, len(memberships)+len(joining)), joining...)
+	for _, membership := range memberships {
+		tenantIDs = append(tenantIDs, membership.TenantID)
+	}
+
+	// Cada política é lida no escopo do seu tenant: a de um tenant isolado fica no schema dele
+	policies := make([]*domain_user.PasswordPolicy, 0, len(tenantIDs))
+	for _, tenantID := range tenantIDs {
+		tenant, err := s.tenants.FindByID(systemCtx, tenantID)
+		if err != nil {
+			return nil, err
+		}
+
+		var policy *domain_user.PasswordPolicy
+		err = s.txManager.WithTx(tenancy.WithTenant(ctx, tenant), func(ctx context.Context) error {
+			found, err := s.currentPolicy(ctx)
+			policy = found
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, policy)
+	}
+	return domain_user.StrictestPolicy(policies...), nil
+}
+
+func (s *AuthService) currentPolicy(ctx context.Context) (*domain_user.PasswordPolicy, error) {
+	tenantID, ok := tenancy.TenantIDFromContext(ctx)
+	if !ok {
+		return nil, database.ErrTenantScopeMissing
+	}
+
+	policy, err := s.policies.FindByTenant(ctx)
+	if isNotFound(err) {
+		return domain_user.DefaultPasswordPolicy(tenantID), nil
+	}
+	return policy, err
+}
+
+func (s *AuthService) findCredential(ctx context.Context, userID string) (*domain_user.Credential, error) {
+	var credential *domain_user.Credential
+	err := s.txManager.WithTx(tenancy.WithSystemScope(ctx), func(ctx context.Context) error {
+		found, err := s.credentials.FindByUser(ctx, userID)
+		credential = found
+		return err
+	})
+	return credential, err
+}
+
+func (s *AuthService) saveCredential(ctx context.Context, credential *domain_user.Credential) error {
+	return s.txManager.WithTx(tenancy.WithSystemScope(ctx), func(ctx context.Context) error {
+		return s.credentials.Save(ctx, credential)
+	})
+}
+
+func (s *AuthService) equalizeTiming(password string) {
+	if s.dummyHash != "" {
+		_, _ = s.hasher.Verify(password, s.dummyHash)
+	}
+}
@@ -0,0 +1,185 @@
+package application_user
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/auth"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/errors"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/tenancy"
+	domain_user "github.com/williamkoller/multi-tenant-nexus-manager/internal/user/domain"
+)
+
+const testPassword = "Correct horse 1"
+
+func TestAuthServiceLocksAfterFailedAttempts(t *testing.T) {
+	service, credentials, user := newAuthServiceFixture(t, domain_user.RoleMember)
+	ctx := context.Background()
+
+	for attempt := 1; attempt <= 2; attempt++ {
+		if _, err := service.Login(ctx, "ana@example.com", "wrong password"); !hasCode(err, errors.ErrUnauthorized.Code) {
+			t.Fatalf("Login with a wrong password returned %v, want UNAUTHORIZED", err)
+		}
+		if credentials.credential.FailedAttempts != attempt || credentials.credential.LockedUntil != nil {
+			t.Fatalf("after %d failures: attempts %d, locked until %v", attempt, credentials.credential.FailedAttempts, credentials.credential.LockedUntil)
+		}
+	}
+	if credentials.locks != 2 {
+		t.Fatalf("failures read the credential with LockByUser %d times, want 2", credentials.locks)
+	}
+
+	before := time.Now().UTC()
+	if _, err := service.Login(ctx, "ana@example.com", "wrong password"); !hasCode(err, errors.ErrUnauthorized.Code) {
+		t.Fatalf("third failure returned %v, want UNAUTHORIZED", err)
+	}
+	lockedUntil := credentials.credential.LockedUntil
+	if lockedUntil == nil || lockedUntil.Sub(before) < 15*time.Minute || lockedUntil.Sub(before) > 15*time.Minute+time.Second {
+		t.Fatalf("locked until %v, want 15 minutes from now", lockedUntil)
+	}
+
+	// Durante o bloqueio nem a senha correta entra, e a falha não é contada
+	if _, err := service.Login(ctx, "ana@example.com", testPassword); !hasCode(err, errors.ErrUnauthorized.Code) {
+		t.Fatalf("Login while locked returned %v, want UNAUTHORIZED", err)
+	}
+	if credentials.locks != 3 {
+		t.Fatal("Login while locked recorded a failure")
+	}
+
+	expired := time.Now().UTC().Add(-time.Second)
+	credentials.credential.LockedUntil = &expired
+	result, err := service.Login(ctx, "ana@example.com", testPassword)
+	if err != nil {
+		t.Fatalf("Login after the lockout window returned %v", err)
+	}
+	if result.User != user || len(result.Tenants) != 1 {
+		t.Fatalf("Login = %+v", result)
+	}
+	if credentials.credential.LockedUntil != nil || credentials.credential.FailedAttempts != 0 || credentials.credential.LastLoginAt == nil {
+		t.Fatalf("successful login left credential %+v", credentials.credential)
+	}
+}
+
+func TestAuthServiceSetPasswordRequiresAdministrator(t *testing.T) {
+	cases := []struct {
+		name      string
+		role      domain_user.Role
+		principal []string
+		tenantID  string
+		shared    bool
+		allowed   bool
+	}{
+		{"member", domain_user.RoleMember, []string{string(domain_user.RoleMember)}, "tenant-1", false, false},
+		{"admin of another tenant", domain_user.RoleMember, []string{string(domain_user.RoleAdmin)}, "tenant-2", false, false},
+		{"identity shared with another tenant", domain_user.RoleMember, []string{string(domain_user.RoleAdmin)}, "tenant-1", true, false},
+		{"admin resetting an owner", domain_user.RoleOwner, []string{string(domain_user.RoleAdmin)}, "tenant-1", false, false},
+		{"admin of the home tenant", domain_user.RoleMember, []string{string(domain_user.RoleAdmin)}, "tenant-1", false, true},
+		{"owner resetting an owner", domain_user.RoleOwner, []string{string(domain_user.RoleOwner)}, "tenant-1", false, true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			service, credentials, user := newAuthServiceFixture(t, tc.role)
+			if tc.shared {
+				other := &domain_user.Membership{TenantID: "tenant-2", UserID: user.GetID(), Role: domain_user.RoleMember, Status: domain_user.MembershipActive}
+				other.Initialize()
+				memberships := service.memberships.(fakeMemberships)
+				service.memberships = fakeMemberships{active: append(memberships.active, other)}
+			}
+			lockedUntil := time.Now().UTC().Add(time.Hour)
+			credentials.credential.LockedUntil = &lockedUntil
+			previousHash := credentials.credential.PasswordHash
+
+			ctx := tenancy.WithTenant(context.Background(), fakeTenant{id: tc.tenantID})
+			ctx = auth.WithPrincipal(ctx, auth.Principal{UserID: "admin-1", TenantID: tc.tenantID, Roles: tc.principal})
+
+			err := service.SetPassword(ctx, user.GetID(), "Brand new pass 2")
+			if !tc.allowed {
+				if !hasCode(err, errors.ErrForbidden.Code) {
+					t.Fatalf("SetPassword returned %v, want FORBIDDEN", err)
+				}
+				if credentials.credential.PasswordHash != previousHash {
+					t.Fatal("password changed without permission")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("SetPassword returned %v", err)
+			}
+			if credentials.credential.PasswordHash == previousHash || credentials.credential.LockedUntil != nil {
+				t.Fatalf("SetPassword did not replace and unlock the credential: %+v", credentials.credential)
+			}
+		})
+	}
+}
+
+func newAuthServiceFixture(t *testing.T, role domain_user.Role) (*AuthService, *fakeCredentials, *domain_user.User) {
+	t.Helper()
+
+	hasher := auth.NewPasswordHasher(auth.Argon2Params{Memory: 1024, Iterations: 1, Threads: 1, SaltLength: 16, KeyLength: 32})
+	hash, err := hasher.Hash(testPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user := &domain_user.User{HomeTenantID: "tenant-1", IsActive: true}
+	user.Initialize()
+	membership := &domain_user.Membership{TenantID: "tenant-1", UserID: user.GetID(), Role: role, Status: domain_user.MembershipActive}
+	membership.Initialize()
+
+	credentials := &fakeCredentials{credential: domain_user.NewCredential(user.GetID(), hash, time.Now().UTC())}
+	service := NewAuthService(
+		fakeUsers{user: user},
+		fakeMemberships{active: []*domain_user.Membership{membership}},
+		credentials,
+		fakePolicies{},
+		fakeTenants{},
+		fakeTxManager{},
+		hasher,
+		AuthConfig{MaxFailedAttempts: 3, LockoutDuration: 15 * time.Minute},
+	)
+	return service, credentials, user
+}
+
+func (r fakeUsers) FindByEmail(_ context.Context, email string) (*domain_user.User, error) {
+	if email != "ana@example.com" {
+		return nil, errors.ErrNotFound
+	}
+	return r.user, nil
+}
+
+func (r fakeMemberships) FindCurrentByUser(ctx context.Context, userID string) ([]*domain_user.Membership, error) {
+	return r.FindActiveByUser(ctx, userID)
+}
+
+// fakeCredentials conta as leituras com LockByUser, usadas só para registrar falhas
+type fakeCredentials struct {
+	credential *domain_user.Credential
+	locks      int
+}
+
+func (r *fakeCredentials) Save(_ context.Context, credential *domain_user.Credential) error {
+	r.credential = credential
+	return nil
+}
+
+func (r *fakeCredentials) FindByUser(_ context.Context, userID string) (*domain_user.Credential, error) {
+	if r.credential == nil || r.credential.UserID != userID {
+		return nil, errors.ErrNotFound
+	}
+	return r.credential, nil
+}
+
+func (r *fakeCredentials) LockByUser(ctx context.Context, userID string) (*domain_user.Credential, error) {
+	r.locks++
+	return r.FindByUser(ctx, userID)
+}
+
+type fakePolicies struct {
+	domain_user.PasswordPolicyRepository
+}
+
+func (fakePolicies) FindByTenant(context.Context) (*domain_user.PasswordPolicy, error) {
+	return nil, errors.ErrNotFound
+}
@@ -0,0 +1,546 @@
+package application_user
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"time"
+
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/database"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/domain"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/errors"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/events"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/mail"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/outbox"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/tenancy"
+	domain_user "github.com/williamkoller/multi-tenant-nexus-manager/internal/user/domain"
+)
+
+type InvitationConfig struct {
+	TTL time.Duration
+	// AcceptURL recebe o token como query string (?token=...)
+	AcceptURL string
+	// TokenKey deriva os tokens enviados; precisa ser o mesmo em todas as instâncias
+	TokenKey domain_user.InvitationTokenKey
+	// SweepInterval e SweepBatch controlam a expiração de convites vencidos
+	SweepInterval time.Duration
+	SweepBatch    int
+}
+
+func DefaultInvitationConfig() InvitationConfig {
+	return InvitationConfig{
+		TTL:           7 * 24 * time.Hour,
+		AcceptURL:     "http://localhost:3000/invitations/accept",
+		SweepInterval: time.Minute,
+		SweepBatch:    100,
+	}
+}
+
+type InviteInput struct {
+	Email     string
+	Role      string
+	InvitedBy string
+}
+
+// AcceptInvitationInput traz os dados cadastrais usados apenas quando o e-mail
+// ainda não tem identidade; para usuários existentes eles são ignorados.
+// Password só é usado quando a identidade ainda não tem senha.
+type AcceptInvitationInput struct {
+	Token    string
+	CPF      string
+	Phone    string
+	FullName string
+	Password string
+}
+
+// CredentialIssuer cria a senha de quem aceita um convite; implementado pelo AuthService
+type CredentialIssuer interface {
+	InitialCredential(ctx context.Context, tenantID, userID, password string) (*domain_user.Credential, error)
+	SaveCredential(ctx context.Context, credential *domain_user.Credential) error
+}
+
+type AcceptedInvitation struct {
+	Invitation *domain_user.Invitation
+	User       *domain_user.User
+	Membership *domain_user.Membership
+}
+
+// InvitationService convida e-mails para o tenant e transforma o aceite em membership.
+// O e-mail sai de SendInvitation, que trata o invitation.sent publicado pelo
+// relay; assim ele só é enviado depois do commit e é refeito se o envio falhar.
+type InvitationService struct {
+	invitations domain_user.InvitationRepository
+	users       domain_user.Repository
+	memberships domain_user.MembershipRepository
+	credentials CredentialIssuer
+	tenants     tenancy.Loader
+	txManager   database.TxManager
+	outbox      *outbox.Outbox
+	sender      mail.Sender
+	config      InvitationConfig
+}
+
+func NewInvitationService(
+	invitations domain_user.InvitationRepository,
+	users domain_user.Repository,
+	memberships domain_user.MembershipRepository,
+	credentials CredentialIssuer,
+	tenants tenancy.Loader,
+	txManager database.TxManager,
+	outbox *outbox.Outbox,
+	sender mail.Sender,
+	config InvitationConfig,
+) *InvitationService {
+	defaults := DefaultInvitationConfig()
+	if config.TTL <= 0 {
+		config.TTL = defaults.TTL
+	}
+	if config.AcceptURL == "" {
+		config.AcceptURL = defaults.AcceptURL
+	}
+	if config.SweepInterval <= 0 {
+		config.SweepInterval = defaults.SweepInterval
+	}
+	if config.SweepBatch <= 0 {
+		config.SweepBatch = defaults.SweepBatch
+	}
+	return &InvitationService{
+		invitations: invitations,
+		users:       users,
+		memberships: memberships,
+		credentials: credentials,
+		tenants:     tenants,
+		txManager:   txManager,
+		outbox:      outbox,
+		sender:      sender,
+		config:      config,
+	}
+}
+
+func (s *InvitationService) Invite(ctx context.Context, input InviteInput) (*domain_user.Invitation, error) {
+	tenant, ok := tenancy.FromContext(ctx)
+	if !ok {
+		return nil, database.ErrTenantScopeMissing
+	}
+
+	role, err := grantableRole(ctx, input.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	invitation, err := domain_user.NewInvitation(tenant.GetID(), input.Email, role, input.InvitedBy, s.config.TTL, s.config.TokenKey)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		if err := s.ensureNotMember(ctx, invitation.Email.String()); err != nil {
+			return err
+		}
+
+		_, err := s.invitations.FindPendingByEmail(ctx, invitation.Email.String())
+		if err == nil {
+			return errors.NewAppErrorWithDetails("CONFLICT", "Resource conflict", "a pending invitation already exists for this email; resend it instead")
+		}
+		if !isNotFound(err) {
+			return err
+		}
+
+		return s.save(ctx, invitation)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return invitation, nil
+}
+
+// Resend troca o token e renova a validade; o link enviado anteriormente deixa de funcionar
+func (s *InvitationService) Resend(ctx context.Context, id string, expectedVersion int64) (*domain_user.Invitation, error) {
+	var invitation *domain_user.Invitation
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		found, err := s.invitations.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := domain.ExpectVersion(found, expectedVersion); err != nil {
+			return err
+		}
+
+		if err := found.Resend(s.config.TTL, time.Now().UTC(), s.config.TokenKey); err != nil {
+			return err
+		}
+
+		invitation = found
+		return s.save(ctx, found)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return invitation, nil
+}
+
+func (s *InvitationService) Revoke(ctx context.Context, id string, expectedVersion int64) (*domain_user.Invitation, error) {
+	var invitation *domain_user.Invitation
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		found, err := s.invitations.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := domain.ExpectVersion(found, expectedVersion); err != nil {
+			return err
+		}
+		if err := found.Revoke(time.Now().UTC()); err != nil {
+			return err
+		}
+
+		invitation = found
+		return s.save(ctx, found)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return invitation, nil
+}
+
+func (s *InvitationService) Get(ctx context.Context, id string) (*domain_user.Invitation, error) {
+	var invitation *domain_user.Invitation
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		found, err := s.invitations.FindByID(ctx, id)
+		invitation = found
+		return err
+	})
+	return invitation, err
+}
+
+func (s *InvitationService) List(ctx context.Context, filter domain.Filter) (domain.Page[*domain_user.Invitation], error) {
+	var page domain.Page[*domain_user.Invitation]
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		found, err := s.invitations.FindAll(ctx, filter)
+		page = found
+		return err
+	})
+	return page, err
+}
+
+// Accept não exige tenant no contexto: o convite localizado pelo token define o tenant.
+// Cria a identidade quando o e-mail ainda não existe ou vincula a existente.
+func (s *InvitationService) Accept(ctx context.Context, input AcceptInvitationInput) (*AcceptedInvitation, error) {
+	systemCtx := tenancy.WithSystemScope(ctx)
+
+	var invitation *domain_user.Invitation
+	err := s.txManager.WithTx(systemCtx, func(ctx context.Context) error {
+		found, err := s.invitations.FindByToken(ctx, input.Token)
+		invitation = found
+		return err
+	})
+	if err != nil {
+		if isNotFound(err) {
+			return nil, domain_user.ErrInvitationNotFound
+		}
+		return nil, err
+	}
+
+	tenant, err := s.tenants.FindByID(systemCtx, invitation.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	if !tenant.IsActive() {
+		return nil, errors.NewAppErrorWithDetails("FORBIDDEN", "Access forbidden", "tenant is not active")
+	}
+
+	// A senha é validada e o hash calculado antes de abrir a transação do tenant
+	user, created, err := s.identityFor(systemCtx, invitation, input)
+	if err != nil {
+		return nil, err
+	}
+	credential, err := s.credentials.InitialCredential(ctx, invitation.TenantID, user.GetID(), input.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	var accepted *AcceptedInvitation
+	var aggregates []domain.AggregateRoot
+	err = s.txManager.WithTx(tenancy.WithTenant(ctx, tenant), func(ctx context.Context) error {
+		current, err := s.invitations.FindByID(ctx, invitation.GetID())
+		if err != nil {
+			return err
+		}
+		now := time.Now().UTC()
+		if err := current.CanAccept(now); err != nil {
+			return err
+		}
+		if err := s.ensureInviterCanGrant(ctx, current); err != nil {
+			return err
+		}
+
+		if created {
+			// O aceite comprova o e-mail e substitui a ativação manual
+			user.Activate()
+		}
+		user.VerifyEmail()
+
+		membership, err := s.membershipFor(ctx, current, user)
+		if err != nil {
+			return err
+		}
+		if err := membership.Accept(); err != nil {
+			return err
+		}
+		if err := current.Accept(input.Token, user.GetID(), now); err != nil {
+			return err
+		}
+
+		// Usuários existentes só são visíveis ao repositório depois que a membership existe
+		if created {
+			if err := s.users.Save(ctx, user); err != nil {
+				return err
+			}
+		}
+		if err := s.memberships.Save(ctx, membership); err != nil {
+			return err
+		}
+		if !created && len(user.GetDomainEvents()) > 0 {
+			if err := s.users.Save(ctx, user); err != nil {
+				return err
+			}
+		}
+		if credential != nil {
+			if err := s.credentials.SaveCredential(ctx, credential); err != nil {
+				return err
+			}
+		}
+		if err := s.invitations.Save(ctx, current); err != nil {
+			return err
+		}
+
+		aggregates = []domain.AggregateRoot{user, membership, current}
+		for _, aggregate := range aggregates {
+			if err := s.outbox.Record(ctx, aggregate); err != nil {
+				return err
+			}
+		}
+
+		accepted = &AcceptedInvitation{Invitation: current, User: user, Membership: membership}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, aggregate := range aggregates {
+		aggregate.ClearDomainEvents()
+	}
+	return accepted, nil
+}
+
+// ExpirePending marca como expirados os convites vencidos de todos os tenants
+func (s *InvitationService) ExpirePending(ctx context.Context) (int, error) {
+	systemCtx := tenancy.WithSystemScope(ctx)
+	now := time.Now().UTC()
+
+	var due []*domain_user.Invitation
+	err := s.txManager.WithTx(systemCtx, func(ctx context.Context) error {
+		found, err := s.invitations.FindExpired(ctx, now, s.config.SweepBatch)
+		due = found
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, candidate := range due {
+		tenant, err := s.tenants.FindByID(systemCtx, candidate.TenantID)
+		if err != nil {
+			log.Printf("invitations: failed to load tenant %s: %v", candidate.TenantID, err)
+			continue
+		}
+
+		err = s.txManager.WithTx(tenancy.WithTenant(ctx, tenant), func(ctx context.Context) error {
+			invitation, err := s.invitations.FindByID(ctx, candidate.GetID())
+			if err != nil {
+				return err
+			}
+			if !invitation.Expire(now) {
+				return nil
+			}
+			return s.save(ctx, invitation)
+		})
+		if err != nil {
+			log.Printf("invitations: failed to expire %s: %v", candidate.GetID(), err)
+			continue
+		}
+		expired++
+	}
+	return expired, nil
+}
+
+// Run expira convites periodicamente até o contexto ser cancelado
+func (s *InvitationService) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.config.SweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.ExpirePending(ctx); err != nil {
+				log.Printf("invitations: sweep failed: %v", err)
+			}
+		}
+	}
+}
+
+// ensureInviterCanGrant repete no aceite a checagem feita no convite: quem
+// convidou precisa continuar owner ou admin ativo do tenant, e owner para
+// convites de owner.
+func (s *InvitationService) ensureInviterCanGrant(ctx context.Context, invitation *domain_user.Invitation) error {
+	forbidden := errors.NewAppErrorWithDetails(errors.ErrForbidden.Code, errors.ErrForbidden.Message,
+		"the inviter can no longer grant this role; ask for a new invitation")
+	if invitation.InvitedBy == "" {
+		return forbidden
+	}
+
+	inviter, err := s.memberships.FindByUser(ctx, invitation.InvitedBy)
+	if isNotFound(err) {
+		return forbidden
+	}
+	if err != nil {
+		return err
+	}
+	if inviter.Status != domain_user.MembershipActive {
+		return forbidden
+	}
+
+	switch inviter.Role {
+	case domain_user.RoleOwner:
+		return nil
+	case domain_user.RoleAdmin:
+		if invitation.Role != domain_user.RoleOwner {
+			return nil
+		}
+	}
+	return forbidden
+}
+
+func (s *InvitationService) ensureNotMember(ctx context.Context, email string) error {
+	user, err := s.users.FindByEmail(ctx, email)
+	if isNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	membership, err := s.memberships.FindByUser(ctx, user.GetID())
+	if isNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if membership.Status != domain_user.MembershipRevoked {
+		return errors.NewAppErrorWithDetails("CONFLICT", "Resource conflict", "user is already a member of this tenant")
+	}
+	return nil
+}
+
+// identityFor roda com escopo de sistema; o users.Save da transação do tenant
+// barra com CONFLICT uma identidade criada em paralelo com o mesmo e-mail
+func (s *InvitationService) identityFor(ctx context.Context, invitation *domain_user.Invitation, input AcceptInvitationInput) (*domain_user.User, bool, error) {
+	var user *domain_user.User
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		found, err := s.users.FindByEmail(ctx, invitation.Email.String())
+		user = found
+		return err
+	})
+	if err == nil {
+		return user, false, nil
+	}
+	if !isNotFound(err) {
+		return nil, false, err
+	}
+
+	user, err = domain_user.NewUser(invitation.TenantID, invitation.Email.String(), input.CPF, input.Phone, input.FullName)
+	if err != nil {
+		return nil, false, err
+	}
+	return user, true, nil
+}
+
+// membershipFor reaproveita memberships revogadas; qualquer outra já dá acesso ao tenant
+func (s *InvitationService) membershipFor(ctx context.Context, invitation *domain_user.Invitation, user *domain_user.User) (*domain_user.Membership, error) {
+	membership, err := s.memberships.FindByUser(ctx, user.GetID())
+	if isNotFound(err) {
+		return domain_user.NewInvitedMembership(invitation.TenantID, user.GetID(), invitation.Role, invitation.InvitedBy)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := membership.Reinvite(invitation.Role, invitation.InvitedBy); err != nil {
+		return nil, err
+	}
+	return membership, nil
+}
+
+// Subscribe registra o envio dos convites no bus alimentado pelo relay do outbox
+func (s *InvitationService) Subscribe(bus events.EventBus) func() {
+	return bus.Subscribe(domain_user.EventInvitationSent, s.SendInvitation,
+		events.WithName("invitation.mailer"), events.WithRetry(3, time.Second))
+}
+
+// SendInvitation monta o link a partir do nonce do evento. Envios substituídos
+// por um reenvio, ou de convites que já saíram de pending, são ignorados.
+func (s *InvitationService) SendInvitation(ctx context.Context, event domain.DomainEvent) error {
+	sent, ok := event.GetEventData().(domain_user.InvitationSent)
+	if !ok {
+		return fmt.Errorf("unexpected %s payload %T", event.GetEventType(), event.GetEventData())
+	}
+	tenant, ok := tenancy.FromContext(ctx)
+	if !ok {
+		return database.ErrTenantScopeMissing
+	}
+	if sent.Nonce == "" {
+		log.Printf("invitations: %s has no nonce, resend the invitation", event.GetAggregateID())
+		return nil
+	}
+
+	var invitation *domain_user.Invitation
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		found, err := s.invitations.FindByID(ctx, event.GetAggregateID())
+		invitation = found
+		return err
+	})
+	if isNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	token := s.config.TokenKey.Token(invitation.GetID(), sent.Nonce)
+	if invitation.Status != domain_user.InvitationStatusPending || !invitation.MatchesToken(token) {
+		return nil
+	}
+	return s.sender.Send(ctx, s.message(tenant, invitation, token))
+}
+
+func (s *InvitationService) save(ctx context.Context, invitation *domain_user.Invitation) error {
+	return s.outbox.Save(ctx, invitation, func(ctx context.Context) error {
+		return s.invitations.Save(ctx, invitation)
+	})
+}
+
+func (s *InvitationService) message(tenant tenancy.Tenant, invitation *domain_user.Invitation, token string) mail.Message {
+	link := s.config.AcceptURL + "?token=" + url.QueryEscape(token)
+	return mail.Message{
+		To:      invitation.Email.String(),
+		Subject: fmt.Sprintf("You have been invited to %s", tenant.GetSlug()),
+		Body: fmt.Sprintf(
+			"You have been invited to join %s as %s.\n\nAccept the invitation: %s\n\nThis link expires at %s.\n",
+			tenant.GetSlug(), invitation.Role, link, invitation.ExpiresAt.Format(time.RFC1123),
+		),
+	}
+}
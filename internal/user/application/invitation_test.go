@@ -0,0 +1,115 @@
+package application_user
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/auth"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/errors"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/tenancy"
+	domain_user "github.com/williamkoller/multi-tenant-nexus-manager/internal/user/domain"
+)
+
+func TestInvitationServiceInviteOwnerRequiresOwner(t *testing.T) {
+	service := NewInvitationService(nil, nil, nil, nil, fakeTenants{}, fakeTxManager{}, nil, nil, testInvitationConfig())
+	ctx := tenancy.WithTenant(context.Background(), fakeTenant{id: "tenant-1"})
+	ctx = auth.WithPrincipal(ctx, auth.Principal{UserID: "admin-1", TenantID: "tenant-1", Roles: []string{string(domain_user.RoleAdmin)}})
+
+	_, err := service.Invite(ctx, InviteInput{Email: "ana@example.com", Role: string(domain_user.RoleOwner), InvitedBy: "admin-1"})
+	if !hasCode(err, errors.ErrForbidden.Code) {
+		t.Fatalf("admin inviting an owner returned %v, want FORBIDDEN", err)
+	}
+}
+
+func TestInvitationServiceAcceptRechecksInviter(t *testing.T) {
+	cases := []struct {
+		name    string
+		invited domain_user.Role
+		inviter *domain_user.Membership
+		allowed bool
+	}{
+		{"admin still grants member", domain_user.RoleMember, inviterMembership(domain_user.RoleAdmin, domain_user.MembershipActive), true},
+		{"owner grants owner", domain_user.RoleOwner, inviterMembership(domain_user.RoleOwner, domain_user.MembershipActive), true},
+		{"admin cannot grant owner", domain_user.RoleOwner, inviterMembership(domain_user.RoleAdmin, domain_user.MembershipActive), false},
+		{"demoted inviter", domain_user.RoleAdmin, inviterMembership(domain_user.RoleMember, domain_user.MembershipActive), false},
+		{"suspended inviter", domain_user.RoleMember, inviterMembership(domain_user.RoleOwner, domain_user.MembershipSuspended), false},
+		{"inviter left the tenant", domain_user.RoleMember, nil, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			invitation, err := domain_user.NewInvitation("tenant-1", "ana@example.com", tc.invited, "inviter-1", time.Hour, testInvitationConfig().TokenKey)
+			if err != nil {
+				t.Fatal(err)
+			}
+			memberships := &fakeInviterMemberships{}
+			if tc.inviter != nil {
+				memberships.byUser = map[string]*domain_user.Membership{"inviter-1": tc.inviter}
+			}
+			service := NewInvitationService(nil, nil, memberships, nil, fakeTenants{}, fakeTxManager{}, nil, nil, testInvitationConfig())
+
+			err = service.ensureInviterCanGrant(context.Background(), invitation)
+			if tc.allowed && err != nil {
+				t.Fatalf("ensureInviterCanGrant returned %v", err)
+			}
+			if !tc.allowed && !hasCode(err, errors.ErrForbidden.Code) {
+				t.Fatalf("ensureInviterCanGrant returned %v, want FORBIDDEN", err)
+			}
+		})
+	}
+}
+
+func TestInvitationTokenIsDerivedFromKeyAndExpires(t *testing.T) {
+	key := testInvitationConfig().TokenKey
+	invitation, err := domain_user.NewInvitation("tenant-1", "ana@example.com", domain_user.RoleMember, "inviter-1", time.Hour, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sent, ok := invitation.GetDomainEvents()[0].GetEventData().(domain_user.InvitationSent)
+	if !ok || sent.Nonce == "" {
+		t.Fatalf("invitation.sent payload = %+v, want a nonce", invitation.GetDomainEvents()[0].GetEventData())
+	}
+
+	token := key.Token(invitation.GetID(), sent.Nonce)
+	if !invitation.MatchesToken(token) {
+		t.Fatal("token derived from the event nonce does not match the invitation")
+	}
+	if invitation.MatchesToken(domain_user.InvitationTokenKey("other-key").Token(invitation.GetID(), sent.Nonce)) {
+		t.Fatal("token derived with another key matched the invitation")
+	}
+
+	if err := invitation.CanAccept(invitation.ExpiresAt.Add(-time.Second)); err != nil {
+		t.Fatalf("CanAccept before expiry returned %v", err)
+	}
+	if err := invitation.Accept(token, "user-1", invitation.ExpiresAt); err != domain_user.ErrInvitationExpired {
+		t.Fatalf("Accept at expiry returned %v, want ErrInvitationExpired", err)
+	}
+	if !invitation.Expire(invitation.ExpiresAt) || invitation.Status != domain_user.InvitationStatusExpired {
+		t.Fatalf("Expire left status %s", invitation.Status)
+	}
+}
+
+func testInvitationConfig() InvitationConfig {
+	config := DefaultInvitationConfig()
+	config.TokenKey = domain_user.InvitationTokenKey("test-invitation-key")
+	return config
+}
+
+func inviterMembership(role domain_user.Role, status domain_user.MembershipStatus) *domain_user.Membership {
+	membership := &domain_user.Membership{TenantID: "tenant-1", UserID: "inviter-1", Role: role, Status: status}
+	membership.Initialize()
+	return membership
+}
+
+type fakeInviterMemberships struct {
+	domain_user.MembershipRepository
+	byUser map[string]*domain_user.Membership
+}
+
+func (r *fakeInviterMemberships) FindByUser(_ context.Context, userID string) (*domain_user.Membership, error) {
+	if membership, ok := r.byUser[userID]; ok {
+		return membership, nil
+	}
+	return nil, errors.ErrNotFound
+}
@@ -0,0 +1,159 @@
+package application_user
+
+import (
+	"context"
+	"log"
+
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/database"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/domain"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/errors"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/tenancy"
+	domain_user "github.com/williamkoller/multi-tenant-nexus-manager/internal/user/domain"
+)
+
+// TenantAccess descreve um tenant em que o usuário pode entrar
+type TenantAccess struct {
+	TenantID     string           `json:"tenant_id"`
+	Slug         string           `json:"slug"`
+	MembershipID string           `json:"membership_id"`
+	Role         domain_user.Role `json:"role"`
+}
+
+func (s *Service) ListMemberships(ctx context.Context, filter domain.Filter) (domain.Page[*domain_user.Membership], error) {
+	var page domain.Page[*domain_user.Membership]
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		found, err := s.memberships.FindAll(ctx, filter)
+		page = found
+		return err
+	})
+	return page, err
+}
+
+func (s *Service) GetMembership(ctx context.Context, id string) (*domain_user.Membership, error) {
+	var membership *domain_user.Membership
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		found, err := s.memberships.FindByID(ctx, id)
+		membership = found
+		return err
+	})
+	return membership, err
+}
+
+func (s *Service) ChangeRole(ctx context.Context, id string, expectedVersion int64, role string) (*domain_user.Membership, error) {
+	return s.modifyMembership(ctx, id, expectedVersion, func(membership *domain_user.Membership) error {
+		granted, err := grantableRole(ctx, role)
+		if err != nil {
+			return err
+		}
+		return membership.ChangeRole(granted)
+	})
+}
+
+func (s *Service) SuspendMembership(ctx context.Context, id string, expectedVersion int64) (*domain_user.Membership, error) {
+	return s.modifyMembership(ctx, id, expectedVersion, func(membership *domain_user.Membership) error {
+		return membership.Suspend()
+	})
+}
+
+func (s *Service) ReactivateMembership(ctx context.Context, id string, expectedVersion int64) (*domain_user.Membership, error) {
+	return s.modifyMembership(ctx, id, expectedVersion, func(membership *domain_user.Membership) error {
+		return membership.Reactivate()
+	})
+}
+
+func (s *Service) RevokeMembership(ctx context.Context, id string, expectedVersion int64) (*domain_user.Membership, error) {
+	return s.modifyMembership(ctx, id, expectedVersion, func(membership *domain_user.Membership) error {
+		membership.Revoke()
+		return nil
+	})
+}
+
+// AccessibleTenants lista os tenants ativos em que o usuário tem membership ativa; usado no login
+func (s *Service) AccessibleTenants(ctx context.Context, userID string) ([]TenantAccess, error) {
+	return accessibleTenants(ctx, s.txManager, s.memberships, s.tenants, userID)
+}
+
+func accessibleTenants(ctx context.Context, txManager database.TxManager, memberships domain_user.MembershipRepository, tenants tenancy.Loader, userID string) ([]TenantAccess, error) {
+	systemCtx := tenancy.WithSystemScope(ctx)
+
+	var active []*domain_user.Membership
+	err := txManager.WithTx(systemCtx, func(ctx context.Context) error {
+		found, err := memberships.FindActiveByUser(ctx, userID)
+		active = found
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	accesses := make([]TenantAccess, 0, len(active))
+	for _, membership := range active {
+		tenant, err := tenants.FindByID(systemCtx, membership.TenantID)
+		if err != nil {
+			log.Printf("user memberships: failed to load tenant %s: %v", membership.TenantID, err)
+			continue
+		}
+		if !tenant.IsActive() {
+			continue
+		}
+		accesses = append(accesses, TenantAccess{
+			TenantID:     tenant.GetID(),
+			Slug:         tenant.GetSlug(),
+			MembershipID: membership.GetID(),
+			Role:         membership.Role,
+		})
+	}
+	return accesses, nil
+}
+
+// ensureAnotherOwner impede que o tenant fique sem owner ativo
+func (s *Service) ensureAnotherOwner(ctx context.Context, leaving *domain_user.Membership) error {
+	owners, err := s.memberships.LockActiveOwners(ctx)
+	if err != nil {
+		return err
+	}
+	for _, owner := range owners {
+		if owner.GetID() != leaving.GetID() {
+			return nil
+		}
+	}
+	return errors.NewAppErrorWithDetails("CONFLICT", "Resource conflict", "the tenant must keep at least one active owner")
+}
+
+func isActiveOwner(membership *domain_user.Membership) bool {
+	return membership.Role == domain_user.RoleOwner && membership.Status == domain_user.MembershipActive
+}
+
+func (s *Service) modifyMembership(ctx context.Context, id string, expectedVersion int64, change func(membership *domain_user.Membership) error) (*domain_user.Membership, error) {
+	var membership *domain_user.Membership
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		found, err := s.memberships.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := domain.ExpectVersion(found, expectedVersion); err != nil {
+			return err
+		}
+		if found.Role == domain_user.RoleOwner {
+			if err := requireOwner(ctx); err != nil {
+				return err
+			}
+		}
+		wasOwner := isActiveOwner(found)
+		if err := change(found); err != nil {
+			return err
+		}
+		if wasOwner && !isActiveOwner(found) {
+			if err := s.ensureAnotherOwner(ctx, found); err != nil {
+				return err
+			}
+		}
+
+		membership = found
+		return s.saveMembership(ctx, found)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return membership, nil
+}
@@ -0,0 +1,308 @@
+package application_user
+
+import (
+	"context"
+	stderrors "errors"
+
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/auth"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/database"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/domain"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/errors"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/outbox"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/tenancy"
+	domain_user "github.com/williamkoller/multi-tenant-nexus-manager/internal/user/domain"
+)
+
+type CreateUserInput struct {
+	Email    string
+	CPF      string
+	Phone    string
+	FullName string
+	// Role da membership criada no tenant atual; vazio assume member
+	Role string
+}
+
+type UpdateUserInput struct {
+	Email    string
+	Phone    string
+	FullName string
+}
+
+// Service implementa os casos de uso de usuários do tenant no contexto.
+// Escritas passam pelo outbox para que os eventos saiam junto com o commit.
+type Service struct {
+	users       domain_user.Repository
+	memberships domain_user.MembershipRepository
+	tenants     tenancy.Loader
+	txManager   database.TxManager
+	outbox      *outbox.Outbox
+}
+
+func NewService(users domain_user.Repository, memberships domain_user.MembershipRepository, tenants tenancy.Loader, txManager database.TxManager, outbox *outbox.Outbox) *Service {
+	return &Service{
+		users:       users,
+		memberships: memberships,
+		tenants:     tenants,
+		txManager:   txManager,
+		outbox:      outbox,
+	}
+}
+
+// errUserUnavailable não diz se o conflito foi de e-mail ou de CPF, e uma
+// identidade já cadastrada só entra em outro tenant aceitando um convite
+var errUserUnavailable = errors.NewAppErrorWithDetails("CONFLICT", "Resource conflict", "user cannot be created; existing users must be invited")
+
+func (s *Service) Create(ctx context.Context, input CreateUserInput) (*domain_user.User, error) {
+	tenantID, ok := tenancy.TenantIDFromContext(ctx)
+	if !ok {
+		return nil, database.ErrTenantScopeMissing
+	}
+
+	role, err := grantableRole(ctx, input.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := domain_user.NewUser(tenantID, input.Email, input.CPF, input.Phone, input.FullName)
+	if err != nil {
+		return nil, err
+	}
+
+	var aggregates []domain.AggregateRoot
+	err = s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		if err := s.users.Save(ctx, user); err != nil {
+			if isConflict(err) {
+				return errUserUnavailable
+			}
+			return err
+		}
+
+		membership, err := domain_user.NewMembership(tenantID, user.GetID(), role)
+		if err != nil {
+			return err
+		}
+		if err := s.memberships.Save(ctx, membership); err != nil {
+			return err
+		}
+
+		aggregates = []domain.AggregateRoot{user, membership}
+		for _, aggregate := range aggregates {
+			if err := s.outbox.Record(ctx, aggregate); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, aggregate := range aggregates {
+		aggregate.ClearDomainEvents()
+	}
+	return user, nil
+}
+
+// grantableRole interpreta o papel pedido (vazio assume member); só owners concedem owner
+func grantableRole(ctx context.Context, value string) (domain_user.Role, error) {
+	if value == "" {
+		return domain_user.RoleMember, nil
+	}
+
+	role, err := domain_user.ParseRole(value)
+	if err != nil {
+		return "", err
+	}
+	if role == domain_user.RoleOwner {
+		if err := requireOwner(ctx); err != nil {
+			return "", err
+		}
+	}
+	return role, nil
+}
+
+// requireOwner reserva a administração de owners a outros owners
+func requireOwner(ctx context.Context) error {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok || !principal.HasRole(string(domain_user.RoleOwner)) {
+		return errors.NewAppErrorWithDetails(errors.ErrForbidden.Code, errors.ErrForbidden.Message, "only owners can manage the owner role")
+	}
+	return nil
+}
+
+func (s *Service) Get(ctx context.Context, id string) (*domain_user.User, error) {
+	var user *domain_user.User
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		found, err := s.users.FindByID(ctx, id)
+		user = found
+		return err
+	})
+	return user, err
+}
+
+func (s *Service) List(ctx context.Context, filter domain.Filter) (domain.Page[*domain_user.User], error) {
+	var page domain.Page[*domain_user.User]
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		found, err := s.users.FindAll(ctx, filter)
+		page = found
+		return err
+	})
+	return page, err
+}
+
+// Update, Activate e Deactivate alteram a identidade global, compartilhada
+// entre tenants; o acesso a um tenant específico muda pela membership. Só
+// Update vale para o próprio usuário: ativar e desativar ficam com quem
+// administra a identidade, senão um usuário desativado poderia se reativar.
+
+func (s *Service) Update(ctx context.Context, id string, expectedVersion int64, input UpdateUserInput) (*domain_user.User, error) {
+	return s.modifyIdentity(ctx, id, expectedVersion, authorizeIdentity, func(user *domain_user.User) error {
+		if err := user.ChangeFullName(input.FullName); err != nil {
+			return err
+		}
+		if err := user.ChangeEmail(input.Email); err != nil {
+			return err
+		}
+		return user.ChangePhone(input.Phone)
+	})
+}
+
+func (s *Service) Activate(ctx context.Context, id string, expectedVersion int64) (*domain_user.User, error) {
+	return s.modifyIdentity(ctx, id, expectedVersion, administerIdentity, func(user *domain_user.User) error {
+		user.Activate()
+		return nil
+	})
+}
+
+func (s *Service) Deactivate(ctx context.Context, id string, expectedVersion int64) (*domain_user.User, error) {
+	return s.modifyIdentity(ctx, id, expectedVersion, administerIdentity, func(user *domain_user.User) error {
+		user.Deactivate()
+		return nil
+	})
+}
+
+// Delete remove o usuário do tenant revogando a membership; a identidade global permanece
+func (s *Service) Delete(ctx context.Context, id string) error {
+	return s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		membership, err := s.memberships.FindByUser(ctx, id)
+		if err != nil {
+			return err
+		}
+		if membership.Role == domain_user.RoleOwner {
+			if err := requireOwner(ctx); err != nil {
+				return err
+			}
+		}
+		if isActiveOwner(membership) {
+			if err := s.ensureAnotherOwner(ctx, membership); err != nil {
+				return err
+			}
+		}
+		membership.Revoke()
+		return s.saveMembership(ctx, membership)
+	})
+}
+
+// modifyIdentity carrega o usuário, confere a permissão e a versão do If-Match
+// e persiste a alteração com seus eventos
+func (s *Service) modifyIdentity(ctx context.Context, id string, expectedVersion int64, authorize identityCheck, change func(user *domain_user.User) error) (*domain_user.User, error) {
+	if err := authorize(ctx, s.txManager, s.users, s.memberships, id); err != nil {
+		return nil, err
+	}
+
+	var user *domain_user.User
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		found, err := s.users.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := domain.ExpectVersion(found, expectedVersion); err != nil {
+			return err
+		}
+		if err := change(found); err != nil {
+			return err
+		}
+
+		user = found
+		return s.save(ctx, found)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// identityCheck é a permissão exigida por modifyIdentity
+type identityCheck func(ctx context.Context, txManager database.TxManager, users domain_user.Repository, memberships domain_user.MembershipRepository, id string) error
+
+// authorizeIdentity libera a identidade para o próprio usuário ou para quem a administra
+func authorizeIdentity(ctx context.Context, txManager database.TxManager, users domain_user.Repository, memberships domain_user.MembershipRepository, id string) error {
+	if principal, ok := auth.PrincipalFromContext(ctx); ok && principal.UserID == id {
+		return nil
+	}
+	return administerIdentity(ctx, txManager, users, memberships, id)
+}
+
+// administerIdentity exige um owner/admin do tenant de origem da identidade,
+// desde que ela não pertença a outro tenant; identidades de owners só por
+// outro owner. Roda antes da transação do tenant, cujo RLS esconderia as
+// demais memberships.
+func administerIdentity(ctx context.Context, txManager database.TxManager, users domain_user.Repository, memberships domain_user.MembershipRepository, id string) error {
+	forbidden := errors.NewAppErrorWithDetails(errors.ErrForbidden.Code, errors.ErrForbidden.Message,
+		"only an administrator of the tenant that solely owns the identity can change it")
+
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok || !(principal.HasRole(string(domain_user.RoleOwner)) || principal.HasRole(string(domain_user.RoleAdmin))) {
+		return forbidden
+	}
+	tenantID, _ := tenancy.TenantIDFromContext(ctx)
+
+	return txManager.WithTx(tenancy.WithSystemScope(ctx), func(ctx context.Context) error {
+		user, err := users.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if user.HomeTenantID != tenantID {
+			return forbidden
+		}
+
+		current, err := memberships.FindCurrentByUser(ctx, id)
+		if err != nil {
+			return err
+		}
+		for _, membership := range current {
+			if membership.TenantID != tenantID {
+				return forbidden
+			}
+			if membership.Role == domain_user.RoleOwner && !principal.HasRole(string(domain_user.RoleOwner)) {
+				return forbidden
+			}
+		}
+		return nil
+	})
+}
+
+func (s *Service) save(ctx context.Context, user *domain_user.User) error {
+	return s.outbox.Save(ctx, user, func(ctx context.Context) error {
+		return s.users.Save(ctx, user)
+	})
+}
+
+func (s *Service) saveMembership(ctx context.Context, membership *domain_user.Membership) error {
+	return s.outbox.Save(ctx, membership, func(ctx context.Context) error {
+		return s.memberships.Save(ctx, membership)
+	})
+}
+
+func isNotFound(err error) bool {
+	return hasCode(err, errors.ErrNotFound.Code)
+}
+
+func isConflict(err error) bool {
+	return hasCode(err, errors.ErrConflict.Code)
+}
+
+func hasCode(err error, code string) bool {
+	var appErr errors.AppError
+	return stderrors.As(err, &appErr) && appErr.Code == code
+}
@@ -0,0 +1,35 @@
+package application_user
+
+import (
+	"context"
+	"testing"
+
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/auth"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/errors"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/tenancy"
+	domain_user "github.com/williamkoller/multi-tenant-nexus-manager/internal/user/domain"
+)
+
+func TestServiceUserCannotReactivateThemselves(t *testing.T) {
+	user := &domain_user.User{HomeTenantID: "tenant-1"}
+	user.Initialize()
+	service := NewService(fakeUsers{user: user}, fakeMemberships{}, fakeTenants{}, fakeTxManager{}, nil)
+
+	ctx := tenancy.WithTenant(context.Background(), fakeTenant{id: "tenant-1"})
+	ctx = auth.WithPrincipal(ctx, auth.Principal{UserID: user.GetID(), TenantID: "tenant-1", Roles: []string{string(domain_user.RoleMember)}})
+
+	if _, err := service.Activate(ctx, user.GetID(), user.GetVersion()); !hasCode(err, errors.ErrForbidden.Code) {
+		t.Fatalf("self Activate returned %v, want FORBIDDEN", err)
+	}
+	if _, err := service.Deactivate(ctx, user.GetID(), user.GetVersion()); !hasCode(err, errors.ErrForbidden.Code) {
+		t.Fatalf("self Deactivate returned %v, want FORBIDDEN", err)
+	}
+	if user.IsActive {
+		t.Fatal("user was reactivated")
+	}
+
+	// Os próprios dados continuam editáveis
+	if err := authorizeIdentity(ctx, fakeTxManager{}, fakeUsers{user: user}, fakeMemberships{}, user.GetID()); err != nil {
+		t.Fatalf("authorizeIdentity for the user themselves returned %v", err)
+	}
+}
@@ -0,0 +1,237 @@
+package application_user
+
+import (
+	"context"
+	"time"
+
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/auth"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/database"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/errors"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/tenancy"
+	domain_user "github.com/williamkoller/multi-tenant-nexus-manager/internal/user/domain"
+)
+
+var ErrInvalidRefreshToken = errors.NewAppErrorWithDetails(
+	errors.ErrUnauthorized.Code,
+	errors.ErrUnauthorized.Message,
+	"invalid or expired refresh token",
+)
+
+type TokenConfig struct {
+	RefreshTTL time.Duration
+}
+
+func DefaultTokenConfig() TokenConfig {
+	return TokenConfig{RefreshTTL: 30 * 24 * time.Hour}
+}
+
+type TokenPair struct {
+	AccessToken      string
+	ExpiresIn        time.Duration
+	RefreshToken     string
+	RefreshExpiresAt time.Time
+	Tenant           TenantAccess
+}
+
+// TokenService emite o par access/refresh para um tenant em que o usuário tem acesso.
+// Cada renovação consome o refresh token; reapresentá-lo revoga a família.
+type TokenService struct {
+	users         domain_user.Repository
+	memberships   domain_user.MembershipRepository
+	tenants       tenancy.Loader
+	refreshTokens domain_user.RefreshTokenRepository
+	txManager     database.TxManager
+	issuer        *auth.TokenIssuer
+	config        TokenConfig
+}
+
+func NewTokenService(
+	users domain_user.Repository,
+	memberships domain_user.MembershipRepository,
+	tenants tenancy.Loader,
+	refreshTokens domain_user.RefreshTokenRepository,
+	txManager database.TxManager,
+	issuer *auth.TokenIssuer,
+	config TokenConfig,
+) *TokenService {
+	if config.RefreshTTL <= 0 {
+		config.RefreshTTL = DefaultTokenConfig().RefreshTTL
+	}
+
+	return &TokenService{
+		users:         users,
+		memberships:   memberships,
+		tenants:       tenants,
+		refreshTokens: refreshTokens,
+		txManager:     txManager,
+		issuer:        issuer,
+		config:        config,
+	}
+}
+
+// IssueForLogin usa o tenant pedido ou, sem preferência, o primeiro tenant acessível
+func (s *TokenService) IssueForLogin(ctx context.Context, login *LoginResult, tenantID string) (*TokenPair, error) {
+	access, err := selectTenant(login.Tenants, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	pair, _, err := s.issue(ctx, login.User.GetID(), access, "")
+	return pair, err
+}
+
+// Switch troca o tenant do principal autenticado, iniciando uma nova família de refresh tokens
+func (s *TokenService) Switch(ctx context.Context, principal auth.Principal, tenantID string) (*TokenPair, error) {
+	accesses, err := accessibleTenants(ctx, s.txManager, s.memberships, s.tenants, principal.UserID)
+	if err != nil {
+		return nil, err
+	}
+	access, err := selectTenant(accesses, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	pair, _, err := s.issue(ctx, principal.UserID, access, "")
+	return pair, err
+}
+
+func (s *TokenService) Refresh(ctx context.Context, token string) (*TokenPair, error) {
+	systemCtx := tenancy.WithSystemScope(ctx)
+	now := time.Now().UTC()
+
+	var pair *TokenPair
+	rejected := false
+	err := s.txManager.WithTx(systemCtx, func(ctx context.Context) error {
+		current, err := s.refreshTokens.FindByToken(ctx, token)
+		if isNotFound(err) {
+			return ErrInvalidRefreshToken
+		}
+		if err != nil {
+			return err
+		}
+		if current.IsExpired(now) {
+			return ErrInvalidRefreshToken
+		}
+
+		// A revogação precisa ser gravada, por isso a transação termina sem erro
+		if current.IsReused() {
+			rejected = true
+			return s.refreshTokens.RevokeFamily(ctx, current.FamilyID, now)
+		}
+
+		access, ok, err := s.currentAccess(ctx, current)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			rejected = true
+			return s.refreshTokens.RevokeFamily(ctx, current.FamilyID, now)
+		}
+
+		next, nextID, err := s.issue(ctx, current.UserID, access, current.FamilyID)
+		if err != nil {
+			return err
+		}
+		current.MarkUsed(nextID, now)
+		if err := s.refreshTokens.Save(ctx, current); err != nil {
+			return err
+		}
+
+		pair = next
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if rejected {
+		return nil, ErrInvalidRefreshToken
+	}
+	return pair, nil
+}
+
+// Revoke encerra a sessão inteira; tokens desconhecidos são ignorados
+func (s *TokenService) Revoke(ctx context.Context, token string) error {
+	systemCtx := tenancy.WithSystemScope(ctx)
+	return s.txManager.WithTx(systemCtx, func(ctx context.Context) error {
+		current, err := s.refreshTokens.FindByToken(ctx, token)
+		if isNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		return s.refreshTokens.RevokeFamily(ctx, current.FamilyID, time.Now().UTC())
+	})
+}
+
+// currentAccess confere se o usuário continua ativo e com acesso ao tenant do token
+func (s *TokenService) currentAccess(ctx context.Context, refresh *domain_user.RefreshToken) (TenantAccess, bool, error) {
+	user, err := s.users.FindByID(ctx, refresh.UserID)
+	if isNotFound(err) {
+		return TenantAccess{}, false, nil
+	}
+	if err != nil {
+		return TenantAccess{}, false, err
+	}
+	if !user.IsActive {
+		return TenantAccess{}, false, nil
+	}
+
+	accesses, err := accessibleTenants(ctx, s.txManager, s.memberships, s.tenants, refresh.UserID)
+	if err != nil {
+		return TenantAccess{}, false, err
+	}
+	for _, access := range accesses {
+		if access.TenantID == refresh.TenantID {
+			return access, true, nil
+		}
+	}
+	return TenantAccess{}, false, nil
+}
+
+// issue devolve também o ID do novo refresh token, usado para encadear a rotação
+func (s *TokenService) issue(ctx context.Context, userID string, access TenantAccess, familyID string) (*TokenPair, string, error) {
+	now := time.Now().UTC()
+	refresh, refreshToken, err := domain_user.NewRefreshToken(familyID, userID, access.TenantID, s.config.RefreshTTL, now)
+	if err != nil {
+		return nil, "", err
+	}
+
+	err = s.txManager.WithTx(tenancy.WithSystemScope(ctx), func(ctx context.Context) error {
+		return s.refreshTokens.Save(ctx, refresh)
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	accessToken, _, err := s.issuer.Issue(auth.Principal{
+		UserID:       userID,
+		TenantID:     access.TenantID,
+		MembershipID: access.MembershipID,
+		Roles:        []string{string(access.Role)},
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &TokenPair{
+		AccessToken:      accessToken,
+		ExpiresIn:        s.issuer.TTL(),
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: refresh.ExpiresAt,
+		Tenant:           access,
+	}, refresh.GetID(), nil
+}
+
+func selectTenant(accesses []TenantAccess, tenantID string) (TenantAccess, error) {
+	if len(accesses) == 0 {
+		return TenantAccess{}, errors.NewAppErrorWithDetails("FORBIDDEN", "Access forbidden", "user has no accessible tenants")
+	}
+	if tenantID == "" {
+		return accesses[0], nil
+	}
+	for _, access := range accesses {
+		if access.TenantID == tenantID || access.Slug == tenantID {
+			return access, nil
+		}
+	}
+	return TenantAccess{}, errors.NewAppErrorWithDetails("FORBIDDEN", "Access forbidden", "user has no access to this tenant")
+}
@@ -0,0 +1,229 @@
+package application_user
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/auth"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/errors"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/tenancy"
+	domain_user "github.com/williamkoller/multi-tenant-nexus-manager/internal/user/domain"
+)
+
+func TestTokenServiceRefreshRotates(t *testing.T) {
+	service, refreshTokens, login := newTokenServiceFixture(t)
+	ctx := context.Background()
+
+	first, err := service.IssueForLogin(ctx, login, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := service.Refresh(ctx, first.RefreshToken)
+	if err != nil {
+		t.Fatalf("Refresh returned %v", err)
+	}
+	if second.RefreshToken == first.RefreshToken || second.AccessToken == "" {
+		t.Fatalf("Refresh did not rotate the pair: %+v", second)
+	}
+
+	used := refreshTokens.find(first.RefreshToken)
+	next := refreshTokens.find(second.RefreshToken)
+	if used.UsedAt == nil || used.ReplacedBy != next.GetID() {
+		t.Fatalf("used token = %+v, want it replaced by %s", used, next.GetID())
+	}
+	if next.FamilyID != used.FamilyID {
+		t.Fatalf("family = %s, want %s", next.FamilyID, used.FamilyID)
+	}
+}
+
+func TestTokenServiceRefreshReuseRevokesFamily(t *testing.T) {
+	service, refreshTokens, login := newTokenServiceFixture(t)
+	ctx := context.Background()
+
+	first, err := service.IssueForLogin(ctx, login, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := service.Refresh(ctx, first.RefreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := service.IssueForLogin(ctx, login, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Reapresentar o token já trocado revoga a família inteira
+	if _, err := service.Refresh(ctx, first.RefreshToken); !hasCode(err, errors.ErrUnauthorized.Code) {
+		t.Fatalf("reused Refresh returned %v, want UNAUTHORIZED", err)
+	}
+	if refreshTokens.find(second.RefreshToken).RevokedAt == nil {
+		t.Fatal("the token issued by the rotation was not revoked")
+	}
+	if _, err := service.Refresh(ctx, second.RefreshToken); !hasCode(err, errors.ErrUnauthorized.Code) {
+		t.Fatalf("Refresh after revocation returned %v, want UNAUTHORIZED", err)
+	}
+
+	// Outras sessões do usuário pertencem a outra família e seguem válidas
+	if refreshTokens.find(other.RefreshToken).RevokedAt != nil {
+		t.Fatal("a token from another family was revoked")
+	}
+	if _, err := service.Refresh(ctx, other.RefreshToken); err != nil {
+		t.Fatalf("Refresh of another family returned %v", err)
+	}
+}
+
+func TestTokenServiceRefreshRejectsUnknownAndExpired(t *testing.T) {
+	service, refreshTokens, login := newTokenServiceFixture(t)
+	ctx := context.Background()
+
+	if _, err := service.Refresh(ctx, "unknown"); !hasCode(err, errors.ErrUnauthorized.Code) {
+		t.Fatalf("Refresh of an unknown token returned %v, want UNAUTHORIZED", err)
+	}
+
+	pair, err := service.IssueForLogin(ctx, login, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	refreshTokens.find(pair.RefreshToken).ExpiresAt = time.Now().UTC().Add(-time.Minute)
+	if _, err := service.Refresh(ctx, pair.RefreshToken); !hasCode(err, errors.ErrUnauthorized.Code) {
+		t.Fatalf("Refresh of an expired token returned %v, want UNAUTHORIZED", err)
+	}
+}
+
+func TestTokenServiceRefreshRevokesLostAccess(t *testing.T) {
+	service, refreshTokens, login := newTokenServiceFixture(t)
+	ctx := context.Background()
+
+	pair, err := service.IssueForLogin(ctx, login, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	login.User.IsActive = false
+
+	if _, err := service.Refresh(ctx, pair.RefreshToken); !hasCode(err, errors.ErrUnauthorized.Code) {
+		t.Fatalf("Refresh of a deactivated user returned %v, want UNAUTHORIZED", err)
+	}
+	if refreshTokens.find(pair.RefreshToken).RevokedAt == nil {
+		t.Fatal("the family of a deactivated user was not revoked")
+	}
+}
+
+func newTokenServiceFixture(t *testing.T) (*TokenService, *fakeRefreshTokens, *LoginResult) {
+	t.Helper()
+
+	keys, err := auth.GenerateKeySet("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user := &domain_user.User{IsActive: true}
+	user.Initialize()
+	membership := &domain_user.Membership{TenantID: "tenant-1", UserID: user.GetID(), Role: domain_user.RoleAdmin, Status: domain_user.MembershipActive}
+	membership.Initialize()
+
+	refreshTokens := &fakeRefreshTokens{tokens: make(map[string]*domain_user.RefreshToken)}
+	service := NewTokenService(
+		fakeUsers{user: user},
+		fakeMemberships{active: []*domain_user.Membership{membership}},
+		fakeTenants{},
+		refreshTokens,
+		fakeTxManager{},
+		auth.NewTokenIssuer(keys, auth.DefaultTokenConfig()),
+		TokenConfig{},
+	)
+
+	login := &LoginResult{
+		User: user,
+		Tenants: []TenantAccess{{
+			TenantID:     membership.TenantID,
+			Slug:         membership.TenantID,
+			MembershipID: membership.GetID(),
+			Role:         membership.Role,
+		}},
+	}
+	return service, refreshTokens, login
+}
+
+type fakeTxManager struct{}
+
+func (fakeTxManager) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type fakeRefreshTokens struct {
+	tokens map[string]*domain_user.RefreshToken
+}
+
+func (r *fakeRefreshTokens) find(token string) *domain_user.RefreshToken {
+	return r.tokens[domain_user.HashRefreshToken(token)]
+}
+
+func (r *fakeRefreshTokens) Save(_ context.Context, token *domain_user.RefreshToken) error {
+	r.tokens[token.TokenHash] = token
+	return nil
+}
+
+func (r *fakeRefreshTokens) FindByToken(_ context.Context, token string) (*domain_user.RefreshToken, error) {
+	if found := r.find(token); found != nil {
+		return found, nil
+	}
+	return nil, errors.ErrNotFound
+}
+
+func (r *fakeRefreshTokens) RevokeFamily(_ context.Context, familyID string, now time.Time) error {
+	for _, token := range r.tokens {
+		if token.FamilyID == familyID && token.RevokedAt == nil {
+			token.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+type fakeUsers struct {
+	domain_user.Repository
+	user *domain_user.User
+}
+
+func (r fakeUsers) FindByID(_ context.Context, id string) (*domain_user.User, error) {
+	if id != r.user.GetID() {
+		return nil, errors.ErrNotFound
+	}
+	return r.user, nil
+}
+
+type fakeMemberships struct {
+	domain_user.MembershipRepository
+	active []*domain_user.Membership
+}
+
+func (r fakeMemberships) FindActiveByUser(_ context.Context, userID string) ([]*domain_user.Membership, error) {
+	var found []*domain_user.Membership
+	for _, membership := range r.active {
+		if membership.UserID == userID {
+			found = append(found, membership)
+		}
+	}
+	return found, nil
+}
+
+type fakeTenants struct{}
+
+func (fakeTenants) FindByID(_ context.Context, id string) (tenancy.Tenant, error) {
+	return fakeTenant{id: id}, nil
+}
+
+func (fakeTenants) FindBySlug(_ context.Context, slug string) (tenancy.Tenant, error) {
+	return fakeTenant{id: slug}, nil
+}
+
+type fakeTenant struct {
+	id string
+}
+
+func (t fakeTenant) GetID() string                   { return t.id }
+func (t fakeTenant) GetSlug() string                 { return t.id }
+func (t fakeTenant) IsActive() bool                  { return true }
+func (t fakeTenant) GetIsolation() tenancy.Isolation { return "" }
+func (t fakeTenant) GetSchemaName() string           { return "" }
@@ -0,0 +1,126 @@
+package domain_user
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/domain"
+)
+
+// PasswordHistory guarda os hashes anteriores, do mais recente para o mais antigo
+type PasswordHistory []string
+
+func (h PasswordHistory) Value() (driver.Value, error) {
+	if h == nil {
+		h = PasswordHistory{}
+	}
+	data, err := json.Marshal([]string(h))
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+func (h *PasswordHistory) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*h = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, (*[]string)(h))
+	case string:
+		return json.Unmarshal([]byte(v), (*[]string)(h))
+	default:
+		return fmt.Errorf("cannot scan %T into PasswordHistory", value)
+	}
+}
+
+var _ domain.AggregateRoot = (*Credential)(nil)
+
+// Credential fica separada do User para que hashes nunca sejam serializados
+// junto com o perfil. O bloqueio vale para a identidade em todos os tenants.
+type Credential struct {
+	domain.BaseAggregateRoot
+	UserID            string          `json:"user_id" gorm:"not null;uniqueIndex"`
+	PasswordHash      string          `json:"-" gorm:"not null"`
+	PasswordHistory   PasswordHistory `json:"-" gorm:"type:jsonb;not null"`
+	PasswordChangedAt time.Time       `json:"password_changed_at" gorm:"not null"`
+	FailedAttempts    int             `json:"failed_attempts" gorm:"not null"`
+	LockedUntil       *time.Time      `json:"locked_until,omitempty"`
+	LastLoginAt       *time.Time      `json:"last_login_at,omitempty"`
+}
+
+func (Credential) TableName() string {
+	return "credentials"
+}
+
+func NewCredential(userID, passwordHash string, now time.Time) *Credential {
+	credential := &Credential{
+		UserID:            userID,
+		PasswordHash:      passwordHash,
+		PasswordChangedAt: now,
+	}
+	credential.Initialize()
+	return credential
+}
+
+// ChangePassword arquiva o hash atual mantendo no máximo historySize entradas
+func (c *Credential) ChangePassword(passwordHash string, historySize int, now time.Time) {
+	history := append(PasswordHistory{c.PasswordHash}, c.PasswordHistory...)
+	if len(history) > historySize {
+		history = history[:historySize]
+	}
+
+	c.PasswordHistory = history
+	c.PasswordHash = passwordHash
+	c.PasswordChangedAt = now
+	c.FailedAttempts = 0
+	c.LockedUntil = nil
+	c.UpdatedAt = now
+}
+
+// RecentHashes devolve o hash atual e os últimos historySize anteriores
+func (c *Credential) RecentHashes(historySize int) []string {
+	hashes := []string{c.PasswordHash}
+	for i, hash := range c.PasswordHistory {
+		if i >= historySize {
+			break
+		}
+		hashes = append(hashes, hash)
+	}
+	return hashes
+}
+
+// RecordFailure bloqueia a credencial ao atingir maxAttempts falhas seguidas
+func (c *Credential) RecordFailure(maxAttempts int, lockout time.Duration, now time.Time) {
+	c.FailedAttempts++
+	if c.FailedAttempts >= maxAttempts {
+		lockedUntil := now.Add(lockout)
+		c.LockedUntil = &lockedUntil
+		c.FailedAttempts = 0
+	}
+	c.UpdatedAt = now
+}
+
+func (c *Credential) RecordSuccess(now time.Time) {
+	c.FailedAttempts = 0
+	c.LockedUntil = nil
+	c.LastLoginAt = &now
+	c.UpdatedAt = now
+}
+
+// Rehash troca o hash por um com parâmetros atuais sem afetar histórico e expiração
+func (c *Credential) Rehash(passwordHash string, now time.Time) {
+	c.PasswordHash = passwordHash
+	c.UpdatedAt = now
+}
+
+func (c *Credential) IsLocked(now time.Time) bool {
+	return c.LockedUntil != nil && now.Before(*c.LockedUntil)
+}
+
+func (c *Credential) IsExpired(maxAge time.Duration, now time.Time) bool {
+	return maxAge > 0 && !now.Before(c.PasswordChangedAt.Add(maxAge))
+}
@@ -0,0 +1,145 @@
+package domain_user
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/events"
+)
+
+const (
+	EventUserCreated         = "user.created"
+	EventUserActivated       = "user.activated"
+	EventUserDeactivated     = "user.deactivated"
+	EventUserEmailChanged    = "user.email_changed"
+	EventUserPhoneChanged    = "user.phone_changed"
+	EventUserFullNameChanged = "user.full_name_changed"
+	EventUserEmailVerified   = "user.email_verified"
+
+	EventMembershipCreated     = "membership.created"
+	EventMembershipInvited     = "membership.invited"
+	EventMembershipAccepted    = "membership.accepted"
+	EventMembershipRoleChanged = "membership.role_changed"
+	EventMembershipSuspended   = "membership.suspended"
+	EventMembershipReactivated = "membership.reactivated"
+	EventMembershipRevoked     = "membership.revoked"
+
+	EventInvitationSent     = "invitation.sent"
+	EventInvitationAccepted = "invitation.accepted"
+	EventInvitationRevoked  = "invitation.revoked"
+	EventInvitationExpired  = "invitation.expired"
+)
+
+type UserCreated struct {
+	HomeTenantID string    `json:"home_tenant_id"`
+	Email        string    `json:"email"`
+	FullName     string    `json:"full_name"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type UserActivated struct {
+	Email       string    `json:"email"`
+	ActivatedAt time.Time `json:"activated_at"`
+}
+
+type UserDeactivated struct {
+	DeactivatedAt time.Time `json:"deactivated_at"`
+}
+
+type UserEmailChanged struct {
+	PreviousEmail string    `json:"previous_email"`
+	Email         string    `json:"email"`
+	ChangedAt     time.Time `json:"changed_at"`
+}
+
+type UserPhoneChanged struct {
+	Phone     string    `json:"phone"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+type UserFullNameChanged struct {
+	FullName  string    `json:"full_name"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+type UserEmailVerified struct {
+	UserID     string    `json:"user_id"`
+	Email      string    `json:"email"`
+	VerifiedAt time.Time `json:"verified_at"`
+}
+
+// MembershipChanged é o payload comum dos eventos de membership; o tipo indica a mudança
+type MembershipChanged struct {
+	TenantID   string           `json:"tenant_id"`
+	UserID     string           `json:"user_id"`
+	Role       Role             `json:"role"`
+	Status     MembershipStatus `json:"status"`
+	OccurredAt time.Time        `json:"occurred_at"`
+}
+
+// InvitationSent nunca carrega o token, só o nonce de que ele é derivado
+type InvitationSent struct {
+	TenantID  string    `json:"tenant_id"`
+	Email     string    `json:"email"`
+	Role      Role      `json:"role"`
+	InvitedBy string    `json:"invited_by,omitempty"`
+	SentCount int       `json:"sent_count"`
+	ExpiresAt time.Time `json:"expires_at"`
+	SentAt    time.Time `json:"sent_at"`
+	Nonce     string    `json:"nonce"`
+}
+
+type InvitationAccepted struct {
+	TenantID   string    `json:"tenant_id"`
+	Email      string    `json:"email"`
+	Role       Role      `json:"role"`
+	UserID     string    `json:"user_id"`
+	AcceptedAt time.Time `json:"accepted_at"`
+}
+
+// InvitationClosed é o payload de invitation.revoked e invitation.expired
+type InvitationClosed struct {
+	TenantID   string           `json:"tenant_id"`
+	Email      string           `json:"email"`
+	Status     InvitationStatus `json:"status"`
+	OccurredAt time.Time        `json:"occurred_at"`
+}
+
+// RegisterEvents registra os payloads do contexto de usuário no registry
+func RegisterEvents(registry *events.Registry) {
+	// v2 renomeou tenant_id para home_tenant_id quando o usuário virou identidade global
+	events.Register[UserCreated](registry, EventUserCreated, 2)
+	registry.RegisterUpcaster(EventUserCreated, 1, renameField("tenant_id", "home_tenant_id"))
+	events.Register[UserActivated](registry, EventUserActivated, 1)
+	events.Register[UserDeactivated](registry, EventUserDeactivated, 1)
+	events.Register[UserEmailChanged](registry, EventUserEmailChanged, 1)
+	events.Register[UserPhoneChanged](registry, EventUserPhoneChanged, 1)
+	events.Register[UserFullNameChanged](registry, EventUserFullNameChanged, 1)
+	events.Register[UserEmailVerified](registry, EventUserEmailVerified, 1)
+
+	for _, eventType := range []string{
+		EventMembershipCreated, EventMembershipInvited, EventMembershipAccepted, EventMembershipRoleChanged,
+		EventMembershipSuspended, EventMembershipReactivated, EventMembershipRevoked,
+	} {
+		events.Register[MembershipChanged](registry, eventType, 1)
+	}
+
+	events.Register[InvitationSent](registry, EventInvitationSent, 1)
+	events.Register[InvitationAccepted](registry, EventInvitationAccepted, 1)
+	events.Register[InvitationClosed](registry, EventInvitationRevoked, 1)
+	events.Register[InvitationClosed](registry, EventInvitationExpired, 1)
+}
+
+func renameField(from, to string) events.Upcaster {
+	return func(payload json.RawMessage) (json.RawMessage, error) {
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(payload, &fields); err != nil {
+			return nil, err
+		}
+		if value, ok := fields[from]; ok {
+			fields[to] = value
+			delete(fields, from)
+		}
+		return json.Marshal(fields)
+	}
+}
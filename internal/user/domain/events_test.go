@@ -0,0 +1,60 @@
+package domain_user
+
+import (
+	"testing"
+	"time"
+
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/domain"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/events"
+)
+
+func TestUserCreatedUpcastsFromVersion1(t *testing.T) {
+	registry := events.NewRegistry()
+	RegisterEvents(registry)
+
+	// Payload gravado antes de o usuário virar identidade global
+	v1 := `{"tenant_id":"tenant-1","email":"ana@example.com","full_name":"Ana","created_at":"2024-03-01T12:00:00Z"}`
+	decoded, err := registry.Decode(EventUserCreated, 1, []byte(v1))
+	if err != nil {
+		t.Fatalf("Decode v1 returned %v", err)
+	}
+
+	want := UserCreated{
+		HomeTenantID: "tenant-1",
+		Email:        "ana@example.com",
+		FullName:     "Ana",
+		CreatedAt:    time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+	}
+	created, ok := decoded.(UserCreated)
+	if !ok || created.HomeTenantID != want.HomeTenantID || created.Email != want.Email ||
+		created.FullName != want.FullName || !created.CreatedAt.Equal(want.CreatedAt) {
+		t.Fatalf("Decode v1 = %#v, want %#v", decoded, want)
+	}
+}
+
+func TestUserEventsEncodeCurrentVersion(t *testing.T) {
+	registry := events.NewRegistry()
+	RegisterEvents(registry)
+
+	event := domain.NewBaseDomainEvent(EventUserCreated, "u1", UserCreated{HomeTenantID: "tenant-1", Email: "ana@example.com"})
+	payload, version, err := registry.Encode(event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != 2 {
+		t.Fatalf("user.created encoded as version %d, want 2", version)
+	}
+
+	// A versão atual é lida sem passar pelos upcasters
+	decoded, err := registry.Decode(EventUserCreated, version, payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created := decoded.(UserCreated); created.HomeTenantID != "tenant-1" {
+		t.Fatalf("Decode v2 = %#v", decoded)
+	}
+
+	if _, version, _ := registry.Encode(domain.NewBaseDomainEvent(EventUserActivated, "u1", UserActivated{})); version != 1 {
+		t.Fatalf("user.activated encoded as version %d, want 1", version)
+	}
+}
@@ -0,0 +1,248 @@
+package domain_user
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/domain"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/domain/value_objects"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/errors"
+)
+
+type InvitationStatus string
+
+const (
+	InvitationStatusPending  InvitationStatus = "pending"
+	InvitationStatusAccepted InvitationStatus = "accepted"
+	InvitationStatusRevoked  InvitationStatus = "revoked"
+	InvitationStatusExpired  InvitationStatus = "expired"
+)
+
+var _ domain.AggregateRoot = (*Invitation)(nil)
+
+var (
+	ErrInvitationNotFound = errors.NewAppErrorWithDetails("NOT_FOUND", "Resource not found", "invitation not found or token is invalid")
+	ErrInvitationExpired  = errors.NewAppErrorWithDetails("CONFLICT", "Resource conflict", "invitation has expired")
+)
+
+// Invitation convida um e-mail para o tenant. Só o hash do token é persistido;
+// o token em claro existe apenas no e-mail, montado depois do commit a partir
+// do nonce do evento invitation.sent (veja InvitationTokenKey).
+type Invitation struct {
+	domain.BaseAggregateRoot
+	TenantID       string              `json:"tenant_id" gorm:"not null;index"`
+	Email          value_objects.Email `json:"email" gorm:"not null;index"`
+	Role           Role                `json:"role" gorm:"not null"`
+	Status         InvitationStatus    `json:"status" gorm:"index;not null"`
+	TokenHash      string              `json:"-" gorm:"not null;uniqueIndex"`
+	InvitedBy      string              `json:"invited_by,omitempty"`
+	ExpiresAt      time.Time           `json:"expires_at" gorm:"index;not null"`
+	SentCount      int                 `json:"sent_count" gorm:"not null"`
+	LastSentAt     time.Time           `json:"last_sent_at" gorm:"not null"`
+	AcceptedAt     *time.Time          `json:"accepted_at,omitempty"`
+	AcceptedUserID string              `json:"accepted_user_id,omitempty"`
+}
+
+func (Invitation) TableName() string {
+	return "invitations"
+}
+
+// Invitations ficam no schema public porque o aceite localiza o convite apenas pelo token
+func (Invitation) SharedTable() bool {
+	return true
+}
+
+// InvitationTokenKey é o segredo do servidor que deriva o token de cada envio.
+// O evento invitation.sent carrega só o nonce, então quem lê o outbox, o stream
+// ou os webhooks não consegue montar o link do convite.
+type InvitationTokenKey []byte
+
+func (k InvitationTokenKey) Token(invitationID, nonce string) string {
+	mac := hmac.New(sha256.New, k)
+	mac.Write([]byte(invitationID + "." + nonce))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func NewInvitation(tenantID, email string, role Role, invitedBy string, ttl time.Duration, key InvitationTokenKey) (*Invitation, error) {
+	emailVO, err := value_objects.NewEmail(email)
+	if err != nil {
+		return nil, invalidInput(err.Error())
+	}
+	if _, err := ParseRole(string(role)); err != nil {
+		return nil, err
+	}
+	if ttl <= 0 {
+		return nil, invalidInput("invitation ttl must be positive")
+	}
+
+	invitation := &Invitation{
+		TenantID:  tenantID,
+		Email:     emailVO,
+		Role:      role,
+		Status:    InvitationStatusPending,
+		InvitedBy: invitedBy,
+	}
+	if err := invitation.issue(key, ttl, time.Now().UTC()); err != nil {
+		return nil, err
+	}
+	return invitation, nil
+}
+
+// Resend gera um novo token, invalidando o link anterior, e renova a validade
+func (i *Invitation) Resend(ttl time.Duration, now time.Time, key InvitationTokenKey) error {
+	if i.Status == InvitationStatusExpired {
+		// Convites expirados podem ser reenviados; o prazo recomeça a contar
+		i.Status = InvitationStatusPending
+	}
+	if i.Status != InvitationStatusPending {
+		return i.invalidTransition(InvitationStatusPending)
+	}
+	if ttl <= 0 {
+		return invalidInput("invitation ttl must be positive")
+	}
+	return i.issue(key, ttl, now)
+}
+
+// Accept consome o token; o convite não pode ser usado novamente
+func (i *Invitation) Accept(token, userID string, now time.Time) error {
+	if !i.MatchesToken(token) {
+		return ErrInvitationNotFound
+	}
+	if err := i.CanAccept(now); err != nil {
+		return err
+	}
+
+	i.Status = InvitationStatusAccepted
+	i.AcceptedAt = &now
+	i.AcceptedUserID = userID
+	i.UpdatedAt = now
+
+	event := domain.NewBaseDomainEvent(
+		EventInvitationAccepted,
+		i.GetID(),
+		InvitationAccepted{
+			TenantID:   i.TenantID,
+			Email:      i.Email.String(),
+			Role:       i.Role,
+			UserID:     userID,
+			AcceptedAt: now,
+		},
+	)
+	i.RaiseDomainEvent(event)
+	return nil
+}
+
+// CanAccept permite validar o convite antes de criar o usuário que vai aceitá-lo
+func (i *Invitation) CanAccept(now time.Time) error {
+	if i.Status == InvitationStatusPending && i.IsExpired(now) {
+		return ErrInvitationExpired
+	}
+	if i.Status != InvitationStatusPending {
+		return i.invalidTransition(InvitationStatusAccepted)
+	}
+	return nil
+}
+
+func (i *Invitation) Revoke(now time.Time) error {
+	if i.Status == InvitationStatusRevoked {
+		return nil
+	}
+	if i.Status == InvitationStatusAccepted {
+		return i.invalidTransition(InvitationStatusRevoked)
+	}
+
+	i.Status = InvitationStatusRevoked
+	i.UpdatedAt = now
+	i.raise(EventInvitationRevoked, now)
+	return nil
+}
+
+// Expire marca convites pendentes vencidos; devolve false quando não havia o que expirar
+func (i *Invitation) Expire(now time.Time) bool {
+	if i.Status != InvitationStatusPending || !i.IsExpired(now) {
+		return false
+	}
+
+	i.Status = InvitationStatusExpired
+	i.UpdatedAt = now
+	i.raise(EventInvitationExpired, now)
+	return true
+}
+
+func (i *Invitation) IsExpired(now time.Time) bool {
+	return !now.Before(i.ExpiresAt)
+}
+
+// MatchesToken compara os hashes em tempo constante
+func (i *Invitation) MatchesToken(token string) bool {
+	return subtle.ConstantTimeCompare([]byte(i.TokenHash), []byte(HashInvitationToken(token))) == 1
+}
+
+// HashInvitationToken é usado para gravar e localizar convites sem guardar o token
+func HashInvitationToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func (i *Invitation) issue(key InvitationTokenKey, ttl time.Duration, now time.Time) error {
+	if len(key) == 0 {
+		return fmt.Errorf("invitation token key is not configured")
+	}
+
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return err
+	}
+	nonce := base64.RawURLEncoding.EncodeToString(buf)
+
+	i.TokenHash = HashInvitationToken(key.Token(i.GetID(), nonce))
+	i.ExpiresAt = now.Add(ttl)
+	i.SentCount++
+	i.LastSentAt = now
+	i.UpdatedAt = now
+
+	event := domain.NewBaseDomainEvent(
+		EventInvitationSent,
+		i.GetID(),
+		InvitationSent{
+			TenantID:  i.TenantID,
+			Email:     i.Email.String(),
+			Role:      i.Role,
+			InvitedBy: i.InvitedBy,
+			SentCount: i.SentCount,
+			ExpiresAt: i.ExpiresAt,
+			SentAt:    now,
+			Nonce:     nonce,
+		},
+	)
+	i.RaiseDomainEvent(event)
+	return nil
+}
+
+func (i *Invitation) raise(eventType string, at time.Time) {
+	event := domain.NewBaseDomainEvent(
+		eventType,
+		i.GetID(),
+		InvitationClosed{
+			TenantID:   i.TenantID,
+			Email:      i.Email.String(),
+			Status:     i.Status,
+			OccurredAt: at,
+		},
+	)
+	i.RaiseDomainEvent(event)
+}
+
+func (i *Invitation) invalidTransition(next InvitationStatus) error {
+	return errors.NewAppErrorWithDetails(
+		"CONFLICT",
+		"Invalid invitation status transition",
+		fmt.Sprintf("cannot transition invitation from %s to %s", i.Status, next),
+	)
+}
@@ -0,0 +1,213 @@
+package domain_user
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/domain"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/errors"
+)
+
+type Role string
+
+const (
+	RoleOwner  Role = "owner"
+	RoleAdmin  Role = "admin"
+	RoleMember Role = "member"
+)
+
+func ParseRole(value string) (Role, error) {
+	switch role := Role(strings.ToLower(strings.TrimSpace(value))); role {
+	case RoleOwner, RoleAdmin, RoleMember:
+		return role, nil
+	default:
+		return "", invalidInput(fmt.Sprintf("invalid role %q", value))
+	}
+}
+
+type MembershipStatus string
+
+const (
+	MembershipInvited   MembershipStatus = "invited"
+	MembershipActive    MembershipStatus = "active"
+	MembershipSuspended MembershipStatus = "suspended"
+	MembershipRevoked   MembershipStatus = "revoked"
+)
+
+var _ domain.AggregateRoot = (*Membership)(nil)
+
+// Membership liga uma identidade global (User) a um tenant com papel e status próprios
+type Membership struct {
+	domain.BaseAggregateRoot
+	TenantID  string           `json:"tenant_id" gorm:"not null;uniqueIndex:idx_memberships_tenant_user"`
+	UserID    string           `json:"user_id" gorm:"not null;index;uniqueIndex:idx_memberships_tenant_user"`
+	Role      Role             `json:"role" gorm:"not null"`
+	Status    MembershipStatus `json:"status" gorm:"index;not null"`
+	InvitedBy string           `json:"invited_by,omitempty"`
+	InvitedAt *time.Time       `json:"invited_at,omitempty"`
+	JoinedAt  *time.Time       `json:"joined_at,omitempty"`
+}
+
+func (Membership) TableName() string {
+	return "memberships"
+}
+
+// Memberships ficam no schema public para listar todos os tenants de um usuário em uma consulta
+func (Membership) SharedTable() bool {
+	return true
+}
+
+// NewMembership adiciona o usuário diretamente como membro ativo
+func NewMembership(tenantID, userID string, role Role) (*Membership, error) {
+	if _, err := ParseRole(string(role)); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	membership := &Membership{
+		TenantID: tenantID,
+		UserID:   userID,
+		Role:     role,
+		Status:   MembershipActive,
+		JoinedAt: &now,
+	}
+	membership.raise(EventMembershipCreated, now)
+	return membership, nil
+}
+
+// NewInvitedMembership aguarda o aceite do convite para ficar ativa
+func NewInvitedMembership(tenantID, userID string, role Role, invitedBy string) (*Membership, error) {
+	if _, err := ParseRole(string(role)); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	membership := &Membership{
+		TenantID:  tenantID,
+		UserID:    userID,
+		Role:      role,
+		Status:    MembershipInvited,
+		InvitedBy: invitedBy,
+		InvitedAt: &now,
+	}
+	membership.raise(EventMembershipInvited, now)
+	return membership, nil
+}
+
+func (m *Membership) Accept() error {
+	if m.Status == MembershipActive {
+		return nil
+	}
+	if m.Status != MembershipInvited {
+		return m.invalidTransition(MembershipActive)
+	}
+
+	now := time.Now().UTC()
+	m.Status = MembershipActive
+	m.JoinedAt = &now
+	m.raise(EventMembershipAccepted, now)
+	return nil
+}
+
+func (m *Membership) ChangeRole(role Role) error {
+	role, err := ParseRole(string(role))
+	if err != nil {
+		return err
+	}
+	if m.Status == MembershipRevoked {
+		return m.invalidTransition(m.Status)
+	}
+	if role == m.Role {
+		return nil
+	}
+
+	m.Role = role
+	m.raise(EventMembershipRoleChanged, time.Now().UTC())
+	return nil
+}
+
+func (m *Membership) Suspend() error {
+	if m.Status == MembershipSuspended {
+		return nil
+	}
+	if m.Status != MembershipActive {
+		return m.invalidTransition(MembershipSuspended)
+	}
+
+	m.Status = MembershipSuspended
+	m.raise(EventMembershipSuspended, time.Now().UTC())
+	return nil
+}
+
+func (m *Membership) Reactivate() error {
+	if m.Status == MembershipActive {
+		return nil
+	}
+	if m.Status != MembershipSuspended {
+		return m.invalidTransition(MembershipActive)
+	}
+
+	m.Status = MembershipActive
+	m.raise(EventMembershipReactivated, time.Now().UTC())
+	return nil
+}
+
+// Revoke é definitivo; um novo acesso exige outro convite
+func (m *Membership) Revoke() {
+	if m.Status == MembershipRevoked {
+		return
+	}
+
+	m.Status = MembershipRevoked
+	m.raise(EventMembershipRevoked, time.Now().UTC())
+}
+
+// Reinvite reaproveita uma membership revogada para um novo convite
+func (m *Membership) Reinvite(role Role, invitedBy string) error {
+	role, err := ParseRole(string(role))
+	if err != nil {
+		return err
+	}
+	if m.Status != MembershipRevoked {
+		return errors.NewAppErrorWithDetails("CONFLICT", "Resource conflict", "user is already a member of this tenant")
+	}
+
+	now := time.Now().UTC()
+	m.Role = role
+	m.Status = MembershipInvited
+	m.InvitedBy = invitedBy
+	m.InvitedAt = &now
+	m.JoinedAt = nil
+	m.raise(EventMembershipInvited, now)
+	return nil
+}
+
+func (m *Membership) CanEnter() bool {
+	return m.Status == MembershipActive
+}
+
+func (m *Membership) raise(eventType string, at time.Time) {
+	m.UpdatedAt = at
+
+	event := domain.NewBaseDomainEvent(
+		eventType,
+		m.GetID(),
+		MembershipChanged{
+			TenantID:   m.TenantID,
+			UserID:     m.UserID,
+			Role:       m.Role,
+			Status:     m.Status,
+			OccurredAt: at,
+		},
+	)
+	m.RaiseDomainEvent(event)
+}
+
+func (m *Membership) invalidTransition(next MembershipStatus) error {
+	return errors.NewAppErrorWithDetails(
+		"CONFLICT",
+		"Invalid membership status transition",
+		fmt.Sprintf("cannot transition membership from %s to %s", m.Status, next),
+	)
+}
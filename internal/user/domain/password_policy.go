@@ -0,0 +1,150 @@
+package domain_user
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/domain"
+)
+
+const (
+	minPasswordLength = 8
+	maxPasswordLength = 128
+	maxHistorySize    = 24
+)
+
+var _ domain.AggregateRoot = (*PasswordPolicy)(nil)
+
+// PasswordPolicy é definida por tenant. Como a identidade é global, a senha de
+// um usuário precisa atender à política mais restritiva entre os seus tenants.
+type PasswordPolicy struct {
+	domain.BaseAggregateRoot
+	TenantID         string `json:"tenant_id" gorm:"not null;uniqueIndex"`
+	MinLength        int    `json:"min_length" gorm:"not null"`
+	RequireUppercase bool   `json:"require_uppercase" gorm:"not null"`
+	RequireLowercase bool   `json:"require_lowercase" gorm:"not null"`
+	RequireDigit     bool   `json:"require_digit" gorm:"not null"`
+	RequireSymbol    bool   `json:"require_symbol" gorm:"not null"`
+	// HistorySize impede reutilizar as últimas N senhas
+	HistorySize int `json:"history_size" gorm:"not null"`
+	// MaxAgeDays igual a zero desativa a expiração
+	MaxAgeDays int `json:"max_age_days" gorm:"not null"`
+}
+
+func (PasswordPolicy) TableName() string {
+	return "password_policies"
+}
+
+func DefaultPasswordPolicy(tenantID string) *PasswordPolicy {
+	policy := &PasswordPolicy{
+		TenantID:         tenantID,
+		MinLength:        12,
+		RequireUppercase: true,
+		RequireLowercase: true,
+		RequireDigit:     true,
+		HistorySize:      5,
+	}
+	policy.Initialize()
+	return policy
+}
+
+type PasswordPolicyRules struct {
+	MinLength        int
+	RequireUppercase bool
+	RequireLowercase bool
+	RequireDigit     bool
+	RequireSymbol    bool
+	HistorySize      int
+	MaxAgeDays       int
+}
+
+func (p *PasswordPolicy) Update(rules PasswordPolicyRules) error {
+	if rules.MinLength < minPasswordLength || rules.MinLength > maxPasswordLength {
+		return invalidInput(fmt.Sprintf("min_length must be between %d and %d", minPasswordLength, maxPasswordLength))
+	}
+	if rules.HistorySize < 0 || rules.HistorySize > maxHistorySize {
+		return invalidInput(fmt.Sprintf("history_size must be between 0 and %d", maxHistorySize))
+	}
+	if rules.MaxAgeDays < 0 {
+		return invalidInput("max_age_days must not be negative")
+	}
+
+	p.MinLength = rules.MinLength
+	p.RequireUppercase = rules.RequireUppercase
+	p.RequireLowercase = rules.RequireLowercase
+	p.RequireDigit = rules.RequireDigit
+	p.RequireSymbol = rules.RequireSymbol
+	p.HistorySize = rules.HistorySize
+	p.MaxAgeDays = rules.MaxAgeDays
+	p.UpdatedAt = time.Now()
+	return nil
+}
+
+// Validate lista todas as regras violadas de uma vez
+func (p *PasswordPolicy) Validate(password string) error {
+	var problems []string
+	if length := utf8.RuneCountInString(password); length < p.MinLength {
+		problems = append(problems, fmt.Sprintf("must have at least %d characters", p.MinLength))
+	} else if length > maxPasswordLength {
+		problems = append(problems, fmt.Sprintf("must have at most %d characters", maxPasswordLength))
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			symbol = true
+		}
+	}
+	if p.RequireUppercase && !upper {
+		problems = append(problems, "must contain an uppercase letter")
+	}
+	if p.RequireLowercase && !lower {
+		problems = append(problems, "must contain a lowercase letter")
+	}
+	if p.RequireDigit && !digit {
+		problems = append(problems, "must contain a digit")
+	}
+	if p.RequireSymbol && !symbol {
+		problems = append(problems, "must contain a symbol")
+	}
+
+	if len(problems) > 0 {
+		return invalidInput("password " + strings.Join(problems, ", "))
+	}
+	return nil
+}
+
+func (p *PasswordPolicy) MaxAge() time.Duration {
+	return time.Duration(p.MaxAgeDays) * 24 * time.Hour
+}
+
+// StrictestPolicy combina políticas escolhendo sempre a regra mais exigente
+func StrictestPolicy(policies ...*PasswordPolicy) *PasswordPolicy {
+	if len(policies) == 0 {
+		return DefaultPasswordPolicy("")
+	}
+
+	strictest := &PasswordPolicy{}
+	for _, policy := range policies {
+		strictest.MinLength = max(strictest.MinLength, policy.MinLength)
+		strictest.RequireUppercase = strictest.RequireUppercase || policy.RequireUppercase
+		strictest.RequireLowercase = strictest.RequireLowercase || policy.RequireLowercase
+		strictest.RequireDigit = strictest.RequireDigit || policy.RequireDigit
+		strictest.RequireSymbol = strictest.RequireSymbol || policy.RequireSymbol
+		strictest.HistorySize = max(strictest.HistorySize, policy.HistorySize)
+		if policy.MaxAgeDays > 0 && (strictest.MaxAgeDays == 0 || policy.MaxAgeDays < strictest.MaxAgeDays) {
+			strictest.MaxAgeDays = policy.MaxAgeDays
+		}
+	}
+	return strictest
+}
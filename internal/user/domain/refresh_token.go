@@ -0,0 +1,78 @@
+package domain_user
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/domain"
+)
+
+var _ domain.AggregateRoot = (*RefreshToken)(nil)
+
+// RefreshToken é opaco e rotativo: cada uso gera o próximo da mesma família.
+// Reapresentar um token já usado indica vazamento e revoga a família inteira.
+type RefreshToken struct {
+	domain.BaseAggregateRoot
+	FamilyID   string     `json:"family_id" gorm:"not null;index"`
+	UserID     string     `json:"user_id" gorm:"not null;index"`
+	TenantID   string     `json:"tenant_id" gorm:"not null;index"`
+	TokenHash  string     `json:"-" gorm:"not null;uniqueIndex"`
+	ExpiresAt  time.Time  `json:"expires_at" gorm:"not null"`
+	UsedAt     *time.Time `json:"used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	ReplacedBy string     `json:"replaced_by,omitempty"`
+}
+
+func (RefreshToken) TableName() string {
+	return "refresh_tokens"
+}
+
+// Refresh tokens ficam no schema public: a renovação chega sem tenant resolvido
+func (RefreshToken) SharedTable() bool {
+	return true
+}
+
+// NewRefreshToken inicia uma família quando familyID é vazio e devolve o token em claro
+func NewRefreshToken(familyID, userID, tenantID string, ttl time.Duration, now time.Time) (*RefreshToken, string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(buf)
+
+	refresh := &RefreshToken{
+		FamilyID:  familyID,
+		UserID:    userID,
+		TenantID:  tenantID,
+		TokenHash: HashRefreshToken(token),
+		ExpiresAt: now.Add(ttl),
+	}
+	refresh.Initialize()
+	if refresh.FamilyID == "" {
+		refresh.FamilyID = refresh.GetID()
+	}
+	return refresh, token, nil
+}
+
+func HashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsReused indica um token que já foi trocado ou revogado
+func (t *RefreshToken) IsReused() bool {
+	return t.UsedAt != nil || t.RevokedAt != nil
+}
+
+func (t *RefreshToken) IsExpired(now time.Time) bool {
+	return !now.Before(t.ExpiresAt)
+}
+
+func (t *RefreshToken) MarkUsed(replacedBy string, now time.Time) {
+	t.UsedAt = &now
+	t.ReplacedBy = replacedBy
+	t.UpdatedAt = now
+}
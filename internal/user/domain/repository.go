@@ -0,0 +1,58 @@
+package domain_user
+
+import (
+	"context"
+	"time"
+
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/domain"
+)
+
+// Repository enxerga apenas os usuários com membership no tenant do contexto;
+// FindByEmail é a exceção, pois identifica a identidade global no login e nos convites.
+type Repository interface {
+	domain.Repository[*User]
+	domain.ReadOnlyRepository[*User]
+	FindByEmail(ctx context.Context, email string) (*User, error)
+}
+
+type MembershipRepository interface {
+	domain.Repository[*Membership]
+	domain.ReadOnlyRepository[*Membership]
+	FindByUser(ctx context.Context, userID string) (*Membership, error)
+	// LockActiveOwners bloqueia os owners ativos do tenant até o fim da transação
+	LockActiveOwners(ctx context.Context) ([]*Membership, error)
+	// FindActiveByUser ignora o tenant do contexto e lista os acessos do usuário em todos os tenants
+	FindActiveByUser(ctx context.Context, userID string) ([]*Membership, error)
+	// FindCurrentByUser também ignora o tenant do contexto e inclui as memberships suspensas ou pendentes
+	FindCurrentByUser(ctx context.Context, userID string) ([]*Membership, error)
+}
+
+type InvitationRepository interface {
+	domain.Repository[*Invitation]
+	domain.ReadOnlyRepository[*Invitation]
+	FindPendingByEmail(ctx context.Context, email string) (*Invitation, error)
+	// FindByToken ignora o tenant do contexto: o token é a única credencial do convidado
+	FindByToken(ctx context.Context, token string) (*Invitation, error)
+	// FindExpired lista convites pendentes vencidos de todos os tenants
+	FindExpired(ctx context.Context, now time.Time, limit int) ([]*Invitation, error)
+}
+
+type CredentialRepository interface {
+	Save(ctx context.Context, credential *Credential) error
+	FindByUser(ctx context.Context, userID string) (*Credential, error)
+	// LockByUser bloqueia a linha até o fim da transação para serializar o contador de falhas
+	LockByUser(ctx context.Context, userID string) (*Credential, error)
+}
+
+type PasswordPolicyRepository interface {
+	Save(ctx context.Context, policy *PasswordPolicy) error
+	// FindByTenant usa o tenant do contexto
+	FindByTenant(ctx context.Context) (*PasswordPolicy, error)
+}
+
+type RefreshTokenRepository interface {
+	Save(ctx context.Context, token *RefreshToken) error
+	// FindByToken bloqueia a linha até o fim da transação para serializar rotações concorrentes
+	FindByToken(ctx context.Context, token string) (*RefreshToken, error)
+	RevokeFamily(ctx context.Context, familyID string, now time.Time) error
+}
@@ -1,40 +1,226 @@
 package domain_user
 
 import (
+	"strings"
 	"time"
 
 	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/domain"
 	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/domain/value_objects"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/errors"
 )
 
+var _ domain.AggregateRoot = (*User)(nil)
+
 type User struct {
 	domain.BaseAggregateRoot
-	ID       string              `json:"id"`
-	Email    value_objects.Email `json:"email"`
-	CPF      value_objects.CPF   `json:"cpf"`
-	Phone    value_objects.Phone `json:"phone"`
-	IsActive bool                `json:"is_active"`
+	// HomeTenantID é o tenant onde a identidade foi criada; o acesso vem das memberships
+	HomeTenantID  string              `json:"home_tenant_id" gorm:"index"`
+	Email         value_objects.Email `json:"email" gorm:"not null;uniqueIndex"`
+	CPF           value_objects.CPF   `json:"cpf" gorm:"not null;uniqueIndex"`
+	Phone         value_objects.Phone `json:"phone"`
+	FullName      string              `json:"full_name" gorm:"not null"`
+	IsActive      bool                `json:"is_active" gorm:"not null"`
+	EmailVerified bool                `json:"email_verified" gorm:"not null"`
+}
+
+func (User) TableName() string {
+	return "users"
+}
+
+// User é uma identidade global, única por e-mail e CPF, que entra nos tenants
+// através de memberships. NewUser cria o usuário inativo e com e-mail não verificado.
+func NewUser(homeTenantID, email, cpf, phone, fullName string) (*User, error) {
+	emailVO, err := value_objects.NewEmail(email)
+	if err != nil {
+		return nil, invalidInput(err.Error())
+	}
+
+	cpfVO, err := value_objects.NewCPF(cpf)
+	if err != nil {
+		return nil, invalidInput(err.Error())
+	}
+
+	phoneVO, err := optionalPhone(phone)
+	if err != nil {
+		return nil, err
+	}
+
+	fullName = strings.TrimSpace(fullName)
+	if fullName == "" {
+		return nil, invalidInput("full name is required")
+	}
+
+	user := &User{
+		HomeTenantID: homeTenantID,
+		Email:        emailVO,
+		CPF:          cpfVO,
+		Phone:        phoneVO,
+		FullName:     fullName,
+	}
+
+	event := domain.NewBaseDomainEvent(
+		EventUserCreated,
+		user.GetID(),
+		UserCreated{
+			HomeTenantID: homeTenantID,
+			Email:        emailVO.String(),
+			FullName:     fullName,
+			CreatedAt:    time.Now().UTC(),
+		},
+	)
+	user.RaiseDomainEvent(event)
+
+	return user, nil
 }
 
+// As transições abaixo só emitem eventos quando o estado realmente muda
+
 func (u *User) Activate() {
+	if u.IsActive {
+		return
+	}
 	u.IsActive = true
+	u.touch()
+
+	event := domain.NewBaseDomainEvent(
+		EventUserActivated,
+		u.GetID(),
+		UserActivated{
+			Email:       u.Email.String(),
+			ActivatedAt: time.Now().UTC(),
+		},
+	)
+	u.RaiseDomainEvent(event)
+}
+
+func (u *User) Deactivate() {
+	if !u.IsActive {
+		return
+	}
+	u.IsActive = false
+	u.touch()
+
+	event := domain.NewBaseDomainEvent(
+		EventUserDeactivated,
+		u.GetID(),
+		UserDeactivated{
+			DeactivatedAt: time.Now().UTC(),
+		},
+	)
+	u.RaiseDomainEvent(event)
+}
+
+// ChangeEmail exige uma nova verificação do endereço
+func (u *User) ChangeEmail(email string) error {
+	emailVO, err := value_objects.NewEmail(email)
+	if err != nil {
+		return invalidInput(err.Error())
+	}
+	if emailVO == u.Email {
+		return nil
+	}
+
+	previous := u.Email
+	u.Email = emailVO
+	u.EmailVerified = false
+	u.touch()
+
+	event := domain.NewBaseDomainEvent(
+		EventUserEmailChanged,
+		u.GetID(),
+		UserEmailChanged{
+			PreviousEmail: previous.String(),
+			Email:         emailVO.String(),
+			ChangedAt:     time.Now().UTC(),
+		},
+	)
+	u.RaiseDomainEvent(event)
+	return nil
+}
+
+// ChangePhone aceita vazio para remover o telefone
+func (u *User) ChangePhone(phone string) error {
+	phoneVO, err := optionalPhone(phone)
+	if err != nil {
+		return err
+	}
+	if phoneVO == u.Phone {
+		return nil
+	}
+
+	u.Phone = phoneVO
+	u.touch()
 
 	event := domain.NewBaseDomainEvent(
-		"user.activated",
+		EventUserPhoneChanged,
 		u.GetID(),
-		map[string]interface{}{
-			"email":        u.Email.String(),
-			"activated_at": time.Now().UTC().Format(time.RFC3339),
+		UserPhoneChanged{
+			Phone:     phoneVO.String(),
+			ChangedAt: time.Now().UTC(),
 		},
 	)
 	u.RaiseDomainEvent(event)
+	return nil
+}
+
+func (u *User) ChangeFullName(fullName string) error {
+	fullName = strings.TrimSpace(fullName)
+	if fullName == "" {
+		return invalidInput("full name is required")
+	}
+	if fullName == u.FullName {
+		return nil
+	}
+
+	u.FullName = fullName
+	u.touch()
+
+	event := domain.NewBaseDomainEvent(
+		EventUserFullNameChanged,
+		u.GetID(),
+		UserFullNameChanged{
+			FullName:  fullName,
+			ChangedAt: time.Now().UTC(),
+		},
+	)
+	u.RaiseDomainEvent(event)
+	return nil
+}
+
+func (u *User) VerifyEmail() {
+	if u.EmailVerified {
+		return
+	}
+	u.EmailVerified = true
+	u.touch()
+
+	event := domain.NewBaseDomainEvent(
+		EventUserEmailVerified,
+		u.GetID(),
+		UserEmailVerified{
+			UserID:     u.GetID(),
+			Email:      u.Email.String(),
+			VerifiedAt: time.Now().UTC(),
+		},
+	)
+	u.RaiseDomainEvent(event)
+}
+
+func (u *User) touch() {
+	u.UpdatedAt = time.Now()
+}
+
+func optionalPhone(phone string) (value_objects.Phone, error) {
+	if strings.TrimSpace(phone) == "" {
+		return value_objects.Phone{}, nil
+	}
+	phoneVO, err := value_objects.NewPhone(phone)
+	if err != nil {
+		return value_objects.Phone{}, invalidInput(err.Error())
+	}
+	return phoneVO, nil
 }
 
-func NewUser(u *User) (*User, error) {
-	return &User{
-		ID:    u.GetID(),
-		Email: u.Email,
-		CPF:   u.CPF,
-		Phone: u.Phone,
-	}, nil
+func invalidInput(details string) error {
+	return errors.NewAppErrorWithDetails("INVALID_INPUT", "Invalid input data", details)
 }
@@ -0,0 +1,190 @@
+package handler_user
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/auth"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/errors"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/response"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/validator"
+	application_user "github.com/williamkoller/multi-tenant-nexus-manager/internal/user/application"
+	domain_user "github.com/williamkoller/multi-tenant-nexus-manager/internal/user/domain"
+)
+
+type AuthHandler struct {
+	service   *application_user.AuthService
+	tokens    *application_user.TokenService
+	validator *validator.Validator
+}
+
+func NewAuthHandler(service *application_user.AuthService, tokens *application_user.TokenService, validator *validator.Validator) *AuthHandler {
+	return &AuthHandler{
+		service:   service,
+		tokens:    tokens,
+		validator: validator,
+	}
+}
+
+// RegisterRoutes expõe a administração de senhas do tenant e a troca de tenant
+func (h *AuthHandler) RegisterRoutes(router gin.IRouter) {
+	router.POST("/auth/switch", h.SwitchTenant)
+	router.PUT("/users/:id/password", requireAdmin, h.SetPassword)
+	router.GET("/password-policy", h.GetPolicy)
+	router.PUT("/password-policy", requireAdmin, h.UpdatePolicy)
+}
+
+// RegisterPublicRoutes expõe as rotas autenticadas por senha ou refresh token
+func (h *AuthHandler) RegisterPublicRoutes(router gin.IRouter) {
+	router.POST("/auth/login", h.Login)
+	router.POST("/auth/refresh", h.Refresh)
+	router.POST("/auth/logout", h.Logout)
+	router.POST("/auth/password", h.ChangePassword)
+}
+
+func (h *AuthHandler) Login(c *gin.Context) {
+	var request LoginRequest
+	if err := h.validator.BindJSON(c, &request); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	result, err := h.service.Login(ctx, request.Email, request.Password)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	pair, err := h.tokens.IssueForLogin(ctx, result, request.TenantID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, LoginResponse{
+		User:    toUserResponse(result.User),
+		Tenants: result.Tenants,
+		Tokens:  toTokenResponse(pair),
+	})
+}
+
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var request RefreshRequest
+	if err := h.validator.BindJSON(c, &request); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	pair, err := h.tokens.Refresh(c.Request.Context(), request.RefreshToken)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, toTokenResponse(pair))
+}
+
+func (h *AuthHandler) Logout(c *gin.Context) {
+	var request RefreshRequest
+	if err := h.validator.BindJSON(c, &request); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	if err := h.tokens.Revoke(c.Request.Context(), request.RefreshToken); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, nil)
+}
+
+func (h *AuthHandler) SwitchTenant(c *gin.Context) {
+	principal, ok := auth.PrincipalFromContext(c.Request.Context())
+	if !ok {
+		response.Error(c, errors.ErrUnauthorized)
+		return
+	}
+
+	var request SwitchTenantRequest
+	if err := h.validator.BindJSON(c, &request); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	pair, err := h.tokens.Switch(c.Request.Context(), principal, request.TenantID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, toTokenResponse(pair))
+}
+
+func (h *AuthHandler) ChangePassword(c *gin.Context) {
+	var request ChangePasswordRequest
+	if err := h.validator.BindJSON(c, &request); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	err := h.service.ChangePassword(c.Request.Context(), application_user.ChangePasswordInput{
+		Email:           request.Email,
+		CurrentPassword: request.CurrentPassword,
+		NewPassword:     request.NewPassword,
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, nil)
+}
+
+func (h *AuthHandler) SetPassword(c *gin.Context) {
+	var request SetPasswordRequest
+	if err := h.validator.BindJSON(c, &request); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	if err := h.service.SetPassword(c.Request.Context(), c.Param("id"), request.Password); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, nil)
+}
+
+func (h *AuthHandler) GetPolicy(c *gin.Context) {
+	policy, err := h.service.GetPolicy(c.Request.Context())
+	h.replyPolicy(c, policy, err)
+}
+
+func (h *AuthHandler) UpdatePolicy(c *gin.Context) {
+	expectedVersion, err := response.IfMatchVersion(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	var request PasswordPolicyRequest
+	if err := h.validator.BindJSON(c, &request); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	policy, err := h.service.UpdatePolicy(c.Request.Context(), expectedVersion, domain_user.PasswordPolicyRules{
+		MinLength:        request.MinLength,
+		RequireUppercase: request.RequireUppercase,
+		RequireLowercase: request.RequireLowercase,
+		RequireDigit:     request.RequireDigit,
+		RequireSymbol:    request.RequireSymbol,
+		HistorySize:      request.HistorySize,
+		MaxAgeDays:       request.MaxAgeDays,
+	})
+	h.replyPolicy(c, policy, err)
+}
+
+func (h *AuthHandler) replyPolicy(c *gin.Context, policy *domain_user.PasswordPolicy, err error) {
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SetETag(c, policy.GetVersion())
+	response.Success(c, toPasswordPolicyResponse(policy))
+}
@@ -0,0 +1,232 @@
+package handler_user
+
+import (
+	"time"
+
+	application_user "github.com/williamkoller/multi-tenant-nexus-manager/internal/user/application"
+	domain_user "github.com/williamkoller/multi-tenant-nexus-manager/internal/user/domain"
+)
+
+type CreateUserRequest struct {
+	Email    string `json:"email" validate:"required,email"`
+	CPF      string `json:"cpf" validate:"required"`
+	Phone    string `json:"phone"`
+	FullName string `json:"full_name" validate:"required,max=200"`
+	Role     string `json:"role" validate:"omitempty,oneof=owner admin member"`
+}
+
+type UpdateUserRequest struct {
+	Email    string `json:"email" validate:"required,email"`
+	Phone    string `json:"phone"`
+	FullName string `json:"full_name" validate:"required,max=200"`
+}
+
+type UserResponse struct {
+	ID            string    `json:"id"`
+	HomeTenantID  string    `json:"home_tenant_id"`
+	Email         string    `json:"email"`
+	EmailVerified bool      `json:"email_verified"`
+	CPF           string    `json:"cpf"`
+	Phone         string    `json:"phone,omitempty"`
+	FullName      string    `json:"full_name"`
+	IsActive      bool      `json:"is_active"`
+	Version       int64     `json:"version"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+func toUserResponse(user *domain_user.User) UserResponse {
+	response := UserResponse{
+		ID:            user.GetID(),
+		HomeTenantID:  user.HomeTenantID,
+		Email:         user.Email.String(),
+		EmailVerified: user.EmailVerified,
+		CPF:           user.CPF.Formatted(),
+		FullName:      user.FullName,
+		IsActive:      user.IsActive,
+		Version:       user.GetVersion(),
+		CreatedAt:     user.CreatedAt,
+		UpdatedAt:     user.UpdatedAt,
+	}
+	if user.Phone.String() != "" {
+		response.Phone = user.Phone.Formatted()
+	}
+	return response
+}
+
+type ChangeRoleRequest struct {
+	Role string `json:"role" validate:"required,oneof=owner admin member"`
+}
+
+type MembershipResponse struct {
+	ID        string                       `json:"id"`
+	TenantID  string                       `json:"tenant_id"`
+	UserID    string                       `json:"user_id"`
+	Role      domain_user.Role             `json:"role"`
+	Status    domain_user.MembershipStatus `json:"status"`
+	InvitedBy string                       `json:"invited_by,omitempty"`
+	InvitedAt *time.Time                   `json:"invited_at,omitempty"`
+	JoinedAt  *time.Time                   `json:"joined_at,omitempty"`
+	Version   int64                        `json:"version"`
+	CreatedAt time.Time                    `json:"created_at"`
+	UpdatedAt time.Time                    `json:"updated_at"`
+}
+
+func toMembershipResponse(membership *domain_user.Membership) MembershipResponse {
+	return MembershipResponse{
+		ID:        membership.GetID(),
+		TenantID:  membership.TenantID,
+		UserID:    membership.UserID,
+		Role:      membership.Role,
+		Status:    membership.Status,
+		InvitedBy: membership.InvitedBy,
+		InvitedAt: membership.InvitedAt,
+		JoinedAt:  membership.JoinedAt,
+		Version:   membership.GetVersion(),
+		CreatedAt: membership.CreatedAt,
+		UpdatedAt: membership.UpdatedAt,
+	}
+}
+
+type InviteRequest struct {
+	Email string `json:"email" validate:"required,email"`
+	Role  string `json:"role" validate:"required,oneof=owner admin member"`
+}
+
+// AcceptInvitationRequest exige CPF e nome apenas para quem ainda não tem cadastro
+// e a senha apenas para quem ainda não tem senha
+type AcceptInvitationRequest struct {
+	Token    string `json:"token" validate:"required"`
+	CPF      string `json:"cpf"`
+	Phone    string `json:"phone"`
+	FullName string `json:"full_name" validate:"max=200"`
+	Password string `json:"password"`
+}
+
+type InvitationResponse struct {
+	ID             string                       `json:"id"`
+	TenantID       string                       `json:"tenant_id"`
+	Email          string                       `json:"email"`
+	Role           domain_user.Role             `json:"role"`
+	Status         domain_user.InvitationStatus `json:"status"`
+	InvitedBy      string                       `json:"invited_by,omitempty"`
+	ExpiresAt      time.Time                    `json:"expires_at"`
+	SentCount      int                          `json:"sent_count"`
+	LastSentAt     time.Time                    `json:"last_sent_at"`
+	AcceptedAt     *time.Time                   `json:"accepted_at,omitempty"`
+	AcceptedUserID string                       `json:"accepted_user_id,omitempty"`
+	Version        int64                        `json:"version"`
+	CreatedAt      time.Time                    `json:"created_at"`
+	UpdatedAt      time.Time                    `json:"updated_at"`
+}
+
+type AcceptedInvitationResponse struct {
+	User       UserResponse       `json:"user"`
+	Membership MembershipResponse `json:"membership"`
+}
+
+func toInvitationResponse(invitation *domain_user.Invitation) InvitationResponse {
+	return InvitationResponse{
+		ID:             invitation.GetID(),
+		TenantID:       invitation.TenantID,
+		Email:          invitation.Email.String(),
+		Role:           invitation.Role,
+		Status:         invitation.Status,
+		InvitedBy:      invitation.InvitedBy,
+		ExpiresAt:      invitation.ExpiresAt,
+		SentCount:      invitation.SentCount,
+		LastSentAt:     invitation.LastSentAt,
+		AcceptedAt:     invitation.AcceptedAt,
+		AcceptedUserID: invitation.AcceptedUserID,
+		Version:        invitation.GetVersion(),
+		CreatedAt:      invitation.CreatedAt,
+		UpdatedAt:      invitation.UpdatedAt,
+	}
+}
+
+// LoginRequest aceita o ID ou o slug do tenant; vazio escolhe o primeiro tenant acessível
+type LoginRequest struct {
+	Email    string `json:"email" validate:"required"`
+	Password string `json:"password" validate:"required"`
+	TenantID string `json:"tenant_id"`
+}
+
+type LoginResponse struct {
+	User    UserResponse                    `json:"user"`
+	Tenants []application_user.TenantAccess `json:"tenants"`
+	Tokens  TokenResponse                   `json:"tokens"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+type SwitchTenantRequest struct {
+	TenantID string `json:"tenant_id" validate:"required"`
+}
+
+type TokenResponse struct {
+	AccessToken      string                        `json:"access_token"`
+	TokenType        string                        `json:"token_type"`
+	ExpiresIn        int64                         `json:"expires_in"`
+	RefreshToken     string                        `json:"refresh_token"`
+	RefreshExpiresAt time.Time                     `json:"refresh_expires_at"`
+	Tenant           application_user.TenantAccess `json:"tenant"`
+}
+
+func toTokenResponse(pair *application_user.TokenPair) TokenResponse {
+	return TokenResponse{
+		AccessToken:      pair.AccessToken,
+		TokenType:        "Bearer",
+		ExpiresIn:        int64(pair.ExpiresIn.Seconds()),
+		RefreshToken:     pair.RefreshToken,
+		RefreshExpiresAt: pair.RefreshExpiresAt,
+		Tenant:           pair.Tenant,
+	}
+}
+
+type ChangePasswordRequest struct {
+	Email           string `json:"email" validate:"required"`
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required"`
+}
+
+type SetPasswordRequest struct {
+	Password string `json:"password" validate:"required"`
+}
+
+type PasswordPolicyRequest struct {
+	MinLength        int  `json:"min_length" validate:"required"`
+	RequireUppercase bool `json:"require_uppercase"`
+	RequireLowercase bool `json:"require_lowercase"`
+	RequireDigit     bool `json:"require_digit"`
+	RequireSymbol    bool `json:"require_symbol"`
+	HistorySize      int  `json:"history_size"`
+	MaxAgeDays       int  `json:"max_age_days"`
+}
+
+type PasswordPolicyResponse struct {
+	TenantID         string `json:"tenant_id"`
+	MinLength        int    `json:"min_length"`
+	RequireUppercase bool   `json:"require_uppercase"`
+	RequireLowercase bool   `json:"require_lowercase"`
+	RequireDigit     bool   `json:"require_digit"`
+	RequireSymbol    bool   `json:"require_symbol"`
+	HistorySize      int    `json:"history_size"`
+	MaxAgeDays       int    `json:"max_age_days"`
+	Version          int64  `json:"version"`
+}
+
+func toPasswordPolicyResponse(policy *domain_user.PasswordPolicy) PasswordPolicyResponse {
+	return PasswordPolicyResponse{
+		TenantID:         policy.TenantID,
+		MinLength:        policy.MinLength,
+		RequireUppercase: policy.RequireUppercase,
+		RequireLowercase: policy.RequireLowercase,
+		RequireDigit:     policy.RequireDigit,
+		RequireSymbol:    policy.RequireSymbol,
+		HistorySize:      policy.HistorySize,
+		MaxAgeDays:       policy.MaxAgeDays,
+		Version:          policy.GetVersion(),
+	}
+}
@@ -0,0 +1,243 @@
+package handler_user
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/middleware"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/query"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/response"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/validator"
+	application_user "github.com/williamkoller/multi-tenant-nexus-manager/internal/user/application"
+	domain_user "github.com/williamkoller/multi-tenant-nexus-manager/internal/user/domain"
+)
+
+type Handler struct {
+	service        *application_user.Service
+	validator      *validator.Validator
+	spec           query.Spec
+	membershipSpec query.Spec
+}
+
+func NewHandler(service *application_user.Service, validator *validator.Validator, spec, membershipSpec query.Spec) *Handler {
+	return &Handler{
+		service:        service,
+		validator:      validator,
+		spec:           spec,
+		membershipSpec: membershipSpec,
+	}
+}
+
+// requireAdmin protege as rotas administrativas do tenant
+var requireAdmin = middleware.RequireRole(string(domain_user.RoleOwner), string(domain_user.RoleAdmin))
+
+// RegisterRoutes espera um grupo que já passou pelo TenantMiddleware. Update,
+// Activate e Deactivate também servem ao próprio usuário, então a permissão
+// delas é conferida pelo service.
+func (h *Handler) RegisterRoutes(router gin.IRouter) {
+	users := router.Group("/users")
+
+	users.POST("", requireAdmin, h.Create)
+	users.GET("", h.List)
+	users.GET("/:id", h.Get)
+	users.PUT("/:id", h.Update)
+	users.DELETE("/:id", requireAdmin, h.Delete)
+	users.POST("/:id/activate", h.Activate)
+	users.POST("/:id/deactivate", h.Deactivate)
+
+	memberships := router.Group("/memberships")
+
+	memberships.GET("", h.ListMemberships)
+	memberships.GET("/:id", h.GetMembership)
+	memberships.PUT("/:id/role", requireAdmin, h.ChangeRole)
+	memberships.POST("/:id/suspend", requireAdmin, h.SuspendMembership)
+	memberships.POST("/:id/reactivate", requireAdmin, h.ReactivateMembership)
+	memberships.DELETE("/:id", requireAdmin, h.RevokeMembership)
+}
+
+func (h *Handler) Create(c *gin.Context) {
+	var request CreateUserRequest
+	if err := h.validator.BindJSON(c, &request); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	user, err := h.service.Create(c.Request.Context(), application_user.CreateUserInput{
+		Email:    request.Email,
+		CPF:      request.CPF,
+		Phone:    request.Phone,
+		FullName: request.FullName,
+		Role:     request.Role,
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SetETag(c, user.GetVersion())
+	response.Created(c, toUserResponse(user))
+}
+
+func (h *Handler) List(c *gin.Context) {
+	filter, err := query.ParseFilter(c.Request.URL.Query(), h.spec)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	page, err := h.service.List(c.Request.Context(), filter)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	items := make([]UserResponse, 0, len(page.Items))
+	for _, user := range page.Items {
+		items = append(items, toUserResponse(user))
+	}
+	response.Paginated(c, items, response.PageMeta(filter, page))
+}
+
+func (h *Handler) Get(c *gin.Context) {
+	user, err := h.service.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SetETag(c, user.GetVersion())
+	response.Success(c, toUserResponse(user))
+}
+
+func (h *Handler) Update(c *gin.Context) {
+	expectedVersion, err := response.IfMatchVersion(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	var request UpdateUserRequest
+	if err := h.validator.BindJSON(c, &request); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	user, err := h.service.Update(c.Request.Context(), c.Param("id"), expectedVersion, application_user.UpdateUserInput{
+		Email:    request.Email,
+		Phone:    request.Phone,
+		FullName: request.FullName,
+	})
+	h.reply(c, user, err)
+}
+
+func (h *Handler) Activate(c *gin.Context) {
+	h.transition(c, h.service.Activate)
+}
+
+func (h *Handler) Deactivate(c *gin.Context) {
+	h.transition(c, h.service.Deactivate)
+}
+
+func (h *Handler) Delete(c *gin.Context) {
+	if err := h.service.Delete(c.Request.Context(), c.Param("id")); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, nil)
+}
+
+func (h *Handler) transition(c *gin.Context, apply func(ctx context.Context, id string, expectedVersion int64) (*domain_user.User, error)) {
+	expectedVersion, err := response.IfMatchVersion(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	user, err := apply(c.Request.Context(), c.Param("id"), expectedVersion)
+	h.reply(c, user, err)
+}
+
+func (h *Handler) reply(c *gin.Context, user *domain_user.User, err error) {
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SetETag(c, user.GetVersion())
+	response.Success(c, toUserResponse(user))
+}
+
+func (h *Handler) ListMemberships(c *gin.Context) {
+	filter, err := query.ParseFilter(c.Request.URL.Query(), h.membershipSpec)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	page, err := h.service.ListMemberships(c.Request.Context(), filter)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	items := make([]MembershipResponse, 0, len(page.Items))
+	for _, membership := range page.Items {
+		items = append(items, toMembershipResponse(membership))
+	}
+	response.Paginated(c, items, response.PageMeta(filter, page))
+}
+
+func (h *Handler) GetMembership(c *gin.Context) {
+	membership, err := h.service.GetMembership(c.Request.Context(), c.Param("id"))
+	h.replyMembership(c, membership, err)
+}
+
+func (h *Handler) ChangeRole(c *gin.Context) {
+	expectedVersion, err := response.IfMatchVersion(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	var request ChangeRoleRequest
+	if err := h.validator.BindJSON(c, &request); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	membership, err := h.service.ChangeRole(c.Request.Context(), c.Param("id"), expectedVersion, request.Role)
+	h.replyMembership(c, membership, err)
+}
+
+func (h *Handler) SuspendMembership(c *gin.Context) {
+	h.membershipTransition(c, h.service.SuspendMembership)
+}
+
+func (h *Handler) ReactivateMembership(c *gin.Context) {
+	h.membershipTransition(c, h.service.ReactivateMembership)
+}
+
+func (h *Handler) RevokeMembership(c *gin.Context) {
+	h.membershipTransition(c, h.service.RevokeMembership)
+}
+
+func (h *Handler) membershipTransition(c *gin.Context, apply func(ctx context.Context, id string, expectedVersion int64) (*domain_user.Membership, error)) {
+	expectedVersion, err := response.IfMatchVersion(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	membership, err := apply(c.Request.Context(), c.Param("id"), expectedVersion)
+	h.replyMembership(c, membership, err)
+}
+
+func (h *Handler) replyMembership(c *gin.Context, membership *domain_user.Membership, err error) {
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SetETag(c, membership.GetVersion())
+	response.Success(c, toMembershipResponse(membership))
+}
@@ -0,0 +1,85 @@
+package handler_user
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/auth"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/errors"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/query"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/validator"
+	application_user "github.com/williamkoller/multi-tenant-nexus-manager/internal/user/application"
+	domain_user "github.com/williamkoller/multi-tenant-nexus-manager/internal/user/domain"
+)
+
+func TestHandlerUpdateRequiresCurrentETag(t *testing.T) {
+	user := &domain_user.User{FullName: "Ana"}
+	user.Initialize()
+	user.SetVersion(3)
+	router := newTestRouter(user)
+
+	get := httptest.NewRecorder()
+	router.ServeHTTP(get, httptest.NewRequest(http.MethodGet, "/users/"+user.GetID(), nil))
+	if get.Code != http.StatusOK || get.Header().Get("ETag") != `"3"` {
+		t.Fatalf("GET = %d with ETag %q, want 200 with \"3\"", get.Code, get.Header().Get("ETag"))
+	}
+
+	for name, ifMatch := range map[string]string{
+		"stale version": `"2"`,
+		"weak etag":     `W/"3"`,
+		"foreign etag":  `"abc"`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			body := strings.NewReader(`{"email":"ana@example.com","full_name":"Ana Maria"}`)
+			request := httptest.NewRequest(http.MethodPut, "/users/"+user.GetID(), body)
+			request.Header.Set("Content-Type", "application/json")
+			request.Header.Set("If-Match", ifMatch)
+
+			put := httptest.NewRecorder()
+			router.ServeHTTP(put, request)
+			if put.Code != http.StatusPreconditionFailed {
+				t.Fatalf("PUT with If-Match %s = %d, want 412: %s", ifMatch, put.Code, put.Body)
+			}
+			if user.FullName != "Ana" || user.GetVersion() != 3 {
+				t.Fatalf("user changed despite the failed precondition: %+v", user)
+			}
+		})
+	}
+}
+
+func newTestRouter(user *domain_user.User) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	service := application_user.NewService(fakeUsers{user: user}, nil, nil, fakeTxManager{}, nil)
+	handler := NewHandler(service, validator.New(), query.DefaultSpec(), query.DefaultSpec())
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		ctx := auth.WithPrincipal(c.Request.Context(), auth.Principal{UserID: user.GetID(), TenantID: "tenant-1"})
+		c.Request = c.Request.WithContext(ctx)
+	})
+	router.GET("/users/:id", handler.Get)
+	router.PUT("/users/:id", handler.Update)
+	return router
+}
+
+type fakeTxManager struct{}
+
+func (fakeTxManager) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type fakeUsers struct {
+	domain_user.Repository
+	user *domain_user.User
+}
+
+func (r fakeUsers) FindByID(_ context.Context, id string) (*domain_user.User, error) {
+	if id != r.user.GetID() {
+		return nil, errors.ErrNotFound
+	}
+	return r.user, nil
+}
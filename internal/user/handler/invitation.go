@@ -0,0 +1,147 @@
+package handler_user
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/auth"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/query"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/response"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/validator"
+	application_user "github.com/williamkoller/multi-tenant-nexus-manager/internal/user/application"
+	domain_user "github.com/williamkoller/multi-tenant-nexus-manager/internal/user/domain"
+)
+
+type InvitationHandler struct {
+	service   *application_user.InvitationService
+	validator *validator.Validator
+	spec      query.Spec
+}
+
+func NewInvitationHandler(service *application_user.InvitationService, validator *validator.Validator, spec query.Spec) *InvitationHandler {
+	return &InvitationHandler{
+		service:   service,
+		validator: validator,
+		spec:      spec,
+	}
+}
+
+// RegisterRoutes espera um grupo que já passou pelo TenantMiddleware
+func (h *InvitationHandler) RegisterRoutes(router gin.IRouter) {
+	invitations := router.Group("/invitations", requireAdmin)
+
+	invitations.POST("", h.Invite)
+	invitations.GET("", h.List)
+	invitations.GET("/:id", h.Get)
+	invitations.POST("/:id/resend", h.Resend)
+	invitations.DELETE("/:id", h.Revoke)
+}
+
+// RegisterPublicRoutes expõe o aceite, que é autenticado apenas pelo token do convite
+func (h *InvitationHandler) RegisterPublicRoutes(router gin.IRouter) {
+	router.POST("/invitations/accept", h.Accept)
+}
+
+func (h *InvitationHandler) Invite(c *gin.Context) {
+	var request InviteRequest
+	if err := h.validator.BindJSON(c, &request); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	input := application_user.InviteInput{
+		Email: request.Email,
+		Role:  request.Role,
+	}
+	if principal, ok := auth.PrincipalFromContext(c.Request.Context()); ok {
+		input.InvitedBy = principal.UserID
+	}
+
+	invitation, err := h.service.Invite(c.Request.Context(), input)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SetETag(c, invitation.GetVersion())
+	response.Created(c, toInvitationResponse(invitation))
+}
+
+func (h *InvitationHandler) List(c *gin.Context) {
+	filter, err := query.ParseFilter(c.Request.URL.Query(), h.spec)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	page, err := h.service.List(c.Request.Context(), filter)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	items := make([]InvitationResponse, 0, len(page.Items))
+	for _, invitation := range page.Items {
+		items = append(items, toInvitationResponse(invitation))
+	}
+	response.Paginated(c, items, response.PageMeta(filter, page))
+}
+
+func (h *InvitationHandler) Get(c *gin.Context) {
+	invitation, err := h.service.Get(c.Request.Context(), c.Param("id"))
+	h.reply(c, invitation, err)
+}
+
+func (h *InvitationHandler) Resend(c *gin.Context) {
+	h.transition(c, h.service.Resend)
+}
+
+func (h *InvitationHandler) Revoke(c *gin.Context) {
+	h.transition(c, h.service.Revoke)
+}
+
+func (h *InvitationHandler) Accept(c *gin.Context) {
+	var request AcceptInvitationRequest
+	if err := h.validator.BindJSON(c, &request); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	accepted, err := h.service.Accept(c.Request.Context(), application_user.AcceptInvitationInput{
+		Token:    request.Token,
+		CPF:      request.CPF,
+		Phone:    request.Phone,
+		FullName: request.FullName,
+		Password: request.Password,
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, AcceptedInvitationResponse{
+		User:       toUserResponse(accepted.User),
+		Membership: toMembershipResponse(accepted.Membership),
+	})
+}
+
+func (h *InvitationHandler) transition(c *gin.Context, apply func(ctx context.Context, id string, expectedVersion int64) (*domain_user.Invitation, error)) {
+	expectedVersion, err := response.IfMatchVersion(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	invitation, err := apply(c.Request.Context(), c.Param("id"), expectedVersion)
+	h.reply(c, invitation, err)
+}
+
+func (h *InvitationHandler) reply(c *gin.Context, invitation *domain_user.Invitation, err error) {
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SetETag(c, invitation.GetVersion())
+	response.Success(c, toInvitationResponse(invitation))
+}
@@ -0,0 +1,290 @@
+package infrastructure_user
+
+import (
+	"context"
+	"time"
+
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/database"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/domain/value_objects"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/errors"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/query"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/repository"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/tenancy"
+	domain_user "github.com/williamkoller/multi-tenant-nexus-manager/internal/user/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	_ domain_user.Repository           = (*Repository)(nil)
+	_ domain_user.MembershipRepository = (*MembershipRepository)(nil)
+	_ domain_user.InvitationRepository = (*InvitationRepository)(nil)
+
+	_ domain_user.CredentialRepository     = (*CredentialRepository)(nil)
+	_ domain_user.PasswordPolicyRepository = (*PasswordPolicyRepository)(nil)
+	_ domain_user.RefreshTokenRepository   = (*RefreshTokenRepository)(nil)
+)
+
+// Models lista as tabelas do contexto para database.Migrate
+func Models() []interface{} {
+	return []interface{}{
+		&domain_user.User{},
+		&domain_user.Membership{},
+		&domain_user.Invitation{},
+		&domain_user.Credential{},
+		&domain_user.PasswordPolicy{},
+		&domain_user.RefreshToken{},
+	}
+}
+
+func Spec() query.Spec {
+	return query.DefaultSpec().
+		WithFilterable("email", "cpf", "phone", "full_name", "is_active", "email_verified").
+		WithSortable("email", "full_name")
+}
+
+func MembershipSpec() query.Spec {
+	return query.DefaultSpec().WithFilterable("user_id", "role", "status")
+}
+
+func InvitationSpec() query.Spec {
+	return query.DefaultSpec().
+		WithFilterable("email", "role", "status").
+		WithSortable("email", "expires_at")
+}
+
+type Repository struct {
+	*repository.GormRepository[*domain_user.User]
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB, spec query.Spec) *Repository {
+	return &Repository{
+		GormRepository: repository.NewGormRepository[*domain_user.User](db, spec).WithScope(memberScope),
+		db:             db,
+	}
+}
+
+func (r *Repository) FindByEmail(ctx context.Context, email string) (*domain_user.User, error) {
+	emailVO, err := value_objects.NewEmail(email)
+	if err != nil {
+		return nil, errors.ErrNotFound
+	}
+
+	var user domain_user.User
+	if err := database.GetTxFromContext(ctx, r.db).First(&user, "email = ?", emailVO).Error; err != nil {
+		return nil, repository.TranslateError(err)
+	}
+	return &user, nil
+}
+
+// memberScope limita os usuários aos que têm membership não revogada no tenant do contexto
+func memberScope(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tenancy.IsSystemScope(ctx) {
+		return db
+	}
+
+	tenantID, ok := tenancy.TenantIDFromContext(ctx)
+	if !ok {
+		scoped := db.Where("1 = 0")
+		scoped.AddError(database.ErrTenantScopeMissing)
+		return scoped
+	}
+
+	return db.Where(
+		"EXISTS (SELECT 1 FROM memberships WHERE memberships.user_id = users.id AND memberships.tenant_id = ? AND memberships.status <> ?)",
+		tenantID, domain_user.MembershipRevoked,
+	)
+}
+
+type MembershipRepository struct {
+	*repository.GormRepository[*domain_user.Membership]
+	db *gorm.DB
+}
+
+func NewMembershipRepository(db *gorm.DB, spec query.Spec) *MembershipRepository {
+	return &MembershipRepository{
+		GormRepository: repository.NewGormRepository[*domain_user.Membership](db, spec),
+		db:             db,
+	}
+}
+
+func (r *MembershipRepository) FindByUser(ctx context.Context, userID string) (*domain_user.Membership, error) {
+	var membership domain_user.Membership
+	if err := r.DB(ctx).First(&membership, "user_id = ?", userID).Error; err != nil {
+		return nil, repository.TranslateError(err)
+	}
+	return &membership, nil
+}
+
+// LockActiveOwners serializa remoções concorrentes de owners: a segunda
+// transação espera a primeira e já enxerga o owner removido
+func (r *MembershipRepository) LockActiveOwners(ctx context.Context) ([]*domain_user.Membership, error) {
+	var owners []*domain_user.Membership
+	err := r.DB(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("role = ? AND status = ?", domain_user.RoleOwner, domain_user.MembershipActive).
+		Find(&owners).Error
+	if err != nil {
+		return nil, repository.TranslateError(err)
+	}
+	return owners, nil
+}
+
+// FindActiveByUser deve rodar fora de transações de tenant, cujo RLS esconderia os demais tenants
+func (r *MembershipRepository) FindActiveByUser(ctx context.Context, userID string) ([]*domain_user.Membership, error) {
+	systemCtx := tenancy.WithSystemScope(ctx)
+
+	var memberships []*domain_user.Membership
+	err := database.GetTxFromContext(systemCtx, r.db).WithContext(systemCtx).
+		Where("user_id = ? AND status = ?", userID, domain_user.MembershipActive).
+		Order("created_at").
+		Find(&memberships).Error
+	if err != nil {
+		return nil, repository.TranslateError(err)
+	}
+	return memberships, nil
+}
+
+// FindCurrentByUser também deve rodar fora de transações de tenant
+func (r *MembershipRepository) FindCurrentByUser(ctx context.Context, userID string) ([]*domain_user.Membership, error) {
+	systemCtx := tenancy.WithSystemScope(ctx)
+
+	var memberships []*domain_user.Membership
+	err := database.GetTxFromContext(systemCtx, r.db).WithContext(systemCtx).
+		Where("user_id = ? AND status <> ?", userID, domain_user.MembershipRevoked).
+		Order("created_at").
+		Find(&memberships).Error
+	if err != nil {
+		return nil, repository.TranslateError(err)
+	}
+	return memberships, nil
+}
+
+type InvitationRepository struct {
+	*repository.GormRepository[*domain_user.Invitation]
+	db *gorm.DB
+}
+
+func NewInvitationRepository(db *gorm.DB, spec query.Spec) *InvitationRepository {
+	return &InvitationRepository{
+		GormRepository: repository.NewGormRepository[*domain_user.Invitation](db, spec),
+		db:             db,
+	}
+}
+
+func (r *InvitationRepository) FindPendingByEmail(ctx context.Context, email string) (*domain_user.Invitation, error) {
+	emailVO, err := value_objects.NewEmail(email)
+	if err != nil {
+		return nil, errors.ErrNotFound
+	}
+
+	var invitation domain_user.Invitation
+	err = r.DB(ctx).First(&invitation, "email = ? AND status = ?", emailVO, domain_user.InvitationStatusPending).Error
+	if err != nil {
+		return nil, repository.TranslateError(err)
+	}
+	return &invitation, nil
+}
+
+func (r *InvitationRepository) FindByToken(ctx context.Context, token string) (*domain_user.Invitation, error) {
+	systemCtx := tenancy.WithSystemScope(ctx)
+
+	var invitation domain_user.Invitation
+	err := database.GetTxFromContext(systemCtx, r.db).WithContext(systemCtx).
+		First(&invitation, "token_hash = ?", domain_user.HashInvitationToken(token)).Error
+	if err != nil {
+		return nil, repository.TranslateError(err)
+	}
+	return &invitation, nil
+}
+
+func (r *InvitationRepository) FindExpired(ctx context.Context, now time.Time, limit int) ([]*domain_user.Invitation, error) {
+	systemCtx := tenancy.WithSystemScope(ctx)
+
+	var invitations []*domain_user.Invitation
+	err := database.GetTxFromContext(systemCtx, r.db).WithContext(systemCtx).
+		Where("status = ? AND expires_at <= ?", domain_user.InvitationStatusPending, now).
+		Order("expires_at").
+		Limit(limit).
+		Find(&invitations).Error
+	if err != nil {
+		return nil, repository.TranslateError(err)
+	}
+	return invitations, nil
+}
+
+type CredentialRepository struct {
+	*repository.GormRepository[*domain_user.Credential]
+}
+
+func NewCredentialRepository(db *gorm.DB) *CredentialRepository {
+	return &CredentialRepository{
+		GormRepository: repository.NewGormRepository[*domain_user.Credential](db, query.DefaultSpec()),
+	}
+}
+
+func (r *CredentialRepository) FindByUser(ctx context.Context, userID string) (*domain_user.Credential, error) {
+	var credential domain_user.Credential
+	if err := r.DB(ctx).First(&credential, "user_id = ?", userID).Error; err != nil {
+		return nil, repository.TranslateError(err)
+	}
+	return &credential, nil
+}
+
+func (r *CredentialRepository) LockByUser(ctx context.Context, userID string) (*domain_user.Credential, error) {
+	var credential domain_user.Credential
+	err := r.DB(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&credential, "user_id = ?", userID).Error
+	if err != nil {
+		return nil, repository.TranslateError(err)
+	}
+	return &credential, nil
+}
+
+type PasswordPolicyRepository struct {
+	*repository.GormRepository[*domain_user.PasswordPolicy]
+}
+
+func NewPasswordPolicyRepository(db *gorm.DB) *PasswordPolicyRepository {
+	return &PasswordPolicyRepository{
+		GormRepository: repository.NewGormRepository[*domain_user.PasswordPolicy](db, query.DefaultSpec()),
+	}
+}
+
+func (r *PasswordPolicyRepository) FindByTenant(ctx context.Context) (*domain_user.PasswordPolicy, error) {
+	var policy domain_user.PasswordPolicy
+	if err := r.DB(ctx).First(&policy).Error; err != nil {
+		return nil, repository.TranslateError(err)
+	}
+	return &policy, nil
+}
+
+type RefreshTokenRepository struct {
+	*repository.GormRepository[*domain_user.RefreshToken]
+}
+
+func NewRefreshTokenRepository(db *gorm.DB) *RefreshTokenRepository {
+	return &RefreshTokenRepository{
+		GormRepository: repository.NewGormRepository[*domain_user.RefreshToken](db, query.DefaultSpec()),
+	}
+}
+
+func (r *RefreshTokenRepository) FindByToken(ctx context.Context, token string) (*domain_user.RefreshToken, error) {
+	var refresh domain_user.RefreshToken
+	err := r.DB(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&refresh, "token_hash = ?", domain_user.HashRefreshToken(token)).Error
+	if err != nil {
+		return nil, repository.TranslateError(err)
+	}
+	return &refresh, nil
+}
+
+func (r *RefreshTokenRepository) RevokeFamily(ctx context.Context, familyID string, now time.Time) error {
+	err := r.DB(ctx).Model(&domain_user.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Updates(map[string]interface{}{"revoked_at": now, "updated_at": now, "version": gorm.Expr("version + 1")}).Error
+	return repository.TranslateError(err)
+}
@@ -0,0 +1,61 @@
+package application_webhook
+
+import (
+	"context"
+	"time"
+
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/database"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/domain"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/events"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/tenancy"
+	domain_webhook "github.com/williamkoller/multi-tenant-nexus-manager/internal/webhook/domain"
+)
+
+// Dispatcher transforma domain events publicados pelo relay do outbox em
+// entregas pendentes para as assinaturas do tenant.
+type Dispatcher struct {
+	subscriptions domain_webhook.SubscriptionRepository
+	deliveries    domain_webhook.DeliveryRepository
+	txManager     database.TxManager
+}
+
+func NewDispatcher(subscriptions domain_webhook.SubscriptionRepository, deliveries domain_webhook.DeliveryRepository, txManager database.TxManager) *Dispatcher {
+	return &Dispatcher{
+		subscriptions: subscriptions,
+		deliveries:    deliveries,
+		txManager:     txManager,
+	}
+}
+
+// Subscribe registra o dispatcher para todos os eventos do bus. Ele roda de
+// forma assíncrona: uma falha ao enfileirar não falha a publicação, senão o
+// relay reentregaria o evento a todos os assinantes, como o envio de convites.
+func (d *Dispatcher) Subscribe(bus events.EventBus) func() {
+	return bus.Subscribe("*", d.Handle,
+		events.WithName("webhook.dispatcher"), events.WithRetry(5, time.Second), events.Async())
+}
+
+// Handle é idempotente: o relay pode reentregar o mesmo evento após uma falha
+func (d *Dispatcher) Handle(ctx context.Context, event domain.DomainEvent) error {
+	// Eventos sem tenant (operações de sistema) não têm assinaturas
+	if _, ok := tenancy.FromContext(ctx); !ok {
+		return nil
+	}
+
+	return d.txManager.WithTx(ctx, func(ctx context.Context) error {
+		subscriptions, err := d.subscriptions.FindMatching(ctx, event.GetEventType())
+		if err != nil || len(subscriptions) == 0 {
+			return err
+		}
+
+		deliveries := make([]*domain_webhook.Delivery, 0, len(subscriptions))
+		for _, subscription := range subscriptions {
+			delivery, err := domain_webhook.NewDelivery(subscription, event)
+			if err != nil {
+				return err
+			}
+			deliveries = append(deliveries, delivery)
+		}
+		return d.deliveries.Enqueue(ctx, deliveries)
+	})
+}
@@ -0,0 +1,169 @@
+package application_webhook
+
+import (
+	"context"
+
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/database"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/domain"
+	domain_webhook "github.com/williamkoller/multi-tenant-nexus-manager/internal/webhook/domain"
+)
+
+type CreateSubscriptionInput struct {
+	URL        string
+	EventTypes []string
+	Secret     string
+}
+
+type UpdateSubscriptionInput struct {
+	URL        string
+	EventTypes []string
+	Active     *bool
+	// RotateSecret gera um novo segredo quando Secret vem vazio
+	RotateSecret bool
+	Secret       string
+}
+
+// Service concentra os casos de uso de assinaturas e entregas. As leituras
+// também rodam em transação para que a sessão de RLS do tenant seja aplicada.
+type Service struct {
+	subscriptions domain_webhook.SubscriptionRepository
+	deliveries    domain_webhook.DeliveryRepository
+	txManager     database.TxManager
+}
+
+func NewService(subscriptions domain_webhook.SubscriptionRepository, deliveries domain_webhook.DeliveryRepository, txManager database.TxManager) *Service {
+	return &Service{
+		subscriptions: subscriptions,
+		deliveries:    deliveries,
+		txManager:     txManager,
+	}
+}
+
+func (s *Service) CreateSubscription(ctx context.Context, input CreateSubscriptionInput) (*domain_webhook.Subscription, error) {
+	subscription, err := domain_webhook.NewSubscription(input.URL, input.EventTypes, input.Secret)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		return s.subscriptions.Save(ctx, subscription)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
+
+func (s *Service) UpdateSubscription(ctx context.Context, id string, expectedVersion int64, input UpdateSubscriptionInput) (*domain_webhook.Subscription, error) {
+	var subscription *domain_webhook.Subscription
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		found, err := s.subscriptions.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := domain.ExpectVersion(found, expectedVersion); err != nil {
+			return err
+		}
+
+		if err := found.Update(input.URL, input.EventTypes); err != nil {
+			return err
+		}
+		if input.RotateSecret || input.Secret != "" {
+			if err := found.RotateSecret(input.Secret); err != nil {
+				return err
+			}
+		}
+		if input.Active != nil {
+			if *input.Active {
+				found.Enable()
+			} else {
+				found.Disable()
+			}
+		}
+
+		subscription = found
+		return s.subscriptions.Save(ctx, found)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
+
+func (s *Service) GetSubscription(ctx context.Context, id string) (*domain_webhook.Subscription, error) {
+	var subscription *domain_webhook.Subscription
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		found, err := s.subscriptions.FindByID(ctx, id)
+		subscription = found
+		return err
+	})
+	return subscription, err
+}
+
+func (s *Service) ListSubscriptions(ctx context.Context, filter domain.Filter) (domain.Page[*domain_webhook.Subscription], error) {
+	var page domain.Page[*domain_webhook.Subscription]
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		found, err := s.subscriptions.FindAll(ctx, filter)
+		page = found
+		return err
+	})
+	return page, err
+}
+
+func (s *Service) DeleteSubscription(ctx context.Context, id string) error {
+	return s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		return s.subscriptions.Delete(ctx, id)
+	})
+}
+
+func (s *Service) GetDelivery(ctx context.Context, id string) (*domain_webhook.Delivery, []domain_webhook.DeliveryAttempt, error) {
+	var (
+		delivery *domain_webhook.Delivery
+		attempts []domain_webhook.DeliveryAttempt
+	)
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		found, err := s.deliveries.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		delivery = found
+
+		attempts, err = s.deliveries.Attempts(ctx, id)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return delivery, attempts, nil
+}
+
+func (s *Service) ListDeliveries(ctx context.Context, filter domain.Filter) (domain.Page[*domain_webhook.Delivery], error) {
+	var page domain.Page[*domain_webhook.Delivery]
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		found, err := s.deliveries.FindAll(ctx, filter)
+		page = found
+		return err
+	})
+	return page, err
+}
+
+// Redeliver reenfileira manualmente uma entrega, inclusive as que estão em dead-letter
+func (s *Service) Redeliver(ctx context.Context, id string) (*domain_webhook.Delivery, error) {
+	var delivery *domain_webhook.Delivery
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		found, err := s.deliveries.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := found.Redeliver(); err != nil {
+			return err
+		}
+
+		delivery = found
+		return s.deliveries.Save(ctx, found)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
@@ -0,0 +1,142 @@
+package domain_webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/domain"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/errors"
+)
+
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliveryDelivered DeliveryStatus = "delivered"
+	// DeliveryDead indica que as tentativas se esgotaram; só sai daqui via Redeliver
+	DeliveryDead DeliveryStatus = "dead"
+)
+
+var _ domain.AggregateRoot = (*Delivery)(nil)
+
+type Delivery struct {
+	domain.BaseAggregateRoot
+	TenantID       string         `json:"tenant_id" gorm:"index;not null"`
+	SubscriptionID string         `json:"subscription_id" gorm:"not null;uniqueIndex:idx_webhook_delivery_event"`
+	EventID        string         `json:"event_id" gorm:"not null;uniqueIndex:idx_webhook_delivery_event"`
+	EventType      string         `json:"event_type" gorm:"index;not null"`
+	Payload        string         `json:"payload" gorm:"type:jsonb;not null"`
+	Status         DeliveryStatus `json:"status" gorm:"index;not null"`
+	Attempts       int            `json:"attempts" gorm:"not null;default:0"`
+	NextAttemptAt  time.Time      `json:"next_attempt_at" gorm:"index;not null"`
+	LastStatusCode int            `json:"last_status_code,omitempty"`
+	LastError      string         `json:"last_error,omitempty"`
+	DeliveredAt    *time.Time     `json:"delivered_at,omitempty"`
+}
+
+func (Delivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+// envelope é o corpo enviado ao endpoint do cliente
+type envelope struct {
+	ID          string      `json:"id"`
+	Type        string      `json:"type"`
+	AggregateID string      `json:"aggregate_id"`
+	OccurredOn  string      `json:"occurred_on"`
+	Data        interface{} `json:"data"`
+}
+
+func NewDelivery(subscription *Subscription, event domain.DomainEvent) (*Delivery, error) {
+	payload, err := json.Marshal(envelope{
+		ID:          event.GetEventID(),
+		Type:        event.GetEventType(),
+		AggregateID: event.GetAggregateID(),
+		OccurredOn:  event.GetOccurredOn(),
+		Data:        event.GetEventData(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode webhook payload for %s: %w", event.GetEventType(), err)
+	}
+
+	delivery := &Delivery{
+		TenantID:       subscription.TenantID,
+		SubscriptionID: subscription.GetID(),
+		EventID:        event.GetEventID(),
+		EventType:      event.GetEventType(),
+		Payload:        string(payload),
+		Status:         DeliveryPending,
+		NextAttemptAt:  time.Now().UTC(),
+	}
+	delivery.GetID()
+	return delivery, nil
+}
+
+func (d *Delivery) RecordSuccess(statusCode int, at time.Time) {
+	d.Attempts++
+	d.Status = DeliveryDelivered
+	d.LastStatusCode = statusCode
+	d.LastError = ""
+	d.DeliveredAt = &at
+	d.UpdatedAt = at
+}
+
+// RecordFailure agenda a próxima tentativa ou move a entrega para dead-letter
+func (d *Delivery) RecordFailure(statusCode int, reason string, at time.Time, maxAttempts int, backoff time.Duration) {
+	d.Attempts++
+	d.LastStatusCode = statusCode
+	d.LastError = reason
+	d.UpdatedAt = at
+	if d.Attempts >= maxAttempts {
+		d.Status = DeliveryDead
+		return
+	}
+	d.NextAttemptAt = at.Add(backoff)
+}
+
+// Redeliver recoloca a entrega na fila com um novo ciclo de tentativas
+func (d *Delivery) Redeliver() error {
+	if d.Status == DeliveryPending {
+		return errors.NewAppErrorWithDetails("CONFLICT", "Resource conflict", "delivery is already pending")
+	}
+
+	now := time.Now().UTC()
+	d.Status = DeliveryPending
+	d.Attempts = 0
+	d.NextAttemptAt = now
+	d.LastError = ""
+	d.DeliveredAt = nil
+	d.UpdatedAt = now
+	return nil
+}
+
+// DeliveryAttempt registra cada chamada HTTP feita para uma entrega
+type DeliveryAttempt struct {
+	domain.BaseEntity
+	TenantID   string `json:"tenant_id" gorm:"index;not null"`
+	DeliveryID string `json:"delivery_id" gorm:"index;not null"`
+	Attempt    int    `json:"attempt" gorm:"not null"`
+	StatusCode int    `json:"status_code,omitempty"`
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+func (DeliveryAttempt) TableName() string {
+	return "webhook_delivery_attempts"
+}
+
+// O corpo da resposta não é guardado: devolvê-lo ao tenant permitiria ler
+// respostas de serviços que o endpoint redirecionasse ou representasse
+func NewDeliveryAttempt(delivery *Delivery, statusCode int, reason string, duration time.Duration) DeliveryAttempt {
+	attempt := DeliveryAttempt{
+		TenantID:   delivery.TenantID,
+		DeliveryID: delivery.GetID(),
+		Attempt:    delivery.Attempts,
+		StatusCode: statusCode,
+		Error:      reason,
+		DurationMs: duration.Milliseconds(),
+	}
+	attempt.Initialize()
+	return attempt
+}
@@ -0,0 +1,21 @@
+package domain_webhook
+
+import (
+	"context"
+
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/domain"
+)
+
+type SubscriptionRepository interface {
+	domain.Repository[*Subscription]
+	domain.ReadOnlyRepository[*Subscription]
+	FindMatching(ctx context.Context, eventType string) ([]*Subscription, error)
+}
+
+type DeliveryRepository interface {
+	domain.Repository[*Delivery]
+	domain.ReadOnlyRepository[*Delivery]
+	// Enqueue ignora entregas já existentes para o mesmo evento e assinatura
+	Enqueue(ctx context.Context, deliveries []*Delivery) error
+	Attempts(ctx context.Context, deliveryID string) ([]DeliveryAttempt, error)
+}
@@ -0,0 +1,60 @@
+package domain_webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	HeaderDeliveryID = "X-Webhook-Delivery"
+	HeaderEventType  = "X-Webhook-Event"
+	HeaderTimestamp  = "X-Webhook-Timestamp"
+	HeaderSignature  = "X-Webhook-Signature"
+
+	signatureScheme = "v1"
+
+	// DefaultTolerance é a janela aceita entre o timestamp assinado e o relógio do receptor
+	DefaultTolerance = 5 * time.Minute
+)
+
+// Sign assina "<timestamp>.<corpo>" com HMAC-SHA256; o timestamp no conteúdo
+// assinado impede que uma entrega capturada seja reaproveitada mais tarde.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp.Unix(), 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return signatureScheme + "=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify é o lado do receptor: confere a assinatura e rejeita timestamps fora da tolerância
+func Verify(secret, timestampHeader, signatureHeader string, body []byte, tolerance time.Duration, now time.Time) error {
+	unix, err := strconv.ParseInt(timestampHeader, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid webhook timestamp")
+	}
+	timestamp := time.Unix(unix, 0)
+
+	if tolerance > 0 {
+		drift := now.Sub(timestamp)
+		if drift < 0 {
+			drift = -drift
+		}
+		if drift > tolerance {
+			return fmt.Errorf("webhook timestamp outside tolerance")
+		}
+	}
+
+	expected := Sign(secret, timestamp, body)
+	for _, candidate := range strings.Split(signatureHeader, ",") {
+		if hmac.Equal([]byte(strings.TrimSpace(candidate)), []byte(expected)) {
+			return nil
+		}
+	}
+	return fmt.Errorf("webhook signature mismatch")
+}
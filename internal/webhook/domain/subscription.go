@@ -0,0 +1,201 @@
+package domain_webhook
+
+import (
+	"crypto/rand"
+	"database/sql/driver"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/netip"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/domain"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/errors"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/events"
+)
+
+const minSecretLength = 16
+
+var _ domain.AggregateRoot = (*Subscription)(nil)
+
+// EventTypes aceita tipos exatos ("user.activated") e wildcards ("user.*", "*")
+type EventTypes []string
+
+func (e EventTypes) Value() (driver.Value, error) {
+	if e == nil {
+		e = EventTypes{}
+	}
+	data, err := json.Marshal([]string(e))
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+func (e *EventTypes) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*e = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, (*[]string)(e))
+	case string:
+		return json.Unmarshal([]byte(v), (*[]string)(e))
+	default:
+		return fmt.Errorf("cannot scan %T into EventTypes", value)
+	}
+}
+
+// Subscription guarda o segredo em claro: ele é necessário para assinar cada entrega
+type Subscription struct {
+	domain.BaseAggregateRoot
+	TenantID   string     `json:"tenant_id" gorm:"index;not null"`
+	URL        string     `json:"url" gorm:"not null"`
+	EventTypes EventTypes `json:"event_types" gorm:"type:jsonb;not null"`
+	Secret     string     `json:"-" gorm:"not null"`
+	Active     bool       `json:"active" gorm:"not null"`
+}
+
+func (Subscription) TableName() string {
+	return "webhook_subscriptions"
+}
+
+// NewSubscription gera um segredo aleatório quando nenhum é informado
+func NewSubscription(rawURL string, eventTypes []string, secret string) (*Subscription, error) {
+	subscription := &Subscription{Active: true}
+	if err := subscription.Update(rawURL, eventTypes); err != nil {
+		return nil, err
+	}
+	if err := subscription.RotateSecret(secret); err != nil {
+		return nil, err
+	}
+	subscription.GetID()
+	return subscription, nil
+}
+
+func (s *Subscription) Update(rawURL string, eventTypes []string) error {
+	normalizedURL, err := normalizeURL(rawURL)
+	if err != nil {
+		return err
+	}
+	normalizedTypes, err := normalizeEventTypes(eventTypes)
+	if err != nil {
+		return err
+	}
+
+	s.URL = normalizedURL
+	s.EventTypes = normalizedTypes
+	s.UpdatedAt = time.Now()
+	return nil
+}
+
+func (s *Subscription) RotateSecret(secret string) error {
+	secret = strings.TrimSpace(secret)
+	if secret == "" {
+		generated, err := GenerateSecret()
+		if err != nil {
+			return err
+		}
+		secret = generated
+	}
+	if len(secret) < minSecretLength {
+		return errors.NewAppErrorWithDetails("INVALID_INPUT", "Invalid input data",
+			fmt.Sprintf("secret must have at least %d characters", minSecretLength))
+	}
+
+	s.Secret = secret
+	s.UpdatedAt = time.Now()
+	return nil
+}
+
+func (s *Subscription) Enable() {
+	s.Active = true
+	s.UpdatedAt = time.Now()
+}
+
+func (s *Subscription) Disable() {
+	s.Active = false
+	s.UpdatedAt = time.Now()
+}
+
+func (s *Subscription) Matches(eventType string) bool {
+	if !s.Active {
+		return false
+	}
+	for _, pattern := range s.EventTypes {
+		if events.Match(pattern, eventType) {
+			return true
+		}
+	}
+	return false
+}
+
+func GenerateSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(buf), nil
+}
+
+func normalizeURL(rawURL string) (string, error) {
+	rawURL = strings.TrimSpace(rawURL)
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Host == "" || (parsed.Scheme != "https" && parsed.Scheme != "http") {
+		return "", errors.NewAppErrorWithDetails("INVALID_INPUT", "Invalid input data", "webhook url must be an absolute http(s) url")
+	}
+
+	// Verificação antecipada; o worker confere de novo o IP resolvido a cada conexão
+	host := strings.ToLower(parsed.Hostname())
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return "", errors.NewAppErrorWithDetails("INVALID_INPUT", "Invalid input data", "webhook url must point to a public address")
+	}
+	if addr, err := netip.ParseAddr(host); err == nil && !IsPublicAddress(addr) {
+		return "", errors.NewAppErrorWithDetails("INVALID_INPUT", "Invalid input data", "webhook url must point to a public address")
+	}
+	return parsed.String(), nil
+}
+
+var reservedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+}
+
+// IsPublicAddress recusa loopback, redes privadas, link-local (onde ficam os
+// serviços de metadados de nuvem) e faixas reservadas
+func IsPublicAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsValid() || addr.IsUnspecified() || addr.IsLoopback() || addr.IsPrivate() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() || addr.IsInterfaceLocalMulticast() || addr.IsMulticast() {
+		return false
+	}
+	for _, prefix := range reservedPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+func normalizeEventTypes(eventTypes []string) (EventTypes, error) {
+	seen := make(map[string]bool, len(eventTypes))
+	normalized := make(EventTypes, 0, len(eventTypes))
+	for _, eventType := range eventTypes {
+		eventType = strings.TrimSpace(eventType)
+		if eventType == "" || seen[eventType] {
+			continue
+		}
+		seen[eventType] = true
+		normalized = append(normalized, eventType)
+	}
+	if len(normalized) == 0 {
+		return nil, errors.NewAppErrorWithDetails("INVALID_INPUT", "Invalid input data", "at least one event type is required")
+	}
+	return normalized, nil
+}
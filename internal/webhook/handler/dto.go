@@ -0,0 +1,88 @@
+package handler_webhook
+
+import (
+	"encoding/json"
+	"time"
+
+	domain_webhook "github.com/williamkoller/multi-tenant-nexus-manager/internal/webhook/domain"
+)
+
+type CreateSubscriptionRequest struct {
+	URL        string   `json:"url" validate:"required,url"`
+	EventTypes []string `json:"event_types" validate:"required,min=1,dive,required"`
+	Secret     string   `json:"secret" validate:"omitempty,min=16"`
+}
+
+type UpdateSubscriptionRequest struct {
+	URL          string   `json:"url" validate:"required,url"`
+	EventTypes   []string `json:"event_types" validate:"required,min=1,dive,required"`
+	Active       *bool    `json:"active"`
+	RotateSecret bool     `json:"rotate_secret"`
+	Secret       string   `json:"secret" validate:"omitempty,min=16"`
+}
+
+type SubscriptionResponse struct {
+	ID         string    `json:"id"`
+	URL        string    `json:"url"`
+	EventTypes []string  `json:"event_types"`
+	Active     bool      `json:"active"`
+	Secret     string    `json:"secret,omitempty"`
+	Version    int64     `json:"version"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// O segredo só é devolvido na criação e na rotação
+func toSubscriptionResponse(subscription *domain_webhook.Subscription, withSecret bool) SubscriptionResponse {
+	response := SubscriptionResponse{
+		ID:         subscription.GetID(),
+		URL:        subscription.URL,
+		EventTypes: subscription.EventTypes,
+		Active:     subscription.Active,
+		Version:    subscription.GetVersion(),
+		CreatedAt:  subscription.CreatedAt,
+		UpdatedAt:  subscription.UpdatedAt,
+	}
+	if withSecret {
+		response.Secret = subscription.Secret
+	}
+	return response
+}
+
+type DeliveryResponse struct {
+	ID             string                           `json:"id"`
+	SubscriptionID string                           `json:"subscription_id"`
+	EventID        string                           `json:"event_id"`
+	EventType      string                           `json:"event_type"`
+	Payload        json.RawMessage                  `json:"payload"`
+	Status         domain_webhook.DeliveryStatus    `json:"status"`
+	Attempts       int                              `json:"attempts"`
+	NextAttemptAt  *time.Time                       `json:"next_attempt_at,omitempty"`
+	LastStatusCode int                              `json:"last_status_code,omitempty"`
+	LastError      string                           `json:"last_error,omitempty"`
+	DeliveredAt    *time.Time                       `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time                        `json:"created_at"`
+	AttemptLog     []domain_webhook.DeliveryAttempt `json:"attempt_log,omitempty"`
+}
+
+func toDeliveryResponse(delivery *domain_webhook.Delivery, attempts []domain_webhook.DeliveryAttempt) DeliveryResponse {
+	response := DeliveryResponse{
+		ID:             delivery.GetID(),
+		SubscriptionID: delivery.SubscriptionID,
+		EventID:        delivery.EventID,
+		EventType:      delivery.EventType,
+		Payload:        json.RawMessage(delivery.Payload),
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		LastStatusCode: delivery.LastStatusCode,
+		LastError:      delivery.LastError,
+		DeliveredAt:    delivery.DeliveredAt,
+		CreatedAt:      delivery.CreatedAt,
+		AttemptLog:     attempts,
+	}
+	if delivery.Status == domain_webhook.DeliveryPending {
+		next := delivery.NextAttemptAt
+		response.NextAttemptAt = &next
+	}
+	return response
+}
@@ -0,0 +1,167 @@
+package handler_webhook
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/query"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/response"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/validator"
+	application_webhook "github.com/williamkoller/multi-tenant-nexus-manager/internal/webhook/application"
+)
+
+type Handler struct {
+	service          *application_webhook.Service
+	validator        *validator.Validator
+	subscriptionSpec query.Spec
+	deliverySpec     query.Spec
+}
+
+func NewHandler(service *application_webhook.Service, validator *validator.Validator, subscriptionSpec, deliverySpec query.Spec) *Handler {
+	return &Handler{
+		service:          service,
+		validator:        validator,
+		subscriptionSpec: subscriptionSpec,
+		deliverySpec:     deliverySpec,
+	}
+}
+
+// RegisterRoutes espera um grupo que já passou pelo TenantMiddleware
+func (h *Handler) RegisterRoutes(router gin.IRouter) {
+	webhooks := router.Group("/webhooks")
+
+	webhooks.POST("/subscriptions", h.CreateSubscription)
+	webhooks.GET("/subscriptions", h.ListSubscriptions)
+	webhooks.GET("/subscriptions/:id", h.GetSubscription)
+	webhooks.PUT("/subscriptions/:id", h.UpdateSubscription)
+	webhooks.DELETE("/subscriptions/:id", h.DeleteSubscription)
+
+	webhooks.GET("/deliveries", h.ListDeliveries)
+	webhooks.GET("/deliveries/:id", h.GetDelivery)
+	webhooks.POST("/deliveries/:id/redeliver", h.Redeliver)
+}
+
+func (h *Handler) CreateSubscription(c *gin.Context) {
+	var request CreateSubscriptionRequest
+	if err := h.validator.BindJSON(c, &request); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	subscription, err := h.service.CreateSubscription(c.Request.Context(), application_webhook.CreateSubscriptionInput{
+		URL:        request.URL,
+		EventTypes: request.EventTypes,
+		Secret:     request.Secret,
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SetETag(c, subscription.GetVersion())
+	response.Created(c, toSubscriptionResponse(subscription, true))
+}
+
+func (h *Handler) ListSubscriptions(c *gin.Context) {
+	filter, err := query.ParseFilter(c.Request.URL.Query(), h.subscriptionSpec)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	page, err := h.service.ListSubscriptions(c.Request.Context(), filter)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	items := make([]SubscriptionResponse, 0, len(page.Items))
+	for _, subscription := range page.Items {
+		items = append(items, toSubscriptionResponse(subscription, false))
+	}
+	response.Paginated(c, items, response.PageMeta(filter, page))
+}
+
+func (h *Handler) GetSubscription(c *gin.Context) {
+	subscription, err := h.service.GetSubscription(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SetETag(c, subscription.GetVersion())
+	response.Success(c, toSubscriptionResponse(subscription, false))
+}
+
+func (h *Handler) UpdateSubscription(c *gin.Context) {
+	expectedVersion, err := response.IfMatchVersion(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	var request UpdateSubscriptionRequest
+	if err := h.validator.BindJSON(c, &request); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	subscription, err := h.service.UpdateSubscription(c.Request.Context(), c.Param("id"), expectedVersion, application_webhook.UpdateSubscriptionInput{
+		URL:          request.URL,
+		EventTypes:   request.EventTypes,
+		Active:       request.Active,
+		RotateSecret: request.RotateSecret,
+		Secret:       request.Secret,
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SetETag(c, subscription.GetVersion())
+	response.Success(c, toSubscriptionResponse(subscription, request.RotateSecret || request.Secret != ""))
+}
+
+func (h *Handler) DeleteSubscription(c *gin.Context) {
+	if err := h.service.DeleteSubscription(c.Request.Context(), c.Param("id")); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, nil)
+}
+
+func (h *Handler) ListDeliveries(c *gin.Context) {
+	filter, err := query.ParseFilter(c.Request.URL.Query(), h.deliverySpec)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	page, err := h.service.ListDeliveries(c.Request.Context(), filter)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	items := make([]DeliveryResponse, 0, len(page.Items))
+	for _, delivery := range page.Items {
+		items = append(items, toDeliveryResponse(delivery, nil))
+	}
+	response.Paginated(c, items, response.PageMeta(filter, page))
+}
+
+func (h *Handler) GetDelivery(c *gin.Context) {
+	delivery, attempts, err := h.service.GetDelivery(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, toDeliveryResponse(delivery, attempts))
+}
+
+func (h *Handler) Redeliver(c *gin.Context) {
+	delivery, err := h.service.Redeliver(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, toDeliveryResponse(delivery, nil))
+}
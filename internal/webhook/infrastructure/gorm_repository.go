@@ -0,0 +1,100 @@
+package infrastructure_webhook
+
+import (
+	"context"
+
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/query"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/repository"
+	domain_webhook "github.com/williamkoller/multi-tenant-nexus-manager/internal/webhook/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	_ domain_webhook.SubscriptionRepository = (*SubscriptionRepository)(nil)
+	_ domain_webhook.DeliveryRepository     = (*DeliveryRepository)(nil)
+)
+
+// Models lista as tabelas do contexto para database.Migrate
+func Models() []interface{} {
+	return []interface{}{
+		&domain_webhook.Subscription{},
+		&domain_webhook.Delivery{},
+		&domain_webhook.DeliveryAttempt{},
+	}
+}
+
+func SubscriptionSpec() query.Spec {
+	return query.DefaultSpec().WithFilterable("url", "active")
+}
+
+func DeliverySpec() query.Spec {
+	return query.DefaultSpec().
+		WithFilterable("subscription_id", "event_id", "event_type", "status").
+		WithSortable("next_attempt_at")
+}
+
+type SubscriptionRepository struct {
+	*repository.GormRepository[*domain_webhook.Subscription]
+}
+
+func NewSubscriptionRepository(db *gorm.DB, spec query.Spec) *SubscriptionRepository {
+	return &SubscriptionRepository{
+		GormRepository: repository.NewGormRepository[*domain_webhook.Subscription](db, spec),
+	}
+}
+
+// FindMatching filtra em memória porque as assinaturas podem usar wildcards
+func (r *SubscriptionRepository) FindMatching(ctx context.Context, eventType string) ([]*domain_webhook.Subscription, error) {
+	var subscriptions []*domain_webhook.Subscription
+	if err := r.DB(ctx).Where("active = ?", true).Find(&subscriptions).Error; err != nil {
+		return nil, repository.TranslateError(err)
+	}
+
+	matching := subscriptions[:0]
+	for _, subscription := range subscriptions {
+		if subscription.Matches(eventType) {
+			matching = append(matching, subscription)
+		}
+	}
+	return matching, nil
+}
+
+type DeliveryRepository struct {
+	*repository.GormRepository[*domain_webhook.Delivery]
+}
+
+func NewDeliveryRepository(db *gorm.DB, spec query.Spec) *DeliveryRepository {
+	return &DeliveryRepository{
+		GormRepository: repository.NewGormRepository[*domain_webhook.Delivery](db, spec),
+	}
+}
+
+func (r *DeliveryRepository) Enqueue(ctx context.Context, deliveries []*domain_webhook.Delivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	for _, delivery := range deliveries {
+		delivery.SetVersion(1)
+	}
+
+	err := r.DB(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "subscription_id"}, {Name: "event_id"}},
+			DoNothing: true,
+		}).
+		Create(&deliveries).Error
+	return repository.TranslateError(err)
+}
+
+func (r *DeliveryRepository) Attempts(ctx context.Context, deliveryID string) ([]domain_webhook.DeliveryAttempt, error) {
+	var attempts []domain_webhook.DeliveryAttempt
+	err := r.DB(ctx).
+		Where("delivery_id = ?", deliveryID).
+		Order("attempt").
+		Find(&attempts).Error
+	if err != nil {
+		return nil, repository.TranslateError(err)
+	}
+	return attempts, nil
+}
@@ -0,0 +1,326 @@
+package infrastructure_webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/database"
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/tenancy"
+	domain_webhook "github.com/williamkoller/multi-tenant-nexus-manager/internal/webhook/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Limite do corpo de resposta lido (e descartado) para reaproveitar a conexão
+const maxResponseBody = 2048
+
+type WorkerConfig struct {
+	BatchSize    int
+	PollInterval time.Duration
+	MaxAttempts  int
+	BaseBackoff  time.Duration
+	MaxBackoff   time.Duration
+	Timeout      time.Duration
+	// ClaimTimeout é o prazo para o lote reservado ser enviado; depois dele
+	// as entregas não registradas voltam para a fila
+	ClaimTimeout time.Duration
+}
+
+func DefaultWorkerConfig() WorkerConfig {
+	return WorkerConfig{
+		BatchSize:    50,
+		PollInterval: 2 * time.Second,
+		MaxAttempts:  8,
+		BaseBackoff:  30 * time.Second,
+		MaxBackoff:   6 * time.Hour,
+		Timeout:      10 * time.Second,
+	}
+}
+
+// Worker envia as entregas pendentes de todos os tenants. Assim como o relay do
+// outbox, várias instâncias podem rodar juntas graças ao FOR UPDATE SKIP LOCKED.
+// As entregas dos tenants isolados ficam no schema de cada um e são lidas em
+// seguida às do schema public.
+type Worker struct {
+	db        *gorm.DB
+	txManager *database.GormTxManager
+	tenants   tenancy.IsolatedLister
+	client    *http.Client
+	config    WorkerConfig
+}
+
+func NewWorker(db *gorm.DB, tenants tenancy.IsolatedLister, client *http.Client, config WorkerConfig) *Worker {
+	defaults := DefaultWorkerConfig()
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaults.BatchSize
+	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = defaults.PollInterval
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = defaults.MaxAttempts
+	}
+	if config.BaseBackoff <= 0 {
+		config.BaseBackoff = defaults.BaseBackoff
+	}
+	if config.MaxBackoff <= 0 {
+		config.MaxBackoff = defaults.MaxBackoff
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = defaults.Timeout
+	}
+	if config.ClaimTimeout <= 0 {
+		config.ClaimTimeout = time.Duration(config.BatchSize+1) * config.Timeout
+	}
+	if client == nil {
+		client = newClient(config.Timeout)
+	}
+
+	return &Worker{
+		db:        db,
+		txManager: database.NewTxManager(db),
+		tenants:   tenants,
+		client:    client,
+		config:    config,
+	}
+}
+
+func (w *Worker) Run(ctx context.Context) {
+	for {
+		processed, err := w.ProcessBatch(ctx)
+		if err != nil {
+			log.Printf("webhook worker: %v", err)
+		}
+
+		if err == nil && processed >= w.config.BatchSize {
+			if ctx.Err() != nil {
+				return
+			}
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(w.config.PollInterval):
+		}
+	}
+}
+
+func (w *Worker) ProcessBatch(ctx context.Context) (int, error) {
+	processed := 0
+	err := database.ForEachPartition(ctx, w.tenants, func(partitionCtx context.Context) error {
+		count, err := w.processPartition(ctx, partitionCtx)
+		processed += count
+		return err
+	})
+	return processed, err
+}
+
+// processPartition envia um lote de partitionCtx; as chamadas HTTP usam ctx,
+// que não carrega o escopo de sistema
+func (w *Worker) processPartition(ctx, partitionCtx context.Context) (int, error) {
+	deliveries, subscriptions, err := w.claim(partitionCtx)
+	if err != nil {
+		return 0, err
+	}
+
+	processed := 0
+	for _, delivery := range deliveries {
+		// As entregas restantes voltam para a fila quando a reserva expirar
+		if ctx.Err() != nil {
+			break
+		}
+		attempt := w.deliver(ctx, delivery, subscriptions[delivery.SubscriptionID])
+		if err := w.record(partitionCtx, delivery, attempt); err != nil {
+			return processed, err
+		}
+		processed++
+	}
+	return processed, nil
+}
+
+// claim reserva o lote adiando next_attempt_at e confirma a transação antes
+// das chamadas HTTP, para que nenhum lock fique preso esperando um endpoint lento
+func (w *Worker) claim(ctx context.Context) ([]*domain_webhook.Delivery, map[string]*domain_webhook.Subscription, error) {
+	var deliveries []*domain_webhook.Delivery
+	var subscriptions map[string]*domain_webhook.Subscription
+
+	err := w.txManager.WithTx(ctx, func(txCtx context.Context) error {
+		tx := database.GetTxFromContext(txCtx, w.db)
+
+		now := time.Now().UTC()
+		err := tx.
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", domain_webhook.DeliveryPending, now).
+			Order("next_attempt_at").
+			Limit(w.config.BatchSize).
+			Find(&deliveries).Error
+		if err != nil || len(deliveries) == 0 {
+			return err
+		}
+
+		subscriptions, err = w.subscriptions(tx, deliveries)
+		if err != nil {
+			return err
+		}
+
+		for _, delivery := range deliveries {
+			delivery.NextAttemptAt = now.Add(w.config.ClaimTimeout)
+			delivery.SetVersion(delivery.GetVersion() + 1)
+			if err := tx.Save(delivery).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return deliveries, subscriptions, nil
+}
+
+// record grava o resultado apenas se a reserva ainda for deste worker
+func (w *Worker) record(ctx context.Context, delivery *domain_webhook.Delivery, attempt domain_webhook.DeliveryAttempt) error {
+	return w.txManager.WithTx(ctx, func(txCtx context.Context) error {
+		tx := database.GetTxFromContext(txCtx, w.db)
+
+		claimed := delivery.GetVersion()
+		delivery.SetVersion(claimed + 1)
+		result := tx.Model(delivery).
+			Where(clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: "version"}, Value: claimed}).
+			Select("*").
+			Updates(delivery)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			log.Printf("webhook worker: claim on delivery %s expired before its result was recorded", delivery.GetID())
+			return nil
+		}
+		return tx.Create(&attempt).Error
+	})
+}
+
+func (w *Worker) subscriptions(tx *gorm.DB, deliveries []*domain_webhook.Delivery) (map[string]*domain_webhook.Subscription, error) {
+	ids := make([]string, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		ids = append(ids, delivery.SubscriptionID)
+	}
+
+	var subscriptions []*domain_webhook.Subscription
+	if err := tx.Where("id IN ?", ids).Find(&subscriptions).Error; err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]*domain_webhook.Subscription, len(subscriptions))
+	for _, subscription := range subscriptions {
+		byID[subscription.GetID()] = subscription
+	}
+	return byID, nil
+}
+
+func (w *Worker) deliver(ctx context.Context, delivery *domain_webhook.Delivery, subscription *domain_webhook.Subscription) domain_webhook.DeliveryAttempt {
+	// Assinatura removida ou desativada: não há para onde entregar
+	if subscription == nil || !subscription.Active {
+		now := time.Now().UTC()
+		delivery.RecordFailure(0, "subscription is missing or inactive", now, 0, 0)
+		return domain_webhook.NewDeliveryAttempt(delivery, 0, delivery.LastError, 0)
+	}
+
+	start := time.Now()
+	statusCode, err := w.send(ctx, delivery, subscription)
+	duration := time.Since(start)
+	now := time.Now().UTC()
+
+	if err == nil {
+		delivery.RecordSuccess(statusCode, now)
+		return domain_webhook.NewDeliveryAttempt(delivery, statusCode, "", duration)
+	}
+
+	delivery.RecordFailure(statusCode, err.Error(), now, w.config.MaxAttempts, w.backoff(delivery.Attempts+1))
+	if delivery.Status == domain_webhook.DeliveryDead {
+		log.Printf("webhook worker: delivery %s (%s) moved to dead-letter after %d attempts: %v",
+			delivery.GetID(), delivery.EventType, delivery.Attempts, err)
+	}
+	return domain_webhook.NewDeliveryAttempt(delivery, statusCode, err.Error(), duration)
+}
+
+func (w *Worker) send(ctx context.Context, delivery *domain_webhook.Delivery, subscription *domain_webhook.Subscription) (int, error) {
+	body := []byte(delivery.Payload)
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := time.Now()
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "nexus-manager-webhooks/1.0")
+	request.Header.Set(domain_webhook.HeaderDeliveryID, delivery.GetID())
+	request.Header.Set(domain_webhook.HeaderEventType, delivery.EventType)
+	request.Header.Set(domain_webhook.HeaderTimestamp, fmt.Sprintf("%d", timestamp.Unix()))
+	request.Header.Set(domain_webhook.HeaderSignature, domain_webhook.Sign(subscription.Secret, timestamp, body))
+
+	response, err := w.client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(response.Body, maxResponseBody))
+
+	// Redirecionamentos não são seguidos e contam como falha
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return response.StatusCode, fmt.Errorf("endpoint responded with status %d", response.StatusCode)
+	}
+	return response.StatusCode, nil
+}
+
+// newClient só conecta em endereços públicos, conferidos depois da resolução
+// de DNS, e não segue redirecionamentos nem proxies do ambiente
+func newClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout, Control: publicAddressOnly}
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: timeout,
+			MaxIdleConns:        100,
+			IdleConnTimeout:     90 * time.Second,
+			ForceAttemptHTTP2:   true,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+func publicAddressOnly(network, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	if !domain_webhook.IsPublicAddress(addrPort.Addr()) {
+		return fmt.Errorf("webhook address %s is not public", addrPort.Addr())
+	}
+	return nil
+}
+
+func (w *Worker) backoff(attempts int) time.Duration {
+	delay := w.config.BaseBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= w.config.MaxBackoff {
+			return w.config.MaxBackoff
+		}
+	}
+	return delay
+}
@@ -0,0 +1,134 @@
+package infrastructure_webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/williamkoller/multi-tenant-nexus-manager/internal/core/domain"
+	domain_webhook "github.com/williamkoller/multi-tenant-nexus-manager/internal/webhook/domain"
+)
+
+func TestWorkerSendsSignedDelivery(t *testing.T) {
+	subscription := &domain_webhook.Subscription{Secret: "whsec_test", Active: true}
+	delivery := newTestDelivery(t, subscription)
+
+	received := make(chan *http.Request, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		err := domain_webhook.Verify(subscription.Secret, r.Header.Get(domain_webhook.HeaderTimestamp),
+			r.Header.Get(domain_webhook.HeaderSignature), body, domain_webhook.DefaultTolerance, time.Now())
+		if err != nil {
+			t.Errorf("signature rejected: %v", err)
+		}
+		if string(body) != delivery.Payload {
+			t.Errorf("body = %s, want %s", body, delivery.Payload)
+		}
+		received <- r
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	subscription.URL = server.URL
+
+	worker := NewWorker(nil, nil, server.Client(), WorkerConfig{})
+	worker.deliver(context.Background(), delivery, subscription)
+
+	request := <-received
+	if got := request.Header.Get(domain_webhook.HeaderDeliveryID); got != delivery.GetID() {
+		t.Errorf("%s = %q, want %q", domain_webhook.HeaderDeliveryID, got, delivery.GetID())
+	}
+	if got := request.Header.Get(domain_webhook.HeaderEventType); got != "user.created" {
+		t.Errorf("%s = %q, want user.created", domain_webhook.HeaderEventType, got)
+	}
+	if request.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("Content-Type = %q", request.Header.Get("Content-Type"))
+	}
+	if delivery.Status != domain_webhook.DeliveryDelivered || delivery.LastStatusCode != http.StatusNoContent {
+		t.Fatalf("delivery = %s (%d), want delivered", delivery.Status, delivery.LastStatusCode)
+	}
+}
+
+func TestWorkerBackoffSchedule(t *testing.T) {
+	worker := NewWorker(nil, nil, http.DefaultClient, WorkerConfig{
+		BaseBackoff: 30 * time.Second,
+		MaxBackoff:  5 * time.Minute,
+	})
+
+	want := []time.Duration{30 * time.Second, time.Minute, 2 * time.Minute, 4 * time.Minute, 5 * time.Minute, 5 * time.Minute}
+	for i, expected := range want {
+		if got := worker.backoff(i + 1); got != expected {
+			t.Errorf("backoff(%d) = %s, want %s", i+1, got, expected)
+		}
+	}
+}
+
+func TestWorkerRetriesFailedDeliveryUntilDeadLetter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	subscription := &domain_webhook.Subscription{URL: server.URL, Secret: "whsec_test", Active: true}
+	delivery := newTestDelivery(t, subscription)
+	worker := NewWorker(nil, nil, server.Client(), WorkerConfig{
+		MaxAttempts: 3,
+		BaseBackoff: time.Minute,
+		MaxBackoff:  time.Hour,
+	})
+
+	for attempt, backoff := range []time.Duration{time.Minute, 2 * time.Minute} {
+		before := time.Now().UTC()
+		result := worker.deliver(context.Background(), delivery, subscription)
+
+		if delivery.Status != domain_webhook.DeliveryPending || delivery.Attempts != attempt+1 {
+			t.Fatalf("after attempt %d: status %s, attempts %d", attempt+1, delivery.Status, delivery.Attempts)
+		}
+		if result.StatusCode != http.StatusServiceUnavailable {
+			t.Fatalf("attempt status = %d, want 503", result.StatusCode)
+		}
+		if delay := delivery.NextAttemptAt.Sub(before); delay < backoff || delay > backoff+time.Second {
+			t.Fatalf("attempt %d rescheduled in %s, want %s", attempt+1, delay, backoff)
+		}
+	}
+
+	worker.deliver(context.Background(), delivery, subscription)
+	if delivery.Status != domain_webhook.DeliveryDead {
+		t.Fatalf("status after %d attempts = %s, want dead", delivery.Attempts, delivery.Status)
+	}
+}
+
+func TestWorkerClientRejectsPrivateAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request reached a loopback endpoint")
+	}))
+	defer server.Close()
+
+	client := newClient(time.Second)
+	_, err := client.Get(server.URL)
+	if err == nil || !strings.Contains(err.Error(), "is not public") {
+		t.Fatalf("Get(%s) returned %v, want a non-public address error", server.URL, err)
+	}
+
+	for _, address := range []string{"10.0.0.1:443", "169.254.169.254:80", "192.168.1.10:8080", "[::1]:443"} {
+		if err := publicAddressOnly("tcp", address, nil); err == nil {
+			t.Errorf("publicAddressOnly(%s) accepted a private address", address)
+		}
+	}
+	if err := publicAddressOnly("tcp", "93.184.216.34:443", nil); err != nil {
+		t.Errorf("publicAddressOnly rejected a public address: %v", err)
+	}
+}
+
+func newTestDelivery(t *testing.T, subscription *domain_webhook.Subscription) *domain_webhook.Delivery {
+	t.Helper()
+	event := domain.NewBaseDomainEvent("user.created", "u1", map[string]string{"email": "ana@example.com"})
+	delivery, err := domain_webhook.NewDelivery(subscription, event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return delivery
+}